@@ -3,6 +3,7 @@ package cmd
 import (
 	"github.com/malleatus/tamjaweb/cmd/bookmarks"
 	internalBookmarks "github.com/malleatus/tamjaweb/internal/bookmarks"
+	"github.com/malleatus/tamjaweb/internal/output"
 	"github.com/spf13/cobra"
 )
 
@@ -14,10 +15,22 @@ func init() {
 		Short: "Manage browser bookmarks",
 	}
 
-	cmd.PersistentFlags().StringVar(&opts.Profile, "profile", "Default", "Browser profile to use")
+	cmd.PersistentFlags().StringVar(&opts.Profile, "profile", "Default", `Browser profile to use, either its name or directory ("all" for every profile)`)
+
+	cmd.PersistentFlags().BoolVar(&opts.Strict, "strict", false, "Exit non-zero when a browser's bookmarks can't be read")
+
+	cmd.PersistentFlags().BoolVar(&opts.Long, "long", false, "Show every bookmark field, including ids, modification date and metadata")
+	cmd.PersistentFlags().DurationVar(&opts.UsedWithin, "used-within", 0, "Only show bookmarks last used within this duration (e.g. 720h)")
+	output.AddSortFlags(cmd.PersistentFlags(), &opts.Sort, internalBookmarks.Sorts)
 
 	cmd.AddCommand(bookmarks.NewSearchCommand(opts))
 	cmd.AddCommand(bookmarks.NewListCommand(opts))
+	cmd.AddCommand(bookmarks.NewAddCommand(opts))
+	cmd.AddCommand(bookmarks.NewRemoveCommand(opts))
+	cmd.AddCommand(bookmarks.NewMoveCommand(opts))
+	cmd.AddCommand(bookmarks.NewRenameCommand(opts))
+	cmd.AddCommand(bookmarks.NewExportCommand(opts))
+	cmd.AddCommand(bookmarks.NewImportCommand(opts))
 
 	rootCmd.AddCommand(cmd)
 }
@@ -0,0 +1,45 @@
+package bookmarks
+
+import (
+	"github.com/spf13/cobra"
+
+	internalBookmarks "github.com/malleatus/tamjaweb/internal/bookmarks"
+)
+
+func NewAddCommand(opts *internalBookmarks.Options) *cobra.Command {
+	var flags editFlags
+	var folder, title string
+
+	cmd := &cobra.Command{
+		Use:          "add <url>",
+		Short:        "Add a bookmark",
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			editor, err := flags.editor()
+			if err != nil {
+				return err
+			}
+
+			url := args[0]
+			if title == "" {
+				title = url
+			}
+
+			bookmark, err := editor.AddBookmark(cmd.Context(), opts.Profile, folder, title, url, flags.options())
+			if err != nil {
+				return editError(err)
+			}
+
+			printEdit(cmd, "Added", bookmark)
+			return nil
+		},
+	}
+	addEditFlags(cmd, &flags)
+
+	cmd.Flags().StringVar(&folder, "folder", "Bookmark Bar", `Folder to add the bookmark to, e.g. "Bookmark Bar/Work", missing folders are created`)
+
+	cmd.Flags().StringVar(&title, "title", "", "Title of the bookmark, defaults to its URL")
+
+	return cmd
+}
@@ -0,0 +1,60 @@
+package bookmarks
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/malleatus/tamjaweb/internal/browser"
+)
+
+// editFlags are the flags shared by the commands that change bookmarks
+type editFlags struct {
+	browser string
+	force   bool
+}
+
+func addEditFlags(cmd *cobra.Command, flags *editFlags) {
+	cmd.Flags().StringVar(&flags.browser, "browser", "Chrome", "Browser whose bookmarks to change, any Chromium-based browser")
+
+	cmd.Flags().BoolVar(&flags.force, "force", false, "Write even though the browser is running (it may overwrite the change), or its lock was left behind by a crash")
+}
+
+// editor returns the browser selected with --browser
+func (f *editFlags) editor() (browser.BookmarkEditor, error) {
+	found, ok := browser.FindBrowser(f.browser)
+	if !ok {
+		return nil, fmt.Errorf("unknown browser %q", f.browser)
+	}
+
+	editor, ok := found.(browser.BookmarkEditor)
+	if !ok {
+		return nil, fmt.Errorf("%s bookmarks can't be changed, only those of Chromium-based browsers", found.Name())
+	}
+
+	return editor, nil
+}
+
+func (f *editFlags) options() browser.EditOptions {
+	return browser.EditOptions{Force: f.force}
+}
+
+// editError explains how to get past a running browser
+func editError(err error) error {
+	if errors.Is(err, browser.ErrProfileInUse) {
+		return fmt.Errorf("%w, close the browser first or pass --force", err)
+	}
+	return err
+}
+
+// printEdit reports a change to bookmark on stdout
+func printEdit(cmd *cobra.Command, action string, bookmark browser.Bookmark) {
+	what := bookmark.Title
+	if bookmark.URL != "" {
+		what = fmt.Sprintf("%s (%s)", bookmark.Title, bookmark.URL)
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "%s %s in %s [id %s]\n", action, what, filepath.ToSlash(bookmark.FolderPath), bookmark.ID)
+}
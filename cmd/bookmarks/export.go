@@ -0,0 +1,55 @@
+package bookmarks
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	internalBookmarks "github.com/malleatus/tamjaweb/internal/bookmarks"
+	"github.com/malleatus/tamjaweb/internal/browser"
+)
+
+func NewExportCommand(opts *internalBookmarks.Options) *cobra.Command {
+	var format, browserName string
+
+	cmd := &cobra.Command{
+		Use:          "export",
+		Short:        "Export bookmarks to stdout, e.g. to import them into another browser",
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if format != internalBookmarks.FormatNetscape {
+				return fmt.Errorf("unsupported format %q, expected one of %s", format, strings.Join(internalBookmarks.ExportFormats, ", "))
+			}
+			if err := opts.Sort.Validate(internalBookmarks.Sorts); err != nil {
+				return err
+			}
+
+			allBookmarks, loadErr := loadBookmarks(cmd, opts)
+
+			entries := internalBookmarks.Entries(applyUsageOptions(allBookmarks, opts))
+			if err := internalBookmarks.SortEntries(entries, opts.Sort); err != nil {
+				return err
+			}
+
+			var bookmarks []browser.Bookmark
+			for _, entry := range entries {
+				if browserName == "" || strings.EqualFold(browserName, entry.Browser) {
+					bookmarks = append(bookmarks, entry.Bookmark)
+				}
+			}
+
+			if err := internalBookmarks.ExportNetscape(cmd.OutOrStdout(), bookmarks); err != nil {
+				return err
+			}
+
+			return loadErr
+		},
+	}
+
+	cmd.Flags().StringVar(&format, "format", internalBookmarks.FormatNetscape, "Format to export as: "+strings.Join(internalBookmarks.ExportFormats, ", "))
+
+	cmd.Flags().StringVar(&browserName, "browser", "", "Only export the bookmarks of this browser")
+
+	return cmd
+}
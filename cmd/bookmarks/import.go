@@ -0,0 +1,93 @@
+package bookmarks
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	internalBookmarks "github.com/malleatus/tamjaweb/internal/bookmarks"
+	"github.com/malleatus/tamjaweb/internal/browser"
+	"github.com/malleatus/tamjaweb/internal/output"
+)
+
+func NewImportCommand(opts *internalBookmarks.Options) *cobra.Command {
+	var flags editFlags
+	var format, folder string
+	var dryRun bool
+
+	cmd := &cobra.Command{
+		Use:          "import <file|->",
+		Short:        "Import bookmarks exported by a browser or bookmarking service",
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if format != internalBookmarks.FormatNetscape {
+				return fmt.Errorf("unsupported format %q, expected one of %s", format, strings.Join(internalBookmarks.ExportFormats, ", "))
+			}
+
+			if err := opts.Output.Validate(); err != nil {
+				return err
+			}
+
+			editor, err := flags.editor()
+			if err != nil {
+				return err
+			}
+
+			var input io.Reader = cmd.InOrStdin()
+			if args[0] != "-" {
+				file, err := os.Open(args[0])
+				if err != nil {
+					return err
+				}
+				defer func() {
+					_ = file.Close()
+				}()
+				input = file
+			}
+
+			bookmarks, err := internalBookmarks.ImportNetscape(input)
+			if err != nil {
+				return fmt.Errorf("%s: %w", args[0], err)
+			}
+
+			if !dryRun {
+				bookmarks, err = editor.AddBookmarks(cmd.Context(), opts.Profile, folder, bookmarks, flags.options())
+				if err != nil {
+					return editError(err)
+				}
+			}
+
+			if err := printBookmarks(cmd, internalBookmarks.Entries(map[string][]browser.Bookmark{editor.Name(): bookmarks}), opts); err != nil {
+				return err
+			}
+
+			// keep machine readable output parseable
+			if opts.Output.Format != output.Table {
+				return nil
+			}
+
+			if dryRun {
+				fmt.Fprintf(cmd.OutOrStdout(), "\nWould import %d bookmark(s) into %s\n", len(bookmarks), folder)
+			} else {
+				fmt.Fprintf(cmd.OutOrStdout(), "\nImported %d bookmark(s) into %s\n", len(bookmarks), folder)
+			}
+
+			return nil
+		},
+	}
+	addEditFlags(cmd, &flags)
+
+	cmd.Flags().StringVar(&format, "format", internalBookmarks.FormatNetscape, "Format of the file: "+strings.Join(internalBookmarks.ExportFormats, ", "))
+
+	cmd.Flags().StringVar(&folder, "folder", "Other Bookmarks/Imported", "Folder to import into, the file's folders are recreated inside it")
+
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show the bookmarks that would be imported without changing anything")
+
+	output.AddFlags(cmd.Flags(), &opts.Output)
+
+	return cmd
+}
@@ -1,34 +1,41 @@
 package bookmarks
 
 import (
-	"fmt"
-
-	"github.com/charmbracelet/log"
 	"github.com/spf13/cobra"
 
 	internalBookmarks "github.com/malleatus/tamjaweb/internal/bookmarks"
-	"github.com/malleatus/tamjaweb/internal/browser"
+	"github.com/malleatus/tamjaweb/internal/output"
 )
 
 func NewListCommand(opts *internalBookmarks.Options) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "list",
-		Short: "List all bookmarks",
-		Run: func(cmd *cobra.Command, args []string) {
-			allBookmarks, err := browser.GetAllBookmarks(opts.Profile)
-			if err != nil {
-				log.Error("Failed to get bookmarks", "error", err)
-				return
+		Use:          "list",
+		Short:        "List all bookmarks",
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := opts.Output.Validate(); err != nil {
+				return err
+			}
+			if err := opts.Sort.Validate(internalBookmarks.Sorts); err != nil {
+				return err
 			}
 
-			formattedOutput, err := internalBookmarks.PrintBookmarks(allBookmarks)
-			if err != nil {
-				log.Error("Failed to format bookmarks", "error", err)
-				return
+			allBookmarks, loadErr := loadBookmarks(cmd, opts)
+
+			entries := internalBookmarks.Entries(applyUsageOptions(allBookmarks, opts))
+			if err := internalBookmarks.SortEntries(entries, opts.Sort); err != nil {
+				return err
 			}
-			fmt.Print(formattedOutput)
+
+			if err := printBookmarks(cmd, entries, opts); err != nil {
+				return err
+			}
+
+			return loadErr
 		},
 	}
 
+	output.AddFlags(cmd.Flags(), &opts.Output)
+
 	return cmd
 }
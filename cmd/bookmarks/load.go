@@ -0,0 +1,40 @@
+package bookmarks
+
+import (
+	"time"
+
+	"github.com/spf13/cobra"
+
+	internalBookmarks "github.com/malleatus/tamjaweb/internal/bookmarks"
+	"github.com/malleatus/tamjaweb/internal/browser"
+	"github.com/malleatus/tamjaweb/internal/output"
+)
+
+// loadBookmarks loads the bookmarks of every browser, reporting the browsers
+// that failed to stderr, see browser.ErrorReporter
+func loadBookmarks(cmd *cobra.Command, opts *internalBookmarks.Options) (map[string][]browser.Bookmark, error) {
+	allBookmarks, err := browser.GetAllBookmarks(cmd.Context(), opts.Profile)
+
+	reporter := browser.ErrorReporter{W: cmd.ErrOrStderr(), Strict: opts.Strict}
+	if err := reporter.Report(err, "bookmarks"); err != nil {
+		return allBookmarks, err
+	}
+
+	return allBookmarks, reporter.Err()
+}
+
+// applyUsageOptions applies --used-within to bookmarks
+func applyUsageOptions(bookmarks map[string][]browser.Bookmark, opts *internalBookmarks.Options) map[string][]browser.Bookmark {
+	if opts.UsedWithin > 0 {
+		bookmarks = internalBookmarks.FilterBookmarksUsedSince(bookmarks, time.Now().Add(-opts.UsedWithin))
+	}
+
+	return bookmarks
+}
+
+// printBookmarks writes entries in the format selected with --output
+func printBookmarks(cmd *cobra.Command, entries []internalBookmarks.Entry, opts *internalBookmarks.Options) error {
+	return output.Render(cmd.OutOrStdout(), opts.Output, internalBookmarks.Records(entries), func() (string, error) {
+		return internalBookmarks.PrintBookmarks(entries, opts.Long)
+	})
+}
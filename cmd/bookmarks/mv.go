@@ -0,0 +1,39 @@
+package bookmarks
+
+import (
+	"github.com/spf13/cobra"
+
+	internalBookmarks "github.com/malleatus/tamjaweb/internal/bookmarks"
+)
+
+func NewMoveCommand(opts *internalBookmarks.Options) *cobra.Command {
+	var flags editFlags
+	var folder string
+
+	cmd := &cobra.Command{
+		Use:          "mv <id|guid|url> --folder <folder>",
+		Short:        "Move a bookmark, or a folder, to another folder",
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			editor, err := flags.editor()
+			if err != nil {
+				return err
+			}
+
+			bookmark, err := editor.MoveBookmark(cmd.Context(), opts.Profile, args[0], folder, flags.options())
+			if err != nil {
+				return editError(err)
+			}
+
+			printEdit(cmd, "Moved", bookmark)
+			return nil
+		},
+	}
+	addEditFlags(cmd, &flags)
+
+	cmd.Flags().StringVar(&folder, "folder", "", `Folder to move to, e.g. "Bookmark Bar/Work", missing folders are created`)
+	_ = cmd.MarkFlagRequired("folder")
+
+	return cmd
+}
@@ -0,0 +1,35 @@
+package bookmarks
+
+import (
+	"github.com/spf13/cobra"
+
+	internalBookmarks "github.com/malleatus/tamjaweb/internal/bookmarks"
+)
+
+func NewRenameCommand(opts *internalBookmarks.Options) *cobra.Command {
+	var flags editFlags
+
+	cmd := &cobra.Command{
+		Use:          "rename <id|guid|url> <title>",
+		Short:        "Change the title of a bookmark, or the name of a folder",
+		Args:         cobra.ExactArgs(2),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			editor, err := flags.editor()
+			if err != nil {
+				return err
+			}
+
+			bookmark, err := editor.RenameBookmark(cmd.Context(), opts.Profile, args[0], args[1], flags.options())
+			if err != nil {
+				return editError(err)
+			}
+
+			printEdit(cmd, "Renamed", bookmark)
+			return nil
+		},
+	}
+	addEditFlags(cmd, &flags)
+
+	return cmd
+}
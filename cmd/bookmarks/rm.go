@@ -0,0 +1,35 @@
+package bookmarks
+
+import (
+	"github.com/spf13/cobra"
+
+	internalBookmarks "github.com/malleatus/tamjaweb/internal/bookmarks"
+)
+
+func NewRemoveCommand(opts *internalBookmarks.Options) *cobra.Command {
+	var flags editFlags
+
+	cmd := &cobra.Command{
+		Use:          "rm <id|guid|url>",
+		Short:        "Remove a bookmark, or a folder and everything in it",
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			editor, err := flags.editor()
+			if err != nil {
+				return err
+			}
+
+			bookmark, err := editor.RemoveBookmark(cmd.Context(), opts.Profile, args[0], flags.options())
+			if err != nil {
+				return editError(err)
+			}
+
+			printEdit(cmd, "Removed", bookmark)
+			return nil
+		},
+	}
+	addEditFlags(cmd, &flags)
+
+	return cmd
+}
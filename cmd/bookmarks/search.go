@@ -1,26 +1,57 @@
 package bookmarks
 
 import (
-	"fmt"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/log"
 	"github.com/spf13/cobra"
 
 	internalBookmarks "github.com/malleatus/tamjaweb/internal/bookmarks"
-	"github.com/malleatus/tamjaweb/internal/browser"
+	"github.com/malleatus/tamjaweb/internal/item"
+	"github.com/malleatus/tamjaweb/internal/output"
+	"github.com/malleatus/tamjaweb/internal/query"
 )
 
 func NewSearchCommand(opts *internalBookmarks.Options) *cobra.Command {
 	var searchTerm string
 
 	cmd := &cobra.Command{
-		Use:   "search",
+		Use:   "search [query]",
 		Short: "Search for bookmarks",
-		Run: func(cmd *cobra.Command, args []string) {
+		Long: `Search for bookmarks
+
+The query is fuzzy matched against the title and URL of each bookmark (see
+--in, --exact and --case-sensitive), and can filter on fields with field:value
+(quoted when the value has spaces):
+
+  title:, url:    the title or URL contains the value
+  host:           the URL's host is the value or one of its subdomains
+  folder:         the folder path contains the value
+  browser:, profile:
+  added:, used:   a date (2024, 2024-06, 2024-06-01) or a duration before now
+                  (30d, 2w), optionally compared with >, >=, < or <=
+  before:, after: shorthands for added:< and added:>
+
+A leading - excludes matches of a filter or word, and quoted text is matched as
+an exact phrase:
+
+  tamjaweb bookmarks search github folder:Work added:2024 -host:gist.github.com`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := opts.Output.Validate(); err != nil {
+				return err
+			}
+			if err := opts.Sort.Validate(internalBookmarks.Sorts); err != nil {
+				return err
+			}
+			if err := opts.Search.Validate(internalBookmarks.SearchFields); err != nil {
+				return err
+			}
+
 			if searchTerm == "" && len(args) == 0 {
 				log.Error("Search term is required")
-				return
+				return nil
 			}
 
 			// Use args as search term if not provided via flag
@@ -28,22 +59,31 @@ func NewSearchCommand(opts *internalBookmarks.Options) *cobra.Command {
 				searchTerm = strings.Join(args, " ")
 			}
 
-			allBookmarks, err := browser.GetAllBookmarks(opts.Profile)
+			q, err := query.Parse(searchTerm, internalBookmarks.QueryFields, time.Now())
 			if err != nil {
-				log.Error("Failed to get bookmarks", "error", err)
-				return
+				return err
 			}
 
-			filteredBookmarks := internalBookmarks.FilterBookmarksByTerm(allBookmarks, searchTerm)
-			formattedOutput, err := internalBookmarks.PrintBookmarks(filteredBookmarks)
-			if err != nil {
-				log.Error("Failed to format bookmarks", "error", err)
-				return
+			allBookmarks, loadErr := loadBookmarks(cmd, opts)
+
+			entries := internalBookmarks.Entries(applyUsageOptions(allBookmarks, opts))
+			entries = internalBookmarks.FilterBookmarksByTerm(q.Filter(entries), q.Text, opts.Search)
+			if err := internalBookmarks.SortEntries(entries, opts.Sort); err != nil {
+				return err
+			}
+
+			if err := printBookmarks(cmd, entries, opts); err != nil {
+				return err
 			}
-			fmt.Print(formattedOutput)
+
+			return loadErr
 		},
 	}
 	cmd.Flags().StringVar(&searchTerm, "term", "", "Term to search for in bookmarks")
 
+	item.AddSearchFlags(cmd.Flags(), &opts.Search, internalBookmarks.SearchFields, internalBookmarks.MatchFields)
+
+	output.AddFlags(cmd.Flags(), &opts.Output)
+
 	return cmd
 }
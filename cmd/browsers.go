@@ -0,0 +1,17 @@
+package cmd
+
+import (
+	"github.com/malleatus/tamjaweb/cmd/browsers"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	cmd := &cobra.Command{
+		Use:   "browsers",
+		Short: "Inspect installed browsers",
+	}
+
+	cmd.AddCommand(browsers.NewProfilesCommand())
+
+	rootCmd.AddCommand(cmd)
+}
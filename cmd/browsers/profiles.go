@@ -0,0 +1,41 @@
+package browsers
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/malleatus/tamjaweb/internal/browser"
+)
+
+func NewProfilesCommand() *cobra.Command {
+	var strict bool
+
+	cmd := &cobra.Command{
+		Use:          "profiles",
+		Short:        "List the profiles found for each browser",
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			allProfiles, err := browser.GetAllProfiles()
+
+			reporter := browser.ErrorReporter{W: cmd.ErrOrStderr(), Strict: strict}
+			if err := reporter.Report(err, "profiles"); err != nil {
+				return err
+			}
+
+			formattedOutput, err := browser.PrintProfiles(allProfiles)
+			if err != nil {
+				return fmt.Errorf("failed to format profiles: %w", err)
+			}
+			if _, err := fmt.Fprint(cmd.OutOrStdout(), formattedOutput); err != nil {
+				return err
+			}
+
+			return reporter.Err()
+		},
+	}
+
+	cmd.Flags().BoolVar(&strict, "strict", false, "Exit non-zero when a browser's profiles can't be read")
+
+	return cmd
+}
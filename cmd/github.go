@@ -16,6 +16,10 @@ func init() {
 	}
 
 	cmd.PersistentFlags().StringVar(&opts.User, "user", "", "GitHub user to use")
+
+	github.AddFetchFlags(cmd.PersistentFlags(), &opts.FetchOptions)
+	cmd.MarkFlagsMutuallyExclusive("refresh", "offline")
+
 	err := cmd.MarkPersistentFlagRequired("user")
 	// TODO handle the error properly, log.Error and exit non-zero this should not happen in normal circumstances
 	if err != nil {
@@ -1,22 +1,56 @@
 package github
 
 import (
-	"fmt"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/log"
 	"github.com/spf13/cobra"
 
 	github "github.com/malleatus/tamjaweb/internal/github"
+	"github.com/malleatus/tamjaweb/internal/item"
+	"github.com/malleatus/tamjaweb/internal/output"
+	"github.com/malleatus/tamjaweb/internal/query"
 )
 
 func NewStarsSearchCommand(opts *github.Options) *cobra.Command {
 	var searchTerm string
 
 	cmd := &cobra.Command{
-		Use:   "search",
+		Use:   "search [query]",
 		Short: "Search for stars",
+		Long: `Search for stars
+
+The query is fuzzy matched against the name and description of each starred
+repository (see --in, --exact and --case-sensitive), and can filter on fields
+with field:value (quoted when the value has spaces):
+
+  repo:, description:, url:  the field contains the value
+  lang:                      the repository's main language
+  user:                      the stargazer
+  starred:                   a date (2024, 2024-06, 2024-06-01) or a duration
+                             before now (30d, 2w), optionally compared with
+                             >, >=, < or <=
+  before:, after:            shorthands for starred:< and starred:>
+
+A leading - excludes matches of a filter or word, and quoted text is matched as
+an exact phrase:
+
+  tamjaweb github stars search --user octocat cli lang:go starred:>2023 -archived`,
 		Run: func(cmd *cobra.Command, args []string) {
+			if err := opts.Output.Validate(); err != nil {
+				log.Error("Invalid output options", "error", err)
+				return
+			}
+			if err := opts.Sort.Validate(github.StarSorts); err != nil {
+				log.Error("Invalid sort options", "error", err)
+				return
+			}
+			if err := opts.Search.Validate(github.SearchFields); err != nil {
+				log.Error("Invalid search options", "error", err)
+				return
+			}
+
 			if searchTerm == "" && len(args) == 0 {
 				log.Error("Search term is required")
 				return
@@ -27,24 +61,40 @@ func NewStarsSearchCommand(opts *github.Options) *cobra.Command {
 				searchTerm = strings.Join(args, " ")
 			}
 
-			allStars, err := github.GetAllStars(opts.User)
+			q, err := query.Parse(searchTerm, github.StarQueryFields, time.Now())
+			if err != nil {
+				log.Error("Invalid search", "error", err)
+				return
+			}
+
+			allStars, refresh, err := github.GetAllStars(opts.FetchOptions)
 			if err != nil {
 				log.Error("Failed to get stars", "error", err)
 				return
 			}
 
-			filteredStars := github.FilterStarsByTerm(allStars, searchTerm)
+			filteredStars := github.FilterStarsByTerm(q.Filter(allStars), q.Text, opts.Search)
+			if err := github.SortStars(filteredStars, opts.Sort); err != nil {
+				log.Error("Failed to sort stars", "error", err)
+				return
+			}
 
-			formattedOutput, err := github.PrintStars(filteredStars)
-			if err != nil {
+			if err := printStars(cmd, filteredStars, opts); err != nil {
 				log.Error("Failed to format stars", "error", err)
 				return
 			}
-			fmt.Print(formattedOutput)
+
+			if err := refresh.Wait(cmd.ErrOrStderr()); err != nil {
+				log.Error("Failed to refresh stars", "error", err)
+			}
 		},
 	}
 	cmd.Flags().StringVar(&searchTerm, "term", "", "Term to search for in bookmarks")
 
+	item.AddSearchFlags(cmd.Flags(), &opts.Search, github.SearchFields, github.MatchFields)
+
+	output.AddFlags(cmd.Flags(), &opts.Output)
+
 	return cmd
 }
 
@@ -53,30 +103,57 @@ func NewStarsListCommand(opts *github.Options) *cobra.Command {
 		Use:   "list",
 		Short: "List all stars",
 		Run: func(cmd *cobra.Command, args []string) {
-			allStars, err := github.GetAllStars(opts.User)
+			if err := opts.Output.Validate(); err != nil {
+				log.Error("Invalid output options", "error", err)
+				return
+			}
+			if err := opts.Sort.Validate(github.StarSorts); err != nil {
+				log.Error("Invalid sort options", "error", err)
+				return
+			}
+
+			allStars, refresh, err := github.GetAllStars(opts.FetchOptions)
 			if err != nil {
 				log.Error("Failed to get stars", "error", err)
 				return
 			}
 
-			formattedOutput, err := github.PrintStars(allStars)
-			if err != nil {
+			if err := github.SortStars(allStars, opts.Sort); err != nil {
+				log.Error("Failed to sort stars", "error", err)
+				return
+			}
+
+			if err := printStars(cmd, allStars, opts); err != nil {
 				log.Error("Failed to format stars", "error", err)
 				return
 			}
-			fmt.Print(formattedOutput)
+
+			if err := refresh.Wait(cmd.ErrOrStderr()); err != nil {
+				log.Error("Failed to refresh stars", "error", err)
+			}
 		},
 	}
 
+	output.AddFlags(cmd.Flags(), &opts.Output)
+
 	return cmd
 }
 
+// printStars writes stars in the format selected with --output
+func printStars(cmd *cobra.Command, stars []github.Star, opts *github.Options) error {
+	return output.Render(cmd.OutOrStdout(), opts.Output, github.StarRecords(stars), func() (string, error) {
+		return github.PrintStars(stars)
+	})
+}
+
 func NewStarsCommand(opts *github.Options) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "stars",
 		Short: "Work with GitHub stars",
 	}
 
+	output.AddSortFlags(cmd.PersistentFlags(), &opts.Sort, github.StarSorts)
+
 	cmd.AddCommand(NewStarsListCommand(opts))
 	cmd.AddCommand(NewStarsSearchCommand(opts))
 
@@ -0,0 +1,24 @@
+package cmd
+
+import (
+	"github.com/malleatus/tamjaweb/cmd/history"
+	internalHistory "github.com/malleatus/tamjaweb/internal/history"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	opts := &internalHistory.Options{}
+
+	cmd := &cobra.Command{
+		Use:   "history",
+		Short: "Search browser history",
+	}
+
+	cmd.PersistentFlags().StringVar(&opts.Profile, "profile", "Default", `Browser profile to use, either its name or directory ("all" for every profile)`)
+
+	cmd.PersistentFlags().BoolVar(&opts.Strict, "strict", false, "Exit non-zero when a browser's history can't be read")
+
+	cmd.AddCommand(history.NewSearchCommand(opts))
+
+	rootCmd.AddCommand(cmd)
+}
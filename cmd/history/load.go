@@ -0,0 +1,35 @@
+package history
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/malleatus/tamjaweb/internal/browser"
+	internalHistory "github.com/malleatus/tamjaweb/internal/history"
+	"github.com/malleatus/tamjaweb/internal/query"
+)
+
+// loadHistory loads the history of every browser visited between --since and
+// --until, reporting the browsers that failed to stderr, see
+// browser.ErrorReporter
+func loadHistory(cmd *cobra.Command, opts *internalHistory.Options, now time.Time) (map[string][]browser.HistoryEntry, error) {
+	since, err := query.ParseTime(opts.Since, now)
+	if err != nil {
+		return nil, fmt.Errorf("--since: %w", err)
+	}
+	until, err := query.ParseTime(opts.Until, now)
+	if err != nil {
+		return nil, fmt.Errorf("--until: %w", err)
+	}
+
+	allHistory, err := browser.GetAllHistory(cmd.Context(), opts.Profile, browser.HistoryWindow{Since: since, Until: until})
+
+	reporter := browser.ErrorReporter{W: cmd.ErrOrStderr(), Strict: opts.Strict}
+	if err := reporter.Report(err, "history"); err != nil {
+		return allHistory, err
+	}
+
+	return allHistory, reporter.Err()
+}
@@ -0,0 +1,75 @@
+package history
+
+import (
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/spf13/cobra"
+
+	internalHistory "github.com/malleatus/tamjaweb/internal/history"
+	"github.com/malleatus/tamjaweb/internal/item"
+	"github.com/malleatus/tamjaweb/internal/output"
+)
+
+func NewSearchCommand(opts *internalHistory.Options) *cobra.Command {
+	var searchTerm string
+
+	cmd := &cobra.Command{
+		Use:          "search",
+		Short:        "Search for visited pages",
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := opts.Output.Validate(); err != nil {
+				return err
+			}
+			if err := opts.Search.Validate(internalHistory.MatchFields); err != nil {
+				return err
+			}
+
+			if searchTerm == "" && len(args) == 0 {
+				log.Error("Search term is required")
+				return nil
+			}
+
+			// Use args as search term if not provided via flag
+			if searchTerm == "" && len(args) > 0 {
+				searchTerm = strings.Join(args, " ")
+			}
+
+			now := time.Now()
+
+			allHistory, loadErr := loadHistory(cmd, opts, now)
+			if allHistory == nil && loadErr != nil {
+				return loadErr
+			}
+
+			filteredHistory := internalHistory.FilterHistoryByTerm(internalHistory.Entries(allHistory), searchTerm, opts.Search)
+			if err := internalHistory.RankHistory(filteredHistory, opts.Rank, now); err != nil {
+				return err
+			}
+
+			err := output.Render(cmd.OutOrStdout(), opts.Output, internalHistory.Records(filteredHistory), func() (string, error) {
+				return internalHistory.PrintHistory(filteredHistory)
+			})
+			if err != nil {
+				return err
+			}
+
+			return loadErr
+		},
+	}
+	cmd.Flags().StringVar(&searchTerm, "term", "", "Term to search for in history")
+
+	cmd.Flags().StringVar(&opts.Since, "since", "", "Only include pages visited since this date (2006-01-02), time (RFC 3339) or duration ago (36h, 7d, 2w)")
+
+	cmd.Flags().StringVar(&opts.Until, "until", "", "Only include pages visited before this date, time or duration ago")
+
+	item.AddSearchFlags(cmd.Flags(), &opts.Search, internalHistory.MatchFields, internalHistory.MatchFields)
+
+	output.AddFlags(cmd.Flags(), &opts.Output)
+
+	cmd.Flags().StringVar(&opts.Rank, "rank", internalHistory.RankRelevance, "How to order results: "+strings.Join(internalHistory.Ranks, ", "))
+
+	return cmd
+}
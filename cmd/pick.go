@@ -0,0 +1,12 @@
+package cmd
+
+import (
+	"github.com/malleatus/tamjaweb/cmd/pick"
+	internalPick "github.com/malleatus/tamjaweb/internal/pick"
+)
+
+func init() {
+	opts := &internalPick.Options{}
+
+	rootCmd.AddCommand(pick.NewPickCommand(opts))
+}
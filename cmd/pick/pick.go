@@ -0,0 +1,127 @@
+package pick
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/malleatus/tamjaweb/internal/github"
+	"github.com/malleatus/tamjaweb/internal/item"
+	internalPick "github.com/malleatus/tamjaweb/internal/pick"
+	"github.com/malleatus/tamjaweb/internal/search"
+)
+
+func NewPickCommand(opts *internalPick.Options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "pick [query]",
+		Short: "Pick bookmarks, stars, tabs or history interactively and open them",
+		Long: `Pick bookmarks, stars, tabs or history interactively and open them
+
+Shows everything in --sources in fzf, with a preview of the highlighted entry.
+Tab selects several entries, enter opens them in the default browser (see
+--action) and these keys accept the selection with another action:
+
+  ctrl-o  open in the default browser
+  ctrl-y  copy the URLs to the clipboard (OSC 52, works over ssh and in tmux)
+  alt-p   print the URLs
+
+Stars are only included with --user.`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := opts.Validate(); err != nil {
+				return err
+			}
+			opts.Query = strings.Join(args, " ")
+
+			items, refresh, loadErr := search.Load(cmd.Context(), cmd.ErrOrStderr(), opts.LoadOptions, time.Now())
+			if len(items) == 0 {
+				if loadErr != nil {
+					return loadErr
+				}
+				return errors.New("nothing to pick from")
+			}
+
+			action, picked, err := internalPick.Pick(items, *opts)
+			if err != nil {
+				return err
+			}
+
+			if err := run(cmd, action, picked); err != nil {
+				return err
+			}
+
+			if err := refresh.Wait(cmd.ErrOrStderr()); err != nil {
+				return err
+			}
+
+			return loadErr
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.Profile, "profile", "Default", `Browser profile to use, either its name or directory ("all" for every profile)`)
+
+	cmd.Flags().BoolVar(&opts.Strict, "strict", false, "Exit non-zero when a browser can't be read")
+
+	cmd.Flags().StringSliceVar(&opts.Sources, "sources", nil, "Lists to pick from: "+strings.Join(item.Sources, ", ")+" (default all, stars only with --user)")
+
+	cmd.Flags().StringVar(&opts.User, "user", "", "GitHub user whose stars to include")
+
+	github.AddFetchFlags(cmd.Flags(), &opts.FetchOptions)
+	cmd.MarkFlagsMutuallyExclusive("refresh", "offline")
+
+	cmd.Flags().StringVar(&opts.Since, "since", "30d", "Only include history visited since this date (2006-01-02), time (RFC 3339) or duration ago (36h, 7d, 2w)")
+
+	cmd.Flags().StringVar(&opts.Action, "action", internalPick.ActionOpen, "What enter does with the selection: "+strings.Join(internalPick.Actions, ", "))
+
+	return cmd
+}
+
+// run does action with the URLs of the picked items
+func run(cmd *cobra.Command, action string, picked []item.Item) error {
+	if len(picked) == 0 {
+		return nil
+	}
+
+	var urls []string
+	for _, entry := range picked {
+		urls = append(urls, entry.URL)
+	}
+
+	switch action {
+	case internalPick.ActionOpen:
+		for _, url := range urls {
+			if err := internalPick.Open(runtime.GOOS, url); err != nil {
+				return err
+			}
+		}
+
+	case internalPick.ActionCopy:
+		// the escape sequence has to reach the terminal even when stdout is
+		// redirected
+		var terminal io.Writer = cmd.ErrOrStderr()
+		if tty, err := os.OpenFile("/dev/tty", os.O_WRONLY, 0); err == nil {
+			defer tty.Close()
+			terminal = tty
+		}
+
+		if err := internalPick.Copy(terminal, strings.Join(urls, "\n"), os.Getenv("TMUX") != ""); err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.ErrOrStderr(), "Copied %d URL(s)\n", len(urls))
+
+	case internalPick.ActionPrint:
+		for _, url := range urls {
+			if _, err := fmt.Fprintln(cmd.OutOrStdout(), url); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/malleatus/tamjaweb/internal/bookmarks"
+	"github.com/malleatus/tamjaweb/internal/github"
+	"github.com/malleatus/tamjaweb/internal/history"
+	"github.com/malleatus/tamjaweb/internal/item"
+	"github.com/malleatus/tamjaweb/internal/output"
+	"github.com/malleatus/tamjaweb/internal/tabs"
+	"github.com/spf13/cobra"
+)
+
+// schemas are the JSON Schemas of the lists written by --output json, keyed
+// by the command group that writes them
+var schemas = map[string]func() map[string]any{
+	"bookmarks": func() map[string]any { return output.Schema[bookmarks.Record]("tamjaweb bookmarks") },
+	"history":   func() map[string]any { return output.Schema[history.Record]("tamjaweb history") },
+	"search":    func() map[string]any { return output.Schema[item.Record]("tamjaweb search") },
+	"stars":     func() map[string]any { return output.Schema[github.StarRecord]("tamjaweb github stars") },
+	"tabs":      func() map[string]any { return output.Schema[tabs.Record]("tamjaweb tabs") },
+}
+
+func init() {
+	var names []string
+	for name := range schemas {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	cmd := &cobra.Command{
+		Use:       "schema <" + strings.Join(names, "|") + ">",
+		Short:     "Print the JSON Schema of a list's --output json",
+		Args:      cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+		ValidArgs: names,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			data, err := json.MarshalIndent(schemas[args[0]](), "", "  ")
+			if err != nil {
+				return err
+			}
+
+			_, err = fmt.Fprintln(cmd.OutOrStdout(), string(data))
+			return err
+		},
+	}
+
+	rootCmd.AddCommand(cmd)
+}
@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/bradleyjkemp/cupaloy/v2"
+	"github.com/stretchr/testify/require"
+)
+
+// the schemas document the --output json field names, which scripts depend
+// on, so any change to them shows up here
+func TestSchemaCommand(t *testing.T) {
+	for name := range schemas {
+		t.Run(name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			rootCmd.SetOut(&stdout)
+			rootCmd.SetArgs([]string{"schema", name})
+			t.Cleanup(func() {
+				rootCmd.SetOut(nil)
+				rootCmd.SetArgs(nil)
+			})
+
+			err := rootCmd.Execute()
+			require.NoError(t, err)
+
+			cupaloy.SnapshotT(t, stdout.String())
+		})
+	}
+}
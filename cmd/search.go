@@ -0,0 +1,12 @@
+package cmd
+
+import (
+	"github.com/malleatus/tamjaweb/cmd/search"
+	internalSearch "github.com/malleatus/tamjaweb/internal/search"
+)
+
+func init() {
+	opts := &internalSearch.Options{}
+
+	rootCmd.AddCommand(search.NewSearchCommand(opts))
+}
@@ -0,0 +1,126 @@
+package search
+
+import (
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/spf13/cobra"
+
+	"github.com/malleatus/tamjaweb/internal/github"
+	"github.com/malleatus/tamjaweb/internal/item"
+	"github.com/malleatus/tamjaweb/internal/output"
+	"github.com/malleatus/tamjaweb/internal/query"
+	internalSearch "github.com/malleatus/tamjaweb/internal/search"
+)
+
+func NewSearchCommand(opts *internalSearch.Options) *cobra.Command {
+	var searchTerm string
+
+	cmd := &cobra.Command{
+		Use:   "search [query]",
+		Short: "Search bookmarks, stars, tabs and history at once",
+		Long: `Search bookmarks, stars, tabs and history at once
+
+Everything in --sources is ranked together in one list. The query is fuzzy
+matched against the title, URL and description of each result (see --in,
+--exact and --case-sensitive), and can filter on fields with field:value
+(quoted when the value has spaces):
+
+  source:         bookmarks, stars, tabs or history
+  title:, url:, folder:, description:
+                  the field contains the value
+  host:           the URL's host is the value or one of its subdomains
+  browser:, profile:
+                  GitHub and the stargazer for stars
+  date:           when a bookmark was added, a repository starred, a tab last
+                  selected or a page last visited, as a date (2024, 2024-06,
+                  2024-06-01) or a duration before now (30d, 2w), optionally
+                  compared with >, >=, < or <=
+  before:, after: shorthands for date:< and date:>
+
+Stars are only included with --user.
+
+  tamjaweb search --user octocat fzf -source:history`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := opts.Output.Validate(); err != nil {
+				return err
+			}
+			if err := opts.Sort.Validate(item.Sorts); err != nil {
+				return err
+			}
+			if err := opts.LoadOptions.Validate(); err != nil {
+				return err
+			}
+			if err := opts.Search.Validate(item.SearchFields); err != nil {
+				return err
+			}
+
+			if searchTerm == "" && len(args) == 0 {
+				log.Error("Search term is required")
+				return nil
+			}
+
+			// Use args as search term if not provided via flag
+			if searchTerm == "" && len(args) > 0 {
+				searchTerm = strings.Join(args, " ")
+			}
+
+			now := time.Now()
+
+			q, err := query.Parse(searchTerm, item.QueryFields, now)
+			if err != nil {
+				return err
+			}
+
+			items, refresh, loadErr := internalSearch.Load(cmd.Context(), cmd.ErrOrStderr(), opts.LoadOptions, now)
+			if items == nil && loadErr != nil {
+				return loadErr
+			}
+
+			items, err = item.FilterByTerm(q.Filter(items), q.Text, opts.Search, item.MatchFields)
+			if err != nil {
+				return err
+			}
+			if err := item.SortItems(items, opts.Sort); err != nil {
+				return err
+			}
+
+			err = output.Render(cmd.OutOrStdout(), opts.Output, item.Records(items), func() (string, error) {
+				return item.PrintItems(items)
+			})
+			if err != nil {
+				return err
+			}
+
+			if err := refresh.Wait(cmd.ErrOrStderr()); err != nil {
+				return err
+			}
+
+			return loadErr
+		},
+	}
+	cmd.Flags().StringVar(&searchTerm, "term", "", "Term to search for")
+
+	cmd.Flags().StringVar(&opts.Profile, "profile", "Default", `Browser profile to use, either its name or directory ("all" for every profile)`)
+
+	cmd.Flags().BoolVar(&opts.Strict, "strict", false, "Exit non-zero when a browser can't be read")
+
+	cmd.Flags().StringSliceVar(&opts.Sources, "sources", nil, "Lists to search: "+strings.Join(item.Sources, ", ")+" (default all, stars only with --user)")
+
+	cmd.Flags().StringVar(&opts.User, "user", "", "GitHub user whose stars to include")
+
+	github.AddFetchFlags(cmd.Flags(), &opts.FetchOptions)
+	cmd.MarkFlagsMutuallyExclusive("refresh", "offline")
+
+	cmd.Flags().StringVar(&opts.Since, "since", "30d", "Only include history visited since this date (2006-01-02), time (RFC 3339) or duration ago (36h, 7d, 2w)")
+
+	item.AddSearchFlags(cmd.Flags(), &opts.Search, item.SearchFields, item.MatchFields)
+
+	output.AddSortFlags(cmd.Flags(), &opts.Sort, item.Sorts)
+
+	output.AddFlags(cmd.Flags(), &opts.Output)
+
+	return cmd
+}
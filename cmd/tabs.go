@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"github.com/malleatus/tamjaweb/cmd/tabs"
+	"github.com/malleatus/tamjaweb/internal/browser"
+	internalTabs "github.com/malleatus/tamjaweb/internal/tabs"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	opts := &internalTabs.Options{}
+
+	cmd := &cobra.Command{
+		Use:   "tabs",
+		Short: "Inspect open browser tabs",
+	}
+
+	cmd.PersistentFlags().StringVar(&opts.Profile, "profile", "Default", `Browser profile to use, either its name or directory ("all" for every profile)`)
+
+	cmd.PersistentFlags().BoolVar(&opts.Strict, "strict", false, "Exit non-zero when a browser's tabs can't be read")
+
+	cmd.PersistentFlags().BoolVar(&opts.Closed, "closed", false, "Include recently closed tabs")
+
+	cmd.PersistentFlags().StringVar(&opts.CDPURL, "cdp-url", browser.DefaultCDPURL, "DevTools protocol address of a browser started with --remote-debugging-port")
+
+	cmd.AddCommand(tabs.NewSearchCommand(opts))
+	cmd.AddCommand(tabs.NewListCommand(opts))
+	cmd.AddCommand(tabs.NewCloseCommand(opts))
+	cmd.AddCommand(tabs.NewActivateCommand(opts))
+	cmd.AddCommand(tabs.NewOpenCommand(opts))
+
+	rootCmd.AddCommand(cmd)
+}
@@ -0,0 +1,38 @@
+package tabs
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/malleatus/tamjaweb/internal/browser"
+	internalTabs "github.com/malleatus/tamjaweb/internal/tabs"
+)
+
+func NewActivateCommand(opts *internalTabs.Options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "activate <query>",
+		Short:        "Bring the live tab best matching query to the front",
+		Args:         cobra.MinimumNArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cdp := browser.NewCDP(opts.CDPURL)
+
+			matches, err := findLiveTabs(cmd, cdp, strings.Join(args, " "))
+			if err != nil {
+				return err
+			}
+
+			tab := matches[0]
+			if err := cdp.ActivateTab(cmd.Context(), tab.ID); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Activated %s (%s)\n", tab.Title, tab.URL)
+
+			return nil
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,53 @@
+package tabs
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/malleatus/tamjaweb/internal/browser"
+	internalTabs "github.com/malleatus/tamjaweb/internal/tabs"
+)
+
+func NewCloseCommand(opts *internalTabs.Options) *cobra.Command {
+	var all bool
+
+	cmd := &cobra.Command{
+		Use:          "close <query>",
+		Short:        "Close the live tab matching query, either a tab id or a search term",
+		Args:         cobra.MinimumNArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cdp := browser.NewCDP(opts.CDPURL)
+
+			matches, err := findLiveTabs(cmd, cdp, strings.Join(args, " "))
+			if err != nil {
+				return err
+			}
+
+			// closing is not undoable from here, so a vague query must not close
+			// more than the user meant to
+			if len(matches) > 1 && !all {
+				output, err := internalTabs.PrintTabs(map[string][]browser.Tab{cdp.Name(): matches})
+				if err != nil {
+					return err
+				}
+				fmt.Fprint(cmd.ErrOrStderr(), output)
+				return fmt.Errorf("%d tabs match, narrow the query or pass --all to close all of them", len(matches))
+			}
+
+			for _, tab := range matches {
+				if err := cdp.CloseTab(cmd.Context(), tab.ID); err != nil {
+					return err
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "Closed %s (%s)\n", tab.Title, tab.URL)
+			}
+
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&all, "all", false, "Close every tab matching query")
+
+	return cmd
+}
@@ -0,0 +1,35 @@
+package tabs
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/malleatus/tamjaweb/internal/output"
+	internalTabs "github.com/malleatus/tamjaweb/internal/tabs"
+)
+
+func NewListCommand(opts *internalTabs.Options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "list",
+		Short:        "List the tabs of each browser's last session",
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := opts.Output.Validate(); err != nil {
+				return err
+			}
+
+			allTabs, loadErr := loadTabs(cmd, opts)
+
+			if err := printTabs(cmd, allTabs, opts); err != nil {
+				return err
+			}
+
+			return loadErr
+		},
+	}
+
+	cmd.Flags().BoolVar(&opts.Live, "live", false, "Read the tabs of the running browser at --cdp-url instead of its saved session")
+
+	output.AddFlags(cmd.Flags(), &opts.Output)
+
+	return cmd
+}
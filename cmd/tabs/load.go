@@ -0,0 +1,71 @@
+package tabs
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/malleatus/tamjaweb/internal/browser"
+	"github.com/malleatus/tamjaweb/internal/output"
+	internalTabs "github.com/malleatus/tamjaweb/internal/tabs"
+)
+
+// loadTabs loads the tabs of every browser, reporting the browsers that
+// failed to stderr, see browser.ErrorReporter
+func loadTabs(cmd *cobra.Command, opts *internalTabs.Options) (map[string][]browser.Tab, error) {
+	if opts.Live {
+		return loadLiveTabs(cmd, opts)
+	}
+
+	allTabs, err := browser.GetAllTabs(cmd.Context(), opts.Profile)
+	if !opts.Closed {
+		allTabs = internalTabs.FilterOpenTabs(allTabs)
+	}
+
+	reporter := browser.ErrorReporter{W: cmd.ErrOrStderr(), Strict: opts.Strict}
+	if err := reporter.Report(err, "tabs"); err != nil {
+		return allTabs, err
+	}
+
+	return allTabs, reporter.Err()
+}
+
+// loadLiveTabs loads the tabs of the browser serving the DevTools protocol at
+// --cdp-url
+func loadLiveTabs(cmd *cobra.Command, opts *internalTabs.Options) (map[string][]browser.Tab, error) {
+	cdp := browser.NewCDP(opts.CDPURL)
+
+	browserName, err := cdp.BrowserName(cmd.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	tabs, err := cdp.GetTabs(cmd.Context(), opts.Profile)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string][]browser.Tab{browserName: tabs}, nil
+}
+
+// findLiveTabs returns the live tabs query refers to
+func findLiveTabs(cmd *cobra.Command, cdp *browser.CDP, query string) ([]browser.Tab, error) {
+	tabs, err := cdp.GetTabs(cmd.Context(), "")
+	if err != nil {
+		return nil, err
+	}
+
+	matches := internalTabs.FindTabs(tabs, query)
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("no tab matches %q", query)
+	}
+
+	return matches, nil
+}
+
+// printTabs writes tabs in the format selected with --output
+func printTabs(cmd *cobra.Command, tabs map[string][]browser.Tab, opts *internalTabs.Options) error {
+	return output.Render(cmd.OutOrStdout(), opts.Output, internalTabs.Records(tabs), func() (string, error) {
+		return internalTabs.PrintTabs(tabs)
+	})
+}
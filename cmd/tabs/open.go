@@ -0,0 +1,32 @@
+package tabs
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/malleatus/tamjaweb/internal/browser"
+	internalTabs "github.com/malleatus/tamjaweb/internal/tabs"
+)
+
+func NewOpenCommand(opts *internalTabs.Options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "open <url>",
+		Short:        "Open url in a new tab of the live browser",
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cdp := browser.NewCDP(opts.CDPURL)
+
+			id, err := cdp.OpenTab(cmd.Context(), args[0])
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Opened %s in tab %s\n", args[0], id)
+
+			return nil
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,58 @@
+package tabs
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/log"
+	"github.com/spf13/cobra"
+
+	"github.com/malleatus/tamjaweb/internal/item"
+	"github.com/malleatus/tamjaweb/internal/output"
+	internalTabs "github.com/malleatus/tamjaweb/internal/tabs"
+)
+
+func NewSearchCommand(opts *internalTabs.Options) *cobra.Command {
+	var searchTerm string
+
+	cmd := &cobra.Command{
+		Use:          "search",
+		Short:        "Search for tabs",
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := opts.Output.Validate(); err != nil {
+				return err
+			}
+			if err := opts.Search.Validate(internalTabs.MatchFields); err != nil {
+				return err
+			}
+
+			if searchTerm == "" && len(args) == 0 {
+				log.Error("Search term is required")
+				return nil
+			}
+
+			// Use args as search term if not provided via flag
+			if searchTerm == "" && len(args) > 0 {
+				searchTerm = strings.Join(args, " ")
+			}
+
+			allTabs, loadErr := loadTabs(cmd, opts)
+
+			filteredTabs := internalTabs.FilterTabsByTerm(allTabs, searchTerm, opts.Search)
+			if err := printTabs(cmd, filteredTabs, opts); err != nil {
+				return err
+			}
+
+			return loadErr
+		},
+	}
+	cmd.Flags().StringVar(&searchTerm, "term", "", "Term to search for in tabs")
+
+	cmd.Flags().BoolVar(&opts.Live, "live", false, "Read the tabs of the running browser at --cdp-url instead of its saved session")
+
+	item.AddSearchFlags(cmd.Flags(), &opts.Search, internalTabs.MatchFields, internalTabs.MatchFields)
+
+	output.AddFlags(cmd.Flags(), &opts.Output)
+
+	return cmd
+}
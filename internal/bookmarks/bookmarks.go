@@ -2,109 +2,158 @@ package bookmarks
 
 import (
 	"bytes"
-	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/charmbracelet/log"
 	"github.com/malleatus/tamjaweb/internal/browser"
-	"github.com/malleatus/tamjaweb/internal/fzf"
+	"github.com/malleatus/tamjaweb/internal/item"
+	"github.com/malleatus/tamjaweb/internal/output"
+	"github.com/malleatus/tamjaweb/internal/query"
 	"github.com/olekukonko/tablewriter"
 )
 
 type Options struct {
 	Profile string
+	Strict  bool
+	// Long shows every bookmark field, including ids and metadata
+	Long bool
+	// UsedWithin only keeps bookmarks opened within this long ago, 0 keeps all
+	UsedWithin time.Duration
+	Sort       output.SortOptions
+	Search     item.SearchOptions
+	Output     output.Options
 }
 
+// MatchFields are the fields of a bookmark search terms are matched against
+var MatchFields = []string{item.FieldTitle, item.FieldURL}
+
+// SearchFields lists the fields --in accepts for bookmarks
+var SearchFields = []string{item.FieldTitle, item.FieldURL, item.FieldFolder}
+
 // FilterBookmarksByTerm filters bookmarks using fzf's filter functionality
-// Returns a map of browser names to matching bookmarks
-func FilterBookmarksByTerm(bookmarks map[string][]browser.Bookmark, term string) map[string][]browser.Bookmark {
+// Returns the matching bookmarks with their scores, best match first
+func FilterBookmarksByTerm(entries []Entry, term string, opts item.SearchOptions) []Entry {
 	// If term is empty, return all bookmarks
 	if term == "" {
-		return bookmarks
+		return entries
 	}
 
-	// Create a slice to hold all bookmarks with their browser names
-	type bookmarkEntry struct {
-		browserName string
-		bookmark    browser.Bookmark
+	matches, err := item.Match(Items(entries), term, opts, MatchFields)
+	if err != nil {
+		log.Error("Failed to filter bookmarks", "error", err)
+		return nil
 	}
 
-	var entries []bookmarkEntry
-
-	// Populate entries
-	for browserName, bookmarkList := range bookmarks {
-		for _, bookmark := range bookmarkList {
-			entries = append(entries, bookmarkEntry{
-				browserName: browserName,
-				bookmark:    bookmark,
-			})
-		}
+	filteredEntries := make([]Entry, 0, len(matches))
+	for _, match := range matches {
+		entry := entries[match.Index]
+		entry.Score = match.Score
+		filteredEntries = append(filteredEntries, entry)
 	}
 
-	// Create inputs for fzf
-	inputs := make([]string, len(entries))
-	for i, entry := range entries {
-		// Create searchable text combining all fields (same as original)
-		inputs[i] = fmt.Sprintf("%d\t%s\t%s",
-			i,
-			entry.bookmark.Title,
-			entry.bookmark.URL,
-		)
-	}
+	return filteredEntries
+}
 
-	// Use the FZF utility to filter
-	matchedIndices, err := fzf.FilterStrings(inputs, term)
-	if err != nil {
-		log.Error("Failed to filter bookmarks", "error", err)
-		return make(map[string][]browser.Bookmark)
-	}
+// QueryFields are the fields bookmark searches can filter on, e.g.
+// folder:Work host:github.com added:>2024-01-01
+var QueryFields = query.Fields[Entry]{
+	"title":   query.Contains(func(e Entry) string { return e.Title }),
+	"url":     query.Contains(func(e Entry) string { return e.URL }),
+	"host":    query.Host(func(e Entry) string { return e.URL }),
+	"folder":  query.Contains(func(e Entry) string { return e.FolderPath }),
+	"browser": query.Equals(func(e Entry) string { return e.Browser }),
+	"profile": query.Equals(func(e Entry) string { return e.Profile }),
+	"added":   query.Date(func(e Entry) time.Time { return e.DateAdded }),
+	"used":    query.Date(func(e Entry) time.Time { return e.DateLastUsed }),
+	"before":  query.Before(func(e Entry) time.Time { return e.DateAdded }),
+	"after":   query.After(func(e Entry) time.Time { return e.DateAdded }),
+}
 
-	// Build filtered bookmarks map
+// FilterBookmarksUsedSince keeps the bookmarks that were last used at or after since.
+// Bookmarks that were never used (or whose browser doesn't track it) are dropped.
+func FilterBookmarksUsedSince(bookmarks map[string][]browser.Bookmark, since time.Time) map[string][]browser.Bookmark {
 	filteredBookmarks := make(map[string][]browser.Bookmark)
 
-	for _, idx := range matchedIndices {
-		entry := entries[idx]
-		filteredBookmarks[entry.browserName] = append(
-			filteredBookmarks[entry.browserName],
-			entry.bookmark,
-		)
+	for browserName, bookmarkList := range bookmarks {
+		for _, bookmark := range bookmarkList {
+			if bookmark.DateLastUsed.IsZero() || bookmark.DateLastUsed.Before(since) {
+				continue
+			}
+			filteredBookmarks[browserName] = append(filteredBookmarks[browserName], bookmark)
+		}
 	}
 
 	return filteredBookmarks
 }
 
-// prints the bookmarks in a tabular format
-func PrintBookmarks(bookmarks map[string][]browser.Bookmark) (string, error) {
-	if len(bookmarks) == 0 {
+// prints the bookmarks in a tabular format. long adds the ids, modification
+// dates and metadata of each bookmark.
+func PrintBookmarks(entries []Entry, long bool) (string, error) {
+	if len(entries) == 0 {
 		return "No bookmarks found", nil
 	}
 
 	var buf bytes.Buffer
 	table := tablewriter.NewWriter(&buf)
 
-	table.SetHeader([]string{"Browser", "Title", "URL", "Folder", "Date Added"})
+	header := []string{"Browser", "Profile", "Title", "URL", "Folder", "Date Added", "Last Used"}
+	if long {
+		header = append(header, "ID", "GUID", "Date Modified", "Folder Modified", "Meta Info")
+	}
+
+	alignment := make([]int, len(header))
+	for i := range alignment {
+		alignment[i] = tablewriter.ALIGN_LEFT
+	}
+
+	table.SetHeader(header)
 	table.SetAutoWrapText(true)
-	table.SetColumnAlignment([]int{
-		tablewriter.ALIGN_LEFT,
-		tablewriter.ALIGN_LEFT,
-		tablewriter.ALIGN_LEFT,
-		tablewriter.ALIGN_LEFT,
-		tablewriter.ALIGN_LEFT,
-	})
+	table.SetColumnAlignment(alignment)
 	table.SetColWidth(50)
 
-	for browserName, bookmarkList := range bookmarks {
-		for _, bookmark := range bookmarkList {
-			table.Append([]string{
-				browserName,
-				bookmark.Title,
-				bookmark.URL,
-				bookmark.FolderPath,
-				bookmark.DateAdded.Format("2006-01-02 15:04:05"),
-			})
+	for _, entry := range entries {
+		row := []string{
+			entry.Browser,
+			entry.Profile,
+			entry.Title,
+			entry.URL,
+			entry.FolderPath,
+			formatDate(entry.DateAdded),
+			formatDate(entry.DateLastUsed),
 		}
+		if long {
+			row = append(row,
+				entry.ID,
+				entry.GUID,
+				formatDate(entry.DateModified),
+				formatDate(entry.FolderDateModified),
+				formatMetaInfo(entry.MetaInfo),
+			)
+		}
+		table.Append(row)
 	}
 
 	table.Render()
 
 	return buf.String(), nil
 }
+
+// formatDate formats t for display, leaving dates that were never set empty
+func formatDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format("2006-01-02 15:04:05")
+}
+
+// formatMetaInfo formats meta info as key=value pairs sorted by key
+func formatMetaInfo(metaInfo map[string]string) string {
+	pairs := make([]string, 0, len(metaInfo))
+	for _, key := range slices.Sorted(maps.Keys(metaInfo)) {
+		pairs = append(pairs, key+"="+metaInfo[key])
+	}
+	return strings.Join(pairs, " ")
+}
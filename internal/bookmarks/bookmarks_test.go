@@ -6,6 +6,8 @@ import (
 
 	"github.com/bradleyjkemp/cupaloy/v2"
 	"github.com/malleatus/tamjaweb/internal/browser"
+	"github.com/malleatus/tamjaweb/internal/item"
+	"github.com/malleatus/tamjaweb/internal/query"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -247,7 +249,10 @@ func TestFilterBookmarksByTerm(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			result := FilterBookmarksByTerm(bookmarks, tc.searchTerm)
+			result := map[string][]Entry{}
+			for _, entry := range FilterBookmarksByTerm(Entries(bookmarks), tc.searchTerm, item.SearchOptions{}) {
+				result[entry.Browser] = append(result[entry.Browser], entry)
+			}
 
 			assert.Equal(t, len(tc.expectedResults), len(result), "Number of browsers with matches")
 
@@ -280,22 +285,142 @@ func TestPrintBookmarks(t *testing.T) {
 				URL:        "https://example.com",
 				DateAdded:  fixedTime,
 				FolderPath: "Test Folder",
+				Profile:    "Work",
 			},
 		},
 	}
 
-	output, err := PrintBookmarks(bookmarks)
+	output, err := PrintBookmarks(Entries(bookmarks), false)
 	require.NoError(t, err)
 
 	// Snapshot test replaces multiple assert statements
 	cupaloy.SnapshotT(t, output)
 }
 
+func TestPrintBookmarksLong(t *testing.T) {
+	fixedTime := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
+	bookmarks := map[string][]browser.Bookmark{
+		"TestBrowser": {
+			{
+				ID:                 "42",
+				GUID:               "00000000-0000-4000-a000-000000000042",
+				Title:              "Test Bookmark",
+				URL:                "https://example.com",
+				DateAdded:          fixedTime,
+				DateLastUsed:       fixedTime.Add(48 * time.Hour),
+				DateModified:       fixedTime.Add(time.Hour),
+				FolderDateModified: fixedTime.Add(2 * time.Hour),
+				FolderPath:         "Test Folder",
+				Profile:            "Work",
+				MetaInfo:           map[string]string{"power_bookmark_meta": "abc", "last_visited_desktop": "13214422057039153"},
+			},
+			{
+				Title:      "Never Used",
+				URL:        "https://unused.example.com",
+				DateAdded:  fixedTime,
+				FolderPath: "Test Folder",
+				Profile:    "Work",
+			},
+		},
+	}
+
+	output, err := PrintBookmarks(Entries(bookmarks), true)
+	require.NoError(t, err)
+
+	cupaloy.SnapshotT(t, output)
+}
+
+func TestFilterBookmarksUsedSince(t *testing.T) {
+	fixedTime := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
+	bookmarks := map[string][]browser.Bookmark{
+		"TestBrowser1": {
+			{Title: "Recent", DateLastUsed: fixedTime},
+			{Title: "Old", DateLastUsed: fixedTime.AddDate(-1, 0, 0)},
+			{Title: "Never"},
+		},
+		"TestBrowser2": {
+			{Title: "Old", DateLastUsed: fixedTime.AddDate(0, -2, 0)},
+		},
+	}
+
+	filtered := FilterBookmarksUsedSince(bookmarks, fixedTime.AddDate(0, -1, 0))
+
+	assert.Equal(t, map[string][]browser.Bookmark{
+		"TestBrowser1": {{Title: "Recent", DateLastUsed: fixedTime}},
+	}, filtered)
+}
+
 func TestPrintBookmarksEmpty(t *testing.T) {
 	bookmarks := map[string][]browser.Bookmark{}
 
-	output, err := PrintBookmarks(bookmarks)
+	output, err := PrintBookmarks(Entries(bookmarks), false)
 	require.NoError(t, err)
 
 	cupaloy.SnapshotT(t, output)
 }
+
+func TestRecords(t *testing.T) {
+	dateAdded := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	bookmarks := map[string][]browser.Bookmark{
+		"Firefox": {
+			{ID: "3", Title: "Firefox Bookmark", URL: "https://firefox.example.com", FolderPath: "toolbar", DateAdded: dateAdded, Profile: "default-release"},
+		},
+		"Chrome": {
+			{ID: "1", GUID: "guid1", Title: "Chrome Bookmark", URL: "https://chrome.example.com", FolderPath: "Bookmark Bar", MetaInfo: map[string]string{"key": "value"}},
+		},
+	}
+
+	records := Records(Entries(bookmarks))
+
+	require.Len(t, records, 2)
+	assert.Equal(t, "Chrome", records[0].Browser, "Should order records by browser")
+	assert.Equal(t, "guid1", records[0].GUID)
+	assert.Equal(t, "Bookmark Bar", records[0].Folder)
+	assert.Equal(t, map[string]string{"key": "value"}, records[0].MetaInfo)
+	assert.True(t, records[0].DateAdded.IsZero())
+
+	assert.Equal(t, "Firefox", records[1].Browser)
+	assert.Equal(t, "default-release", records[1].Profile)
+	assert.Equal(t, dateAdded, records[1].DateAdded.Time)
+}
+
+func TestQueryFields(t *testing.T) {
+	fixedTime := time.Date(2024, 3, 1, 12, 0, 0, 0, time.Local)
+	entries := Entries(map[string][]browser.Bookmark{
+		"Brave": {
+			{Title: "Pull requests", URL: "https://github.com/pulls", FolderPath: "Bookmarks/Work", DateAdded: fixedTime, DateLastUsed: fixedTime},
+			{Title: "GitHub Blog", URL: "https://github.blog", FolderPath: "Bookmarks/Work", DateAdded: fixedTime.AddDate(-1, 0, 0)},
+		},
+		"Chrome": {
+			{Title: "GitHub", URL: "https://github.com", FolderPath: "Bookmark Bar", DateAdded: fixedTime, Profile: "Work"},
+		},
+	})
+
+	testCases := []struct {
+		search   string
+		expected []string
+	}{
+		{search: "folder:work", expected: []string{"Pull requests", "GitHub Blog"}},
+		{search: "host:github.com", expected: []string{"Pull requests", "GitHub"}},
+		{search: "browser:brave -host:github.com", expected: []string{"GitHub Blog"}},
+		{search: "profile:work", expected: []string{"GitHub"}},
+		{search: "added:>2024-01-01", expected: []string{"Pull requests", "GitHub"}},
+		{search: "before:2024", expected: []string{"GitHub Blog"}},
+		{search: "used:2024-03", expected: []string{"Pull requests"}},
+		{search: `title:"pull req" url:pulls`, expected: []string{"Pull requests"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.search, func(t *testing.T) {
+			q, err := query.Parse(tc.search, QueryFields, fixedTime)
+			require.NoError(t, err)
+			assert.Empty(t, q.Text)
+
+			var titles []string
+			for _, entry := range q.Filter(entries) {
+				titles = append(titles, entry.Title)
+			}
+			assert.Equal(t, tc.expected, titles)
+		})
+	}
+}
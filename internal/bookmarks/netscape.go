@@ -0,0 +1,230 @@
+package bookmarks
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/malleatus/tamjaweb/internal/browser"
+	xhtml "golang.org/x/net/html"
+)
+
+// FormatNetscape is the bookmark file format every browser (and most
+// bookmarking services) can import and export
+const FormatNetscape = "netscape"
+
+// ExportFormats lists the formats bookmarks can be exported as
+var ExportFormats = []string{FormatNetscape}
+
+const netscapeHeader = `<!DOCTYPE NETSCAPE-Bookmark-file-1>
+<!-- This is an automatically generated file.
+     It will be read and overwritten.
+     DO NOT EDIT! -->
+<META HTTP-EQUIV="Content-Type" CONTENT="text/html; charset=UTF-8">
+<TITLE>Bookmarks</TITLE>
+<H1>Bookmarks</H1>
+`
+
+// netscapeFolder is a folder of the exported tree, holding bookmarks and
+// folders in the order they were first seen
+type netscapeFolder struct {
+	name     string
+	children []netscapeItem
+	folders  map[string]*netscapeFolder
+}
+
+// netscapeItem is either a bookmark or a folder
+type netscapeItem struct {
+	bookmark *browser.Bookmark
+	folder   *netscapeFolder
+}
+
+func (f *netscapeFolder) subfolder(name string) *netscapeFolder {
+	if folder, ok := f.folders[name]; ok {
+		return folder
+	}
+
+	folder := &netscapeFolder{name: name, folders: map[string]*netscapeFolder{}}
+	f.folders[name] = folder
+	f.children = append(f.children, netscapeItem{folder: folder})
+	return folder
+}
+
+// ExportNetscape writes bookmarks as a Netscape bookmark file, nesting them
+// in folders by their Folders. Dates are written in whole seconds, as the
+// format has no finer precision.
+func ExportNetscape(w io.Writer, bookmarks []browser.Bookmark) error {
+	root := &netscapeFolder{folders: map[string]*netscapeFolder{}}
+	for i := range bookmarks {
+		folder := root
+		for _, name := range bookmarks[i].FolderNames() {
+			folder = folder.subfolder(name)
+		}
+		folder.children = append(folder.children, netscapeItem{bookmark: &bookmarks[i]})
+	}
+
+	out := bufio.NewWriter(w)
+	out.WriteString(netscapeHeader)
+	writeNetscapeFolder(out, root, 0)
+
+	return out.Flush()
+}
+
+func writeNetscapeFolder(out *bufio.Writer, folder *netscapeFolder, depth int) {
+	indent := strings.Repeat("    ", depth)
+
+	fmt.Fprintf(out, "%s<DL><p>\n", indent)
+	for _, item := range folder.children {
+		if item.folder != nil {
+			fmt.Fprintf(out, "%s    <DT><H3>%s</H3>\n", indent, html.EscapeString(item.folder.name))
+			writeNetscapeFolder(out, item.folder, depth+1)
+			continue
+		}
+
+		bookmark := item.bookmark
+		fmt.Fprintf(out, `%s    <DT><A HREF="%s"`, indent, html.EscapeString(bookmark.URL))
+		for _, attr := range []struct {
+			name string
+			date time.Time
+		}{
+			{"ADD_DATE", bookmark.DateAdded},
+			{"LAST_MODIFIED", bookmark.DateModified},
+			{"LAST_VISIT", bookmark.DateLastUsed},
+		} {
+			if !attr.date.IsZero() {
+				fmt.Fprintf(out, ` %s="%d"`, attr.name, attr.date.Unix())
+			}
+		}
+		fmt.Fprintf(out, ">%s</A>\n", html.EscapeString(bookmark.Title))
+	}
+	fmt.Fprintf(out, "%s</DL><p>\n", indent)
+}
+
+// ImportNetscape reads the bookmarks of a Netscape bookmark file, as written
+// by ExportNetscape, browsers and bookmarking services
+func ImportNetscape(r io.Reader) ([]browser.Bookmark, error) {
+	tokenizer := xhtml.NewTokenizer(r)
+
+	var bookmarks []browser.Bookmark
+	var isNetscape bool
+	// folders holds the name of every open <DL>, "" for lists that are not
+	// a folder (e.g. the outermost one)
+	var folders []string
+	// heading is the name of the last <H3>, which names the <DL> after it
+	var heading *string
+
+	for {
+		tokenType := tokenizer.Next()
+		switch tokenType {
+		case xhtml.ErrorToken:
+			if err := tokenizer.Err(); !errors.Is(err, io.EOF) {
+				return nil, err
+			}
+			if !isNetscape {
+				return nil, errors.New("not a Netscape bookmark file, expected <!DOCTYPE NETSCAPE-Bookmark-file-1>")
+			}
+			return bookmarks, nil
+
+		case xhtml.DoctypeToken:
+			isNetscape = strings.EqualFold(string(tokenizer.Text()), "NETSCAPE-Bookmark-file-1")
+
+		case xhtml.StartTagToken:
+			token := tokenizer.Token()
+
+			switch token.Data {
+			case "dl":
+				name := ""
+				if heading != nil {
+					name = *heading
+					heading = nil
+				}
+				folders = append(folders, name)
+
+			case "h3":
+				name := readNetscapeText(tokenizer, "h3")
+				heading = &name
+
+			case "a":
+				names := netscapeFolders(folders)
+				bookmark := browser.Bookmark{Folders: names, FolderPath: filepath.Join(names...)}
+				for _, attr := range token.Attr {
+					switch attr.Key {
+					case "href":
+						bookmark.URL = attr.Val
+					case "add_date":
+						bookmark.DateAdded = parseNetscapeDate(attr.Val)
+					case "last_modified":
+						bookmark.DateModified = parseNetscapeDate(attr.Val)
+					case "last_visit":
+						bookmark.DateLastUsed = parseNetscapeDate(attr.Val)
+					}
+				}
+				bookmark.Title = readNetscapeText(tokenizer, "a")
+
+				// <A> is also used for feeds and web slices without a HREF
+				if bookmark.URL != "" {
+					bookmarks = append(bookmarks, bookmark)
+				}
+			}
+
+		case xhtml.EndTagToken:
+			name, _ := tokenizer.TagName()
+			if string(name) == "dl" && len(folders) > 0 {
+				folders = folders[:len(folders)-1]
+			}
+		}
+	}
+}
+
+// readNetscapeText reads the text up to the closing tag
+func readNetscapeText(tokenizer *xhtml.Tokenizer, tag string) string {
+	var sb strings.Builder
+	for {
+		switch tokenizer.Next() {
+		case xhtml.ErrorToken:
+			return strings.TrimSpace(sb.String())
+		case xhtml.TextToken:
+			sb.Write(tokenizer.Text())
+		case xhtml.EndTagToken:
+			if name, _ := tokenizer.TagName(); string(name) == tag {
+				return strings.TrimSpace(sb.String())
+			}
+		}
+	}
+}
+
+// netscapeFolders returns the names of the open folders, leaving out the
+// lists that are not a folder
+func netscapeFolders(folders []string) []string {
+	var names []string
+	for _, name := range folders {
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// parseNetscapeDate parses an ADD_DATE style timestamp. These are meant to be
+// seconds since the epoch, but some tools write milliseconds or microseconds.
+func parseNetscapeDate(value string) time.Time {
+	n, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+	if err != nil || n <= 0 {
+		return time.Time{}
+	}
+
+	switch {
+	case n > 1e14:
+		return time.UnixMicro(n)
+	case n > 1e11:
+		return time.UnixMilli(n)
+	default:
+		return time.Unix(n, 0)
+	}
+}
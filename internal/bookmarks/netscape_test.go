@@ -0,0 +1,154 @@
+package bookmarks
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bradleyjkemp/cupaloy/v2"
+	"github.com/malleatus/tamjaweb/internal/browser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func netscapeBookmarks() []browser.Bookmark {
+	return []browser.Bookmark{
+		{
+			Title:      "Example Site",
+			URL:        "https://example.com",
+			DateAdded:  time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC),
+			FolderPath: "Bookmark Bar",
+		},
+		{
+			Title:        "GitHub",
+			URL:          "https://github.com",
+			DateAdded:    time.Date(2023, 2, 1, 12, 0, 0, 0, time.UTC),
+			DateLastUsed: time.Date(2024, 5, 1, 9, 30, 0, 0, time.UTC),
+			DateModified: time.Date(2024, 4, 1, 8, 0, 0, 0, time.UTC),
+			FolderPath:   filepath.Join("Bookmark Bar", "Work"),
+		},
+		{
+			Title:      "Search & <Rescue>",
+			URL:        "https://example.com/search?q=a&b=\"c\"",
+			DateAdded:  time.Date(2023, 3, 1, 12, 0, 0, 0, time.UTC),
+			FolderPath: "Bookmark Bar",
+		},
+		{
+			Title:      "Other Site",
+			URL:        "https://othersite.com",
+			DateAdded:  time.Date(2023, 4, 1, 12, 0, 0, 0, time.UTC),
+			FolderPath: "Other Bookmarks",
+		},
+		{
+			Title:     "Unfiled",
+			URL:       "https://unfiled.example.com",
+			DateAdded: time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC),
+		},
+	}
+}
+
+func TestExportNetscape(t *testing.T) {
+	var buf bytes.Buffer
+	err := ExportNetscape(&buf, netscapeBookmarks())
+	require.NoError(t, err)
+
+	cupaloy.SnapshotT(t, buf.String())
+}
+
+func TestNetscapeRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	err := ExportNetscape(&buf, netscapeBookmarks())
+	require.NoError(t, err)
+
+	imported, err := ImportNetscape(&buf)
+	require.NoError(t, err)
+
+	expected := netscapeBookmarks()
+
+	require.Len(t, imported, len(expected))
+	for i := range expected {
+		assert.Equal(t, expected[i].Title, imported[i].Title)
+		assert.Equal(t, expected[i].URL, imported[i].URL)
+		assert.Equal(t, expected[i].FolderPath, imported[i].FolderPath)
+		assert.True(t, expected[i].DateAdded.Equal(imported[i].DateAdded), "DateAdded of %s", expected[i].Title)
+		assert.True(t, expected[i].DateLastUsed.Equal(imported[i].DateLastUsed), "DateLastUsed of %s", expected[i].Title)
+		assert.True(t, expected[i].DateModified.Equal(imported[i].DateModified), "DateModified of %s", expected[i].Title)
+	}
+}
+
+func TestNetscapeRoundTripFolderWithSeparator(t *testing.T) {
+	var buf bytes.Buffer
+	err := ExportNetscape(&buf, []browser.Bookmark{
+		{Title: "Go", URL: "https://go.dev", Folders: []string{"Bookmark Bar", "Dev/Tools"}, FolderPath: filepath.Join("Bookmark Bar", "Dev/Tools")},
+	})
+	require.NoError(t, err)
+	assert.Contains(t, buf.String(), "<H3>Dev/Tools</H3>")
+
+	imported, err := ImportNetscape(&buf)
+	require.NoError(t, err)
+
+	require.Len(t, imported, 1)
+	assert.Equal(t, []string{"Bookmark Bar", "Dev/Tools"}, imported[0].Folders, "Should keep the folder as one")
+}
+
+func TestImportNetscape(t *testing.T) {
+	// as exported by Chrome, with unclosed <DT>s, descriptions and icons
+	input := `<!DOCTYPE NETSCAPE-Bookmark-file-1>
+<!-- This is an automatically generated file. -->
+<META HTTP-EQUIV="Content-Type" CONTENT="text/html; charset=UTF-8">
+<TITLE>Bookmarks</TITLE>
+<H1>Bookmarks</H1>
+<DL><p>
+    <DT><H3 ADD_DATE="1672574400" LAST_MODIFIED="1672574400" PERSONAL_TOOLBAR_FOLDER="true">Bookmarks bar</H3>
+    <DL><p>
+        <DT><A HREF="https://example.com" ADD_DATE="1672574400" ICON="data:image/png;base64,AAAA">Example &amp; Co</A>
+        <DD>A description that is not a bookmark
+        <DT><H3>Work</H3>
+        <DL><p>
+            <DT><A HREF="https://github.com" ADD_DATE="1675252800000">GitHub</A>
+        </DL><p>
+        <DT><A HREF="https://after.example.com" ADD_DATE="1675252800000000">After Work</A>
+    </DL><p>
+    <DT><A FEEDURL="https://example.com/feed">Feed without a link</A>
+    <DT><A HREF="https://top.example.com">Top</A>
+</DL><p>
+`
+
+	bookmarks, err := ImportNetscape(strings.NewReader(input))
+	require.NoError(t, err)
+
+	assert.Equal(t, []browser.Bookmark{
+		{
+			Title:      "Example & Co",
+			URL:        "https://example.com",
+			DateAdded:  time.Unix(1672574400, 0),
+			Folders:    []string{"Bookmarks bar"},
+			FolderPath: "Bookmarks bar",
+		},
+		{
+			Title:      "GitHub",
+			URL:        "https://github.com",
+			DateAdded:  time.UnixMilli(1675252800000),
+			Folders:    []string{"Bookmarks bar", "Work"},
+			FolderPath: filepath.Join("Bookmarks bar", "Work"),
+		},
+		{
+			Title:      "After Work",
+			URL:        "https://after.example.com",
+			DateAdded:  time.UnixMicro(1675252800000000),
+			Folders:    []string{"Bookmarks bar"},
+			FolderPath: "Bookmarks bar",
+		},
+		{
+			Title: "Top",
+			URL:   "https://top.example.com",
+		},
+	}, bookmarks)
+}
+
+func TestImportNetscapeRejectsOtherFiles(t *testing.T) {
+	_, err := ImportNetscape(strings.NewReader(`<!DOCTYPE html><html><body><a href="https://example.com">Example</a></body></html>`))
+	assert.EqualError(t, err, "not a Netscape bookmark file, expected <!DOCTYPE NETSCAPE-Bookmark-file-1>")
+}
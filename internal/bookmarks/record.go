@@ -0,0 +1,46 @@
+package bookmarks
+
+import (
+	"github.com/malleatus/tamjaweb/internal/output"
+)
+
+// Record is a bookmark as written by the machine readable output formats.
+// The json names are part of tamjaweb's interface and must not change.
+type Record struct {
+	Browser            string            `json:"browser" doc:"Browser the bookmark was read from"`
+	Profile            string            `json:"profile" doc:"Name of the browser profile"`
+	ID                 string            `json:"id" doc:"The browser's id for the bookmark, only unique within a profile"`
+	GUID               string            `json:"guid" doc:"Identifier that is stable across syncs, empty when the browser has none"`
+	Title              string            `json:"title"`
+	URL                string            `json:"url"`
+	Folder             string            `json:"folder" doc:"Folders the bookmark is in, starting with the root folder"`
+	DateAdded          output.Date       `json:"date_added"`
+	DateLastUsed       output.Date       `json:"date_last_used" doc:"When the bookmark was last opened, null if never or not tracked"`
+	DateModified       output.Date       `json:"date_modified" doc:"When the bookmark last changed, null if not recorded"`
+	FolderDateModified output.Date       `json:"folder_date_modified" doc:"When the folder the bookmark is in last changed, null if not recorded"`
+	MetaInfo           map[string]string `json:"meta_info" doc:"Free form metadata attached by the browser or extensions"`
+	Score              int               `json:"score" doc:"The fzf score of the match when searching, higher is better, 0 otherwise"`
+}
+
+// Records converts bookmark entries to Records, keeping their order
+func Records(entries []Entry) []Record {
+	var records []Record
+	for _, entry := range entries {
+		records = append(records, Record{
+			Browser:            entry.Browser,
+			Profile:            entry.Profile,
+			ID:                 entry.ID,
+			GUID:               entry.GUID,
+			Title:              entry.Title,
+			URL:                entry.URL,
+			Folder:             entry.FolderPath,
+			DateAdded:          output.NewDate(entry.DateAdded),
+			DateLastUsed:       output.NewDate(entry.DateLastUsed),
+			DateModified:       output.NewDate(entry.DateModified),
+			FolderDateModified: output.NewDate(entry.FolderDateModified),
+			MetaInfo:           entry.MetaInfo,
+			Score:              entry.Score,
+		})
+	}
+	return records
+}
@@ -0,0 +1,108 @@
+package bookmarks
+
+import (
+	"cmp"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/malleatus/tamjaweb/internal/browser"
+	"github.com/malleatus/tamjaweb/internal/item"
+	"github.com/malleatus/tamjaweb/internal/output"
+)
+
+// Sorts lists the orders bookmarks can be sorted in
+var Sorts = []string{
+	output.SortRelevance,
+	output.SortTitle,
+	output.SortURL,
+	output.SortDateAdded,
+	output.SortLastUsed,
+	output.SortFolder,
+	output.SortBrowser,
+}
+
+// Entry is a bookmark together with the browser it was read from
+type Entry struct {
+	Browser string
+	// Score is fzf's score for the search term, see fzf.Match. It is 0
+	// when not searching.
+	Score int
+	browser.Bookmark
+}
+
+// Entries flattens bookmarks into a single list, ordered by browser name and
+// then in each browser's own order
+func Entries(bookmarks map[string][]browser.Bookmark) []Entry {
+	var entries []Entry
+	for _, browserName := range slices.Sorted(maps.Keys(bookmarks)) {
+		for _, bookmark := range bookmarks[browserName] {
+			entries = append(entries, Entry{Browser: browserName, Bookmark: bookmark})
+		}
+	}
+	return entries
+}
+
+// Item adapts the bookmark for searches across sources
+func (e Entry) Item() item.Item {
+	return item.Item{
+		Source:  item.SourceBookmarks,
+		Browser: e.Browser,
+		Profile: e.Profile,
+		Title:   e.Title,
+		URL:     e.URL,
+		Folder:  e.FolderPath,
+		Date:    e.DateAdded,
+		Details: []item.Detail{
+			{Label: "Last used", Value: formatDate(e.DateLastUsed)},
+		},
+		Score: e.Score,
+	}
+}
+
+// Items adapts entries for searches across sources, keeping their order
+func Items(entries []Entry) []item.Item {
+	items := make([]item.Item, 0, len(entries))
+	for _, entry := range entries {
+		items = append(items, entry.Item())
+	}
+	return items
+}
+
+// SortEntries orders entries in place, see output.Sort
+func SortEntries(entries []Entry, opts output.SortOptions) error {
+	if err := opts.Validate(Sorts); err != nil {
+		return err
+	}
+
+	var compare func(a, b Entry) int
+	var missing func(Entry) bool
+	switch opts.By {
+	case output.SortRelevance:
+		compare = func(a, b Entry) int { return cmp.Compare(b.Score, a.Score) }
+	case output.SortTitle:
+		compare = func(a, b Entry) int {
+			return cmp.Or(output.CompareText(a.Title, b.Title), strings.Compare(a.URL, b.URL))
+		}
+	case output.SortURL:
+		compare = func(a, b Entry) int { return strings.Compare(a.URL, b.URL) }
+	case output.SortDateAdded:
+		compare = func(a, b Entry) int { return b.DateAdded.Compare(a.DateAdded) }
+		missing = func(e Entry) bool { return e.DateAdded.IsZero() }
+	case output.SortLastUsed:
+		compare = func(a, b Entry) int { return b.DateLastUsed.Compare(a.DateLastUsed) }
+		missing = func(e Entry) bool { return e.DateLastUsed.IsZero() }
+	case output.SortFolder:
+		compare = func(a, b Entry) int {
+			return cmp.Or(output.CompareText(a.FolderPath, b.FolderPath), output.CompareText(a.Title, b.Title))
+		}
+	case output.SortBrowser:
+		compare = func(a, b Entry) int {
+			return cmp.Or(strings.Compare(a.Browser, b.Browser), strings.Compare(a.Profile, b.Profile))
+		}
+	}
+
+	output.Sort(entries, opts.Reverse, compare, missing)
+
+	return nil
+}
@@ -0,0 +1,118 @@
+package bookmarks
+
+import (
+	"testing"
+	"time"
+
+	"github.com/malleatus/tamjaweb/internal/browser"
+	"github.com/malleatus/tamjaweb/internal/item"
+	"github.com/malleatus/tamjaweb/internal/output"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sortTestEntries() []Entry {
+	fixedTime := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	return []Entry{
+		{Browser: "Firefox", Score: 10, Bookmark: browser.Bookmark{Title: "beta", URL: "https://b.example.com", FolderPath: "toolbar", Profile: "default", DateAdded: fixedTime, DateLastUsed: fixedTime}},
+		{Browser: "Chrome", Score: 30, Bookmark: browser.Bookmark{Title: "Alpha", URL: "https://c.example.com", FolderPath: "Bookmark Bar/Work", Profile: "Work", DateAdded: fixedTime.AddDate(1, 0, 0)}},
+		{Browser: "Chrome", Score: 20, Bookmark: browser.Bookmark{Title: "gamma", URL: "https://a.example.com", FolderPath: "Bookmark Bar", Profile: "Default", DateLastUsed: fixedTime.AddDate(-1, 0, 0)}},
+	}
+}
+
+func TestSortEntries(t *testing.T) {
+	testCases := []struct {
+		sort     output.SortOptions
+		expected []string
+	}{
+		{output.SortOptions{By: output.SortRelevance}, []string{"Alpha", "gamma", "beta"}},
+		{output.SortOptions{By: output.SortRelevance, Reverse: true}, []string{"beta", "gamma", "Alpha"}},
+		{output.SortOptions{By: output.SortTitle}, []string{"Alpha", "beta", "gamma"}},
+		{output.SortOptions{By: output.SortTitle, Reverse: true}, []string{"gamma", "beta", "Alpha"}},
+		{output.SortOptions{By: output.SortURL}, []string{"gamma", "beta", "Alpha"}},
+		{output.SortOptions{By: output.SortDateAdded}, []string{"Alpha", "beta", "gamma"}},
+		{output.SortOptions{By: output.SortDateAdded, Reverse: true}, []string{"beta", "Alpha", "gamma"}},
+		{output.SortOptions{By: output.SortLastUsed}, []string{"beta", "gamma", "Alpha"}},
+		{output.SortOptions{By: output.SortLastUsed, Reverse: true}, []string{"gamma", "beta", "Alpha"}},
+		{output.SortOptions{By: output.SortFolder}, []string{"gamma", "Alpha", "beta"}},
+		{output.SortOptions{By: output.SortBrowser}, []string{"gamma", "Alpha", "beta"}},
+	}
+
+	for _, tc := range testCases {
+		name := tc.sort.By
+		if tc.sort.Reverse {
+			name += " reversed"
+		}
+
+		t.Run(name, func(t *testing.T) {
+			entries := sortTestEntries()
+			err := SortEntries(entries, tc.sort)
+			require.NoError(t, err)
+
+			var titles []string
+			for _, entry := range entries {
+				titles = append(titles, entry.Title)
+			}
+			assert.Equal(t, tc.expected, titles)
+		})
+	}
+}
+
+func TestSortEntriesInvalid(t *testing.T) {
+	err := SortEntries(sortTestEntries(), output.SortOptions{By: "popularity"})
+	assert.EqualError(t, err, `invalid sort "popularity", expected one of relevance, title, url, date-added, last-used, folder, browser`)
+}
+
+func TestEntries(t *testing.T) {
+	bookmarks := map[string][]browser.Bookmark{
+		"Safari":  {{Title: "Safari 1"}, {Title: "Safari 2"}},
+		"Chrome":  {{Title: "Chrome 1"}, {Title: "Chrome 2"}},
+		"Firefox": {{Title: "Firefox 1"}},
+	}
+
+	// map iteration order is random, so check that it never leaks through
+	for range 10 {
+		var titles []string
+		for _, entry := range Entries(bookmarks) {
+			titles = append(titles, entry.Browser+": "+entry.Title)
+		}
+		assert.Equal(t, []string{
+			"Chrome: Chrome 1",
+			"Chrome: Chrome 2",
+			"Firefox: Firefox 1",
+			"Safari: Safari 1",
+			"Safari: Safari 2",
+		}, titles)
+	}
+}
+
+func TestFilterBookmarksByTermScores(t *testing.T) {
+	entries := Entries(map[string][]browser.Bookmark{
+		"Chrome": {
+			{Title: "Go Documentation", URL: "https://go.dev/doc"},
+			{Title: "Golang Weekly", URL: "https://golangweekly.com"},
+			{Title: "Unrelated", URL: "https://example.com"},
+		},
+	})
+
+	matches := FilterBookmarksByTerm(entries, "golang", item.SearchOptions{})
+
+	require.Len(t, matches, 1)
+	assert.Equal(t, "Golang Weekly", matches[0].Title)
+	assert.Positive(t, matches[0].Score)
+
+	matches = FilterBookmarksByTerm(entries, "go", item.SearchOptions{})
+
+	require.Len(t, matches, 2)
+	for i, match := range matches {
+		assert.Positive(t, match.Score, "Should score %s", match.Title)
+		if i > 0 {
+			assert.LessOrEqual(t, match.Score, matches[i-1].Score, "Should order matches best first")
+		}
+	}
+
+	for _, entry := range FilterBookmarksByTerm(entries, "", item.SearchOptions{}) {
+		assert.Zero(t, entry.Score, "Should not score without a term")
+	}
+}
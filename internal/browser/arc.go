@@ -0,0 +1,25 @@
+package browser
+
+import (
+	"github.com/malleatus/tamjaweb/internal/logger"
+)
+
+var arcLogger = logger.New("browser:arc")
+
+// Arc is not available on Linux. On Windows it is installed as an MSIX
+// package, so its user data lives inside the package's local cache.
+var arcVendor = chromiumVendor{
+	name:    "Arc",
+	windows: []string{"Packages", "TheBrowserCompany.Arc_ttt1ap7aakyb4", "LocalCache", "Local", "Arc", "User Data"},
+	darwin:  []string{"Arc", "User Data"},
+}
+
+func init() {
+	arcLogger.Info("Registering Arc browser")
+	RegisterBrowser(NewArc())
+}
+
+// NewArc creates a new Arc browser instance with the default path provider
+func NewArc() *Chromium {
+	return newChromium(arcVendor)
+}
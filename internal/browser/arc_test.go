@@ -0,0 +1,64 @@
+package browser
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetArcBookmarksPathForPlatform(t *testing.T) {
+	testCases := []struct {
+		name        string
+		dirs        platformDirs
+		profile     string
+		expected    string
+		expectError bool
+	}{
+		{
+			name:     "Windows path",
+			dirs:     platformDirs{goos: "windows", homeDir: `C:\Users\testuser`, localAppData: `C:\Users\testuser\AppData\Local`},
+			profile:  "Default",
+			expected: filepath.Join(`C:\Users\testuser\AppData\Local`, "Packages", "TheBrowserCompany.Arc_ttt1ap7aakyb4", "LocalCache", "Local", "Arc", "User Data", "Default", "Bookmarks"),
+		},
+		{
+			name:     "macOS path",
+			dirs:     platformDirs{goos: "darwin", homeDir: "/Users/testuser"},
+			profile:  "Default",
+			expected: filepath.Join("/Users/testuser", "Library", "Application Support", "Arc", "User Data", "Default", "Bookmarks"),
+		},
+		{
+			name:        "Linux is not available",
+			dirs:        platformDirs{goos: "linux", homeDir: "/home/testuser"},
+			profile:     "Default",
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			path, err := arcVendor.bookmarksPathForPlatform(tc.dirs, tc.profile)
+
+			if tc.expectError {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), "Arc is not available on linux")
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expected, path)
+			}
+		})
+	}
+}
+
+func TestGetArcBookmarksWithFixture(t *testing.T) {
+	dirs := platformDirs{goos: "darwin", homeDir: t.TempDir()}
+	bookmarksFile := writeBookmarksFixture(t, arcVendor, dirs, "Default")
+
+	bookmarks, err := newTestChromium(arcVendor, bookmarksFile).GetBookmarks(context.Background(), "Default")
+	require.NoError(t, err)
+
+	assert.Equal(t, "Arc", NewArc().Name())
+	assertSampleBookmarks(t, bookmarks)
+}
@@ -1,21 +1,69 @@
 package browser
 
 import (
+	"context"
+	"path/filepath"
+	"strings"
 	"time"
+
+	"github.com/malleatus/tamjaweb/internal/logger"
 )
 
+var browserLogger = logger.New("browser")
+
 // Bookmark represents a browser bookmark
 type Bookmark struct {
-	Title      string
-	URL        string
-	DateAdded  time.Time
+	// ID is the browser's own identifier for the bookmark, only unique within
+	// a single profile
+	ID string
+	// GUID identifies the bookmark across syncs and is stable between runs,
+	// empty when the browser doesn't record one
+	GUID      string
+	Title     string
+	URL       string
+	DateAdded time.Time
+	// DateLastUsed is when the bookmark was last opened, zero if never (or if
+	// the browser doesn't track it)
+	DateLastUsed time.Time
+	// DateModified is when the bookmark was last changed, zero if the
+	// browser didn't record it. Chromium rarely does.
+	DateModified time.Time
+	// FolderDateModified is when the folder holding the bookmark last
+	// changed, e.g. a bookmark was added to or removed from it. It is zero if
+	// the browser didn't record it.
+	FolderDateModified time.Time
+	// Folders are the names of the folders holding the bookmark, outermost
+	// first. FolderPath joins them with the path separator, so it can't tell
+	// a folder named "A/B" from B inside A.
+	Folders    []string
 	FolderPath string
+	Profile    string
+	// MetaInfo holds the free form metadata some browsers (and extensions)
+	// attach to bookmarks
+	MetaInfo map[string]string
+}
+
+// FolderNames returns Folders, or splits FolderPath for bookmarks that only
+// set that
+func (b Bookmark) FolderNames() []string {
+	if b.Folders != nil {
+		return b.Folders
+	}
+	return SplitFolderPath(b.FolderPath)
+}
+
+// SplitFolderPath splits a folder path, e.g. given on the command line, into
+// its folder names. Both / and the path separator separate folders.
+func SplitFolderPath(folderPath string) []string {
+	return strings.FieldsFunc(folderPath, func(r rune) bool {
+		return r == '/' || r == filepath.Separator
+	})
 }
 
 // Browser defines methods that all browser implementations must provide
 type Browser interface {
 	Name() string
-	GetBookmarks(profile string) ([]Bookmark, error)
+	GetBookmarks(ctx context.Context, profile string) ([]Bookmark, error)
 }
 
 // RegisteredBrowsers is a slice of all available browser implementations
@@ -26,17 +74,27 @@ func RegisterBrowser(b Browser) {
 	RegisteredBrowsers = append(RegisteredBrowsers, b)
 }
 
-// GetAllBookmarks returns bookmarks from all registered browsers
-func GetAllBookmarks(profile string) (map[string][]Bookmark, error) {
-	result := make(map[string][]Bookmark)
-
+// FindBrowser returns the registered browser with the given name, ignoring case
+func FindBrowser(name string) (Browser, bool) {
 	for _, browser := range RegisteredBrowsers {
-		bookmarks, err := browser.GetBookmarks(profile)
-		if err != nil {
-			continue // Skip this browser if there's an error
+		if strings.EqualFold(browser.Name(), name) {
+			return browser, true
 		}
-		result[browser.Name()] = bookmarks
 	}
 
-	return result, nil
+	return nil, false
+}
+
+// GetAllBookmarks returns bookmarks from all registered browsers. profile may be
+// a profile's name, its directory or AllProfiles.
+//
+// Browsers (and their profiles) are loaded concurrently, see LoadWorkers and
+// LoadTimeout. Browsers that are not installed are skipped. Any other failure,
+// including timing out, is returned as BrowserErrors alongside the bookmarks
+// that could be loaded. Both are ordered by RegisteredBrowsers and then by
+// profile, regardless of which finished first.
+func GetAllBookmarks(ctx context.Context, profile string) (map[string][]Bookmark, error) {
+	return loadAll(ctx, RegisteredBrowsers, profile, func(ctx context.Context, browser Browser, profile string) ([]Bookmark, error) {
+		return browser.GetBookmarks(ctx, profile)
+	})
 }
@@ -0,0 +1,114 @@
+package browser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeBrowser is a Browser that returns canned bookmarks and errors
+type fakeBrowser struct {
+	name      string
+	bookmarks []Bookmark
+	err       error
+}
+
+func (f *fakeBrowser) Name() string {
+	return f.name
+}
+
+func (f *fakeBrowser) GetBookmarks(ctx context.Context, profile string) ([]Bookmark, error) {
+	return f.bookmarks, f.err
+}
+
+// withRegisteredBrowsers replaces RegisteredBrowsers for the duration of the test
+func withRegisteredBrowsers(t *testing.T, browsers ...Browser) {
+	t.Helper()
+
+	original := RegisteredBrowsers
+	RegisteredBrowsers = browsers
+	t.Cleanup(func() {
+		RegisteredBrowsers = original
+	})
+}
+
+func TestGetAllBookmarksSkipsNotInstalledBrowsers(t *testing.T) {
+	withRegisteredBrowsers(t,
+		&fakeBrowser{name: "Installed", bookmarks: []Bookmark{{Title: "Example", URL: "https://example.com"}}},
+		&fakeBrowser{name: "Missing", err: fmt.Errorf("bookmarks not found at /nowhere: %w", ErrNotInstalled)},
+	)
+
+	bookmarks, err := GetAllBookmarks(context.Background(), "Default")
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string][]Bookmark{
+		"Installed": {{Title: "Example", URL: "https://example.com"}},
+	}, bookmarks)
+}
+
+func TestGetAllBookmarksReportsBrokenBrowsers(t *testing.T) {
+	corruptFile := filepath.Join(t.TempDir(), "Bookmarks")
+	err := os.WriteFile(corruptFile, []byte("{not json"), 0644)
+	require.NoError(t, err)
+
+	withRegisteredBrowsers(t,
+		&fakeBrowser{name: "Installed", bookmarks: []Bookmark{{Title: "Example", URL: "https://example.com"}}},
+		newTestChromium(chromeVendor, corruptFile),
+		&fakeBrowser{name: "Unreadable", err: errors.New("permission denied")},
+	)
+
+	bookmarks, err := GetAllBookmarks(context.Background(), "Default")
+	require.Error(t, err)
+
+	assert.Equal(t, map[string][]Bookmark{
+		"Installed": {{Title: "Example", URL: "https://example.com"}},
+	}, bookmarks, "Should still return bookmarks from working browsers")
+
+	var browserErrs BrowserErrors
+	require.ErrorAs(t, err, &browserErrs)
+	require.Equal(t, 2, len(browserErrs))
+
+	assert.Equal(t, "Chrome", browserErrs[0].Browser)
+	assert.Equal(t, "Default", browserErrs[0].Profile)
+	assert.Equal(t, corruptFile, browserErrs[0].Path)
+	assert.Contains(t, browserErrs[0].Error(), "Chrome (Default): "+corruptFile+": ")
+
+	assert.Equal(t, "Unreadable", browserErrs[1].Browser)
+	assert.Equal(t, "Default", browserErrs[1].Profile)
+	assert.Empty(t, browserErrs[1].Path)
+	assert.Equal(t, "Unreadable (Default): permission denied", browserErrs[1].Error())
+
+	assert.False(t, errors.Is(err, ErrNotInstalled))
+}
+
+func TestGetAllBookmarksKeepsPartialProfileResults(t *testing.T) {
+	dirs := platformDirs{goos: "linux", homeDir: t.TempDir()}
+	writeLocalStateFixture(t, chromiumBrowserVendor, dirs)
+	writeBookmarksFixture(t, chromiumBrowserVendor, dirs, "Default")
+
+	// a corrupt file for the Work profile
+	workPath, err := chromiumBrowserVendor.bookmarksPathForPlatform(dirs, "Profile 3")
+	require.NoError(t, err)
+	err = os.MkdirAll(filepath.Dir(workPath), 0755)
+	require.NoError(t, err)
+	err = os.WriteFile(workPath, []byte("[]"), 0644)
+	require.NoError(t, err)
+
+	withRegisteredBrowsers(t, newFixtureChromium(chromiumBrowserVendor, dirs))
+
+	bookmarks, err := GetAllBookmarks(context.Background(), AllProfiles)
+
+	assertSampleBookmarks(t, bookmarks["Chromium"])
+
+	var browserErrs BrowserErrors
+	require.ErrorAs(t, err, &browserErrs)
+	require.Equal(t, 1, len(browserErrs), "Side Project has no Bookmarks file, which is not a failure")
+	assert.Equal(t, "Work", browserErrs[0].Profile)
+	assert.Equal(t, workPath, browserErrs[0].Path)
+}
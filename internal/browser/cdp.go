@@ -0,0 +1,318 @@
+package browser
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/malleatus/tamjaweb/internal/logger"
+)
+
+var cdpLogger = logger.New("browser:cdp")
+
+// DefaultCDPURL is where a browser started with --remote-debugging-port=9222
+// serves the DevTools protocol
+const DefaultCDPURL = "http://127.0.0.1:9222"
+
+// CDP reads and controls the live tabs of a browser through the Chrome
+// DevTools Protocol. Unlike the other tab sources it needs the browser to be
+// running with --remote-debugging-port, so it is not registered and has to
+// be asked for explicitly.
+type CDP struct {
+	baseURL    string
+	httpClient *http.Client
+	dialer     *websocket.Dialer
+	nextID     atomic.Int64
+}
+
+// NewCDP creates a CDP client for the browser serving the protocol at baseURL
+// (e.g. DefaultCDPURL)
+func NewCDP(baseURL string) *CDP {
+	return &CDP{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		httpClient: http.DefaultClient,
+		dialer:     websocket.DefaultDialer,
+	}
+}
+
+func (c *CDP) Name() string {
+	return "CDP"
+}
+
+// cdpVersion is the response of /json/version
+type cdpVersion struct {
+	Browser              string `json:"Browser"`
+	WebSocketDebuggerURL string `json:"webSocketDebuggerUrl"`
+}
+
+// cdpTarget is an entry of /json/list
+type cdpTarget struct {
+	ID    string `json:"id"`
+	Type  string `json:"type"`
+	Title string `json:"title"`
+	URL   string `json:"url"`
+}
+
+// BrowserName returns the product name of the browser, e.g. "Chrome"
+func (c *CDP) BrowserName(ctx context.Context) (string, error) {
+	var version cdpVersion
+	if err := c.getJSON(ctx, "/json/version", &version); err != nil {
+		return "", err
+	}
+
+	name, _, _ := strings.Cut(version.Browser, "/")
+	if name == "" {
+		return c.Name(), nil
+	}
+	// e.g. "HeadlessChrome" and "Chrome" are the same browser for our purposes
+	return strings.TrimPrefix(name, "Headless"), nil
+}
+
+// GetTabs returns the pages open in the browser, ordered by window. profile is
+// ignored, the protocol only sees the profile the browser was started with.
+func (c *CDP) GetTabs(ctx context.Context, profile string) ([]Tab, error) {
+	var targets []cdpTarget
+	if err := c.getJSON(ctx, "/json/list", &targets); err != nil {
+		return nil, err
+	}
+
+	var pages []cdpTarget
+	for _, target := range targets {
+		if target.Type == "page" {
+			pages = append(pages, target)
+		}
+	}
+
+	windows := c.windowsForTargets(ctx, pages)
+
+	// number windows by their id, the order /json/list returns pages in
+	// changes as tabs are activated
+	var windowIDs []int
+	seen := map[int]bool{}
+	for _, page := range pages {
+		if id := windows[page.ID]; !seen[id] {
+			seen[id] = true
+			windowIDs = append(windowIDs, id)
+		}
+	}
+	sort.Ints(windowIDs)
+	windowNumbers := make(map[int]int, len(windowIDs))
+	for i, id := range windowIDs {
+		windowNumbers[id] = i + 1
+	}
+
+	tabs := make([]Tab, 0, len(pages))
+	indexes := map[int]int{}
+	for _, page := range pages {
+		window := windowNumbers[windows[page.ID]]
+		tabs = append(tabs, Tab{
+			ID:     page.ID,
+			Window: window,
+			Index:  indexes[window],
+			Title:  page.Title,
+			URL:    page.URL,
+		})
+		indexes[window]++
+	}
+
+	sort.SliceStable(tabs, func(i, j int) bool {
+		return tabs[i].Window < tabs[j].Window
+	})
+
+	return tabs, nil
+}
+
+// windowsForTargets looks up the window of each target. Windows are only
+// used to group tabs, so failures put every tab in the same window.
+func (c *CDP) windowsForTargets(ctx context.Context, targets []cdpTarget) map[string]int {
+	windows := make(map[string]int, len(targets))
+	if len(targets) == 0 {
+		return windows
+	}
+
+	conn, err := c.dial(ctx)
+	if err != nil {
+		cdpLogger.Debug("Failed to connect to look up windows", "error", err)
+		return windows
+	}
+	defer conn.close()
+
+	for _, target := range targets {
+		var result struct {
+			WindowID int `json:"windowId"`
+		}
+		if err := conn.call(ctx, "Browser.getWindowForTarget", map[string]string{"targetId": target.ID}, &result); err != nil {
+			cdpLogger.Debug("Failed to get window for target", "target", target.ID, "error", err)
+			continue
+		}
+		windows[target.ID] = result.WindowID
+	}
+
+	return windows
+}
+
+// ActivateTab brings the tab with the given id to the front
+func (c *CDP) ActivateTab(ctx context.Context, id string) error {
+	return c.call(ctx, "Target.activateTarget", map[string]string{"targetId": id}, nil)
+}
+
+// CloseTab closes the tab with the given id
+func (c *CDP) CloseTab(ctx context.Context, id string) error {
+	var result struct {
+		Success *bool `json:"success"`
+	}
+	if err := c.call(ctx, "Target.closeTarget", map[string]string{"targetId": id}, &result); err != nil {
+		return err
+	}
+
+	// older versions report failures through success instead of an error
+	if result.Success != nil && !*result.Success {
+		return fmt.Errorf("failed to close tab %s", id)
+	}
+
+	return nil
+}
+
+// OpenTab opens url in a new tab, returning the new tab's id
+func (c *CDP) OpenTab(ctx context.Context, url string) (string, error) {
+	var result struct {
+		TargetID string `json:"targetId"`
+	}
+	if err := c.call(ctx, "Target.createTarget", map[string]string{"url": url}, &result); err != nil {
+		return "", err
+	}
+
+	return result.TargetID, nil
+}
+
+// call runs a single command on a new connection to the browser
+func (c *CDP) call(ctx context.Context, method string, params any, result any) error {
+	conn, err := c.dial(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.close()
+
+	return conn.call(ctx, method, params, result)
+}
+
+// getJSON fetches one of the HTTP endpoints of the protocol
+func (c *CDP) getJSON(ctx context.Context, path string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+path, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach the DevTools protocol at %s, is the browser running with --remote-debugging-port? %w", c.baseURL, err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: unexpected status %s", path, resp.Status)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("GET %s: failed to decode response: %w", path, err)
+	}
+
+	return nil
+}
+
+// cdpConn is a websocket connection to the browser target
+type cdpConn struct {
+	cdp  *CDP
+	conn *websocket.Conn
+}
+
+// cdpMessage is a command sent to the browser, or its response. Events are
+// messages without an id and are skipped.
+type cdpMessage struct {
+	ID     int64           `json:"id,omitempty"`
+	Method string          `json:"method,omitempty"`
+	Params any             `json:"params,omitempty"`
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+// dial connects to the browser target, found through /json/version
+func (c *CDP) dial(ctx context.Context) (*cdpConn, error) {
+	var version cdpVersion
+	if err := c.getJSON(ctx, "/json/version", &version); err != nil {
+		return nil, err
+	}
+	if version.WebSocketDebuggerURL == "" {
+		return nil, errors.New("browser did not report a webSocketDebuggerUrl")
+	}
+
+	conn, _, err := c.dialer.DialContext(ctx, version.WebSocketDebuggerURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", version.WebSocketDebuggerURL, err)
+	}
+
+	return &cdpConn{cdp: c, conn: conn}, nil
+}
+
+func (c *cdpConn) close() {
+	_ = c.conn.Close()
+}
+
+// call sends a command and waits for its response, decoding the result into
+// result when it is not nil
+func (c *cdpConn) call(ctx context.Context, method string, params any, result any) error {
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = c.conn.SetReadDeadline(deadline)
+		_ = c.conn.SetWriteDeadline(deadline)
+	}
+
+	// unblock reads when ctx is cancelled without a deadline
+	stop := context.AfterFunc(ctx, func() {
+		_ = c.conn.Close()
+	})
+	defer stop()
+
+	id := c.cdp.nextID.Add(1)
+	if err := c.conn.WriteJSON(cdpMessage{ID: id, Method: method, Params: params}); err != nil {
+		return fmt.Errorf("%s: %w", method, err)
+	}
+
+	for {
+		var response cdpMessage
+		if err := c.conn.ReadJSON(&response); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			// the read deadline can fire just before ctx notices
+			if deadline, ok := ctx.Deadline(); ok && !time.Now().Before(deadline) {
+				return context.DeadlineExceeded
+			}
+			return fmt.Errorf("%s: %w", method, err)
+		}
+
+		if response.ID != id {
+			continue
+		}
+
+		if response.Error != nil {
+			return fmt.Errorf("%s: %s (%d)", method, response.Error.Message, response.Error.Code)
+		}
+
+		if result == nil || len(response.Result) == 0 {
+			return nil
+		}
+		return json.Unmarshal(response.Result, result)
+	}
+}
@@ -0,0 +1,247 @@
+package browser
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeDevTools is an httptest stand-in for a browser started with
+// --remote-debugging-port, serving the JSON endpoints and answering protocol
+// commands over a websocket
+type fakeDevTools struct {
+	server  *httptest.Server
+	targets []cdpTarget
+	// windows maps target ids to window ids
+	windows map[string]int
+	// errors makes the named methods fail
+	errors map[string]string
+
+	mu    sync.Mutex
+	calls []string
+}
+
+func newFakeDevTools(t *testing.T) *fakeDevTools {
+	t.Helper()
+
+	f := &fakeDevTools{
+		targets: []cdpTarget{
+			{ID: "PAGE2", Type: "page", Title: "Example", URL: "https://example.com/"},
+			{ID: "WORKER1", Type: "service_worker", Title: "Service Worker", URL: "https://example.com/sw.js"},
+			{ID: "PAGE1", Type: "page", Title: "GitHub", URL: "https://github.com/"},
+			{ID: "PAGE3", Type: "page", Title: "Go", URL: "https://go.dev/"},
+		},
+		windows: map[string]int{"PAGE1": 7, "PAGE2": 7, "PAGE3": 3},
+		errors:  map[string]string{},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/json/version", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(cdpVersion{
+			Browser:              "HeadlessChrome/126.0.6478.126",
+			WebSocketDebuggerURL: "ws" + strings.TrimPrefix(f.server.URL, "http") + "/devtools/browser/fake",
+		})
+	})
+	mux.HandleFunc("/json/list", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		_ = json.NewEncoder(w).Encode(f.targets)
+	})
+	mux.HandleFunc("/devtools/browser/fake", f.serveWebsocket)
+
+	f.server = httptest.NewServer(mux)
+	t.Cleanup(f.server.Close)
+
+	return f
+}
+
+func (f *fakeDevTools) serveWebsocket(w http.ResponseWriter, r *http.Request) {
+	conn, err := (&websocket.Upgrader{}).Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	for {
+		var request struct {
+			ID     int64             `json:"id"`
+			Method string            `json:"method"`
+			Params map[string]string `json:"params"`
+		}
+		if err := conn.ReadJSON(&request); err != nil {
+			return
+		}
+
+		// events are interleaved with responses by real browsers
+		_ = conn.WriteJSON(map[string]any{"method": "Target.targetInfoChanged", "params": map[string]any{}})
+
+		response := map[string]any{"id": request.ID}
+		if message, ok := f.errors[request.Method]; ok {
+			response["error"] = map[string]any{"code": -32000, "message": message}
+		} else {
+			response["result"] = f.handle(request.Method, request.Params)
+		}
+
+		if err := conn.WriteJSON(response); err != nil {
+			return
+		}
+	}
+}
+
+func (f *fakeDevTools) handle(method string, params map[string]string) map[string]any {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.calls = append(f.calls, method+" "+params["targetId"]+params["url"])
+
+	switch method {
+	case "Browser.getWindowForTarget":
+		return map[string]any{"windowId": f.windows[params["targetId"]]}
+	case "Target.closeTarget":
+		for i, target := range f.targets {
+			if target.ID == params["targetId"] {
+				f.targets = append(f.targets[:i], f.targets[i+1:]...)
+				return map[string]any{"success": true}
+			}
+		}
+		return map[string]any{"success": false}
+	case "Target.createTarget":
+		f.targets = append(f.targets, cdpTarget{ID: "NEW1", Type: "page", URL: params["url"]})
+		return map[string]any{"targetId": "NEW1"}
+	default:
+		return map[string]any{}
+	}
+}
+
+func (f *fakeDevTools) recordedCalls() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.calls...)
+}
+
+func TestCDPGetTabs(t *testing.T) {
+	devTools := newFakeDevTools(t)
+	cdp := NewCDP(devTools.server.URL)
+
+	tabs, err := cdp.GetTabs(context.Background(), "Default")
+	require.NoError(t, err)
+
+	assert.Equal(t, []Tab{
+		{ID: "PAGE3", Window: 1, Index: 0, Title: "Go", URL: "https://go.dev/"},
+		{ID: "PAGE2", Window: 2, Index: 0, Title: "Example", URL: "https://example.com/"},
+		{ID: "PAGE1", Window: 2, Index: 1, Title: "GitHub", URL: "https://github.com/"},
+	}, tabs, "Should only list pages, grouped by window")
+}
+
+func TestCDPGetTabsWithoutWindows(t *testing.T) {
+	devTools := newFakeDevTools(t)
+	devTools.errors["Browser.getWindowForTarget"] = "Browser window not found"
+	cdp := NewCDP(devTools.server.URL)
+
+	tabs, err := cdp.GetTabs(context.Background(), "Default")
+	require.NoError(t, err)
+
+	require.Equal(t, 3, len(tabs))
+	for _, tab := range tabs {
+		assert.Equal(t, 1, tab.Window, "Should fall back to a single window")
+	}
+}
+
+func TestCDPBrowserName(t *testing.T) {
+	devTools := newFakeDevTools(t)
+
+	name, err := NewCDP(devTools.server.URL + "/").BrowserName(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, "Chrome", name)
+}
+
+func TestCDPTabActions(t *testing.T) {
+	devTools := newFakeDevTools(t)
+	cdp := NewCDP(devTools.server.URL)
+	ctx := context.Background()
+
+	err := cdp.ActivateTab(ctx, "PAGE1")
+	require.NoError(t, err)
+
+	err = cdp.CloseTab(ctx, "PAGE2")
+	require.NoError(t, err)
+
+	id, err := cdp.OpenTab(ctx, "https://news.example.com/")
+	require.NoError(t, err)
+	assert.Equal(t, "NEW1", id)
+
+	assert.Equal(t, []string{
+		"Target.activateTarget PAGE1",
+		"Target.closeTarget PAGE2",
+		"Target.createTarget https://news.example.com/",
+	}, devTools.recordedCalls())
+
+	tabs, err := cdp.GetTabs(ctx, "Default")
+	require.NoError(t, err)
+
+	var ids []string
+	for _, tab := range tabs {
+		ids = append(ids, tab.ID)
+	}
+	assert.ElementsMatch(t, []string{"PAGE1", "PAGE3", "NEW1"}, ids)
+}
+
+func TestCDPCloseUnknownTab(t *testing.T) {
+	devTools := newFakeDevTools(t)
+
+	err := NewCDP(devTools.server.URL).CloseTab(context.Background(), "MISSING")
+	assert.ErrorContains(t, err, "failed to close tab MISSING")
+}
+
+func TestCDPProtocolError(t *testing.T) {
+	devTools := newFakeDevTools(t)
+	devTools.errors["Target.activateTarget"] = "No target with given id found"
+
+	err := NewCDP(devTools.server.URL).ActivateTab(context.Background(), "MISSING")
+	assert.EqualError(t, err, "Target.activateTarget: No target with given id found (-32000)")
+}
+
+func TestCDPNotRunning(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	url := server.URL
+	server.Close()
+
+	_, err := NewCDP(url).GetTabs(context.Background(), "Default")
+	assert.ErrorContains(t, err, "is the browser running with --remote-debugging-port?")
+}
+
+func TestCDPCallCancelled(t *testing.T) {
+	devTools := newFakeDevTools(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	// a browser that accepts the connection but never answers
+	devTools.server.Config.Handler.(*http.ServeMux).HandleFunc("/devtools/browser/hang", func(w http.ResponseWriter, r *http.Request) {
+		conn, err := (&websocket.Upgrader{}).Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		<-r.Context().Done()
+		_ = conn.Close()
+	})
+
+	cdp := NewCDP(devTools.server.URL)
+	conn, _, err := cdp.dialer.DialContext(ctx, "ws"+strings.TrimPrefix(devTools.server.URL, "http")+"/devtools/browser/hang", nil)
+	require.NoError(t, err)
+
+	err = (&cdpConn{cdp: cdp, conn: conn}).call(ctx, "Target.activateTarget", nil, nil)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
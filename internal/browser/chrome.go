@@ -0,0 +1,24 @@
+package browser
+
+import (
+	"github.com/malleatus/tamjaweb/internal/logger"
+)
+
+var chromeLogger = logger.New("browser:chrome")
+
+var chromeVendor = chromiumVendor{
+	name:    "Chrome",
+	windows: []string{"Google", "Chrome", "User Data"},
+	darwin:  []string{"Google", "Chrome"},
+	linux:   []string{"google-chrome"},
+}
+
+func init() {
+	chromeLogger.Info("Registering Google Chrome browser")
+	RegisterBrowser(NewChrome())
+}
+
+// NewChrome creates a new Google Chrome browser instance with the default path provider
+func NewChrome() *Chromium {
+	return newChromium(chromeVendor)
+}
@@ -0,0 +1,508 @@
+package browser
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/malleatus/tamjaweb/internal/logger"
+)
+
+var chromiumLogger = logger.New("browser:chromium")
+
+// BookmarksPathProvider is a function type that returns the path to bookmarks
+type BookmarksPathProvider func(profile string) (string, error)
+
+// chromiumVendor describes where a Chromium-family browser keeps its user
+// data on each supported platform. A nil entry means the browser is not
+// available on that platform.
+type chromiumVendor struct {
+	name string
+	// windows is relative to %LOCALAPPDATA% (or %APPDATA% when windowsRoaming is set)
+	windows []string
+	// darwin is relative to ~/Library/Application Support
+	darwin []string
+	// linux is relative to ~/.config
+	linux []string
+	// windowsRoaming stores the user data under %APPDATA% instead of %LOCALAPPDATA%
+	windowsRoaming bool
+	// profileInRoot means the "Default" profile lives directly in the user
+	// data directory instead of a "Default" sub directory (e.g. Opera)
+	profileInRoot bool
+}
+
+// platformDirs holds the OS specific base directories browsers keep their
+// data under
+type platformDirs struct {
+	goos         string
+	homeDir      string
+	localAppData string
+	appData      string
+}
+
+// currentPlatformDirs returns the platformDirs for the running system
+func currentPlatformDirs() (platformDirs, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return platformDirs{}, err
+	}
+
+	dirs := platformDirs{
+		goos:    runtime.GOOS,
+		homeDir: homeDir,
+	}
+	if runtime.GOOS == "windows" {
+		dirs.localAppData = os.Getenv("LOCALAPPDATA")
+		dirs.appData = os.Getenv("APPDATA")
+	}
+
+	return dirs, nil
+}
+
+// userDataDirForPlatform returns the vendor's user data directory for the specified platform
+func (v chromiumVendor) userDataDirForPlatform(dirs platformDirs) (string, error) {
+	var base string
+	var parts []string
+
+	switch dirs.goos {
+	case "windows":
+		base, parts = dirs.localAppData, v.windows
+		if v.windowsRoaming {
+			base = dirs.appData
+		}
+	case "darwin":
+		base, parts = filepath.Join(dirs.homeDir, "Library", "Application Support"), v.darwin
+	case "linux":
+		base, parts = filepath.Join(dirs.homeDir, ".config"), v.linux
+	default:
+		return "", fmt.Errorf("unsupported operating system: %s: %w", dirs.goos, ErrNotInstalled)
+	}
+
+	if parts == nil {
+		return "", fmt.Errorf("%s is not available on %s: %w", v.name, dirs.goos, ErrNotInstalled)
+	}
+
+	return filepath.Join(append([]string{base}, parts...)...), nil
+}
+
+// profileDirForPlatform returns the directory of one of the vendor's profiles for the specified platform
+func (v chromiumVendor) profileDirForPlatform(dirs platformDirs, profile string) (string, error) {
+	userDataDir, err := v.userDataDirForPlatform(dirs)
+	if err != nil {
+		return "", err
+	}
+
+	if v.profileInRoot && profile == "Default" {
+		return userDataDir, nil
+	}
+
+	return filepath.Join(userDataDir, profile), nil
+}
+
+// bookmarksPathForPlatform returns the path to the vendor's bookmarks for the specified platform
+func (v chromiumVendor) bookmarksPathForPlatform(dirs platformDirs, profile string) (string, error) {
+	profileDir, err := v.profileDirForPlatform(dirs, profile)
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(profileDir, "Bookmarks"), nil
+}
+
+// localStatePath returns the path to the vendor's "Local State" file based on OS
+func (v chromiumVendor) localStatePath() (string, error) {
+	dirs, err := currentPlatformDirs()
+	if err != nil {
+		return "", err
+	}
+
+	userDataDir, err := v.userDataDirForPlatform(dirs)
+	if err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(userDataDir, "Local State")
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return "", fmt.Errorf("%s browser local state not found at %s: %w", v.name, path, ErrNotInstalled)
+	}
+
+	return path, nil
+}
+
+// profileDir returns the directory of one of the vendor's profiles based on OS
+func (v chromiumVendor) profileDir(profile string) (string, error) {
+	dirs, err := currentPlatformDirs()
+	if err != nil {
+		return "", err
+	}
+
+	path, err := v.profileDirForPlatform(dirs, profile)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return "", fmt.Errorf("%s browser profile not found at %s: %w", v.name, path, ErrNotInstalled)
+	}
+
+	return path, nil
+}
+
+// bookmarksPath returns the path to the vendor's bookmarks file based on OS
+func (v chromiumVendor) bookmarksPath(profile string) (string, error) {
+	dirs, err := currentPlatformDirs()
+	if err != nil {
+		return "", err
+	}
+
+	path, err := v.bookmarksPathForPlatform(dirs, profile)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return "", fmt.Errorf("%s browser bookmarks not found at %s: %w", v.name, path, ErrNotInstalled)
+	}
+
+	return path, nil
+}
+
+// Chromium reads bookmarks for any browser built on Chromium, which all share
+// the same Bookmarks JSON format
+type Chromium struct {
+	name              string
+	logger            *log.Logger
+	getBookmarksPath  BookmarksPathProvider
+	getLocalStatePath func() (string, error)
+	// getProfileDir returns the profile directory holding the other files
+	// read (e.g. sessions)
+	getProfileDir func(profile string) (string, error)
+}
+
+// newChromium creates a new Chromium-family browser instance for the vendor
+func newChromium(v chromiumVendor) *Chromium {
+	return &Chromium{
+		name:              v.name,
+		logger:            logger.New("browser:" + strings.ToLower(v.name)),
+		getBookmarksPath:  v.bookmarksPath,
+		getLocalStatePath: v.localStatePath,
+		getProfileDir:     v.profileDir,
+	}
+}
+
+func (c *Chromium) Name() string {
+	return c.name
+}
+
+// ChromiumBookmarks represents the structure of the Bookmarks JSON file
+type ChromiumBookmarks struct {
+	Checksum string                `json:"checksum"`
+	Roots    ChromiumBookmarkRoots `json:"roots"`
+	Version  int                   `json:"version"`
+}
+
+// ChromiumBookmarkRoots holds the top level folders of the bookmarks tree
+// keyed by their name in the JSON file ("bookmark_bar", "other", "synced" and
+// any vendor specific roots)
+type ChromiumBookmarkRoots map[string]ChromiumBookmarkNode
+
+// chromiumRootOrder is the order Chromium shows its built in roots in
+var chromiumRootOrder = []string{"bookmark_bar", "other", "synced"}
+
+// chromiumRootNames are used for built in roots that have no name in the file
+var chromiumRootNames = map[string]string{
+	"bookmark_bar": "Bookmark Bar",
+	"other":        "Other Bookmarks",
+	"synced":       "Mobile Bookmarks",
+}
+
+// UnmarshalJSON decodes every root that is a bookmark node. Some vendors keep
+// other bookkeeping under "roots", which is skipped instead of failing the
+// whole file.
+func (r *ChromiumBookmarkRoots) UnmarshalJSON(data []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	roots := make(ChromiumBookmarkRoots, len(raw))
+	for key, value := range raw {
+		var node ChromiumBookmarkNode
+		if err := json.Unmarshal(value, &node); err != nil {
+			chromiumLogger.Debug("Skipping root that is not a bookmark node", "root", key, "error", err)
+			continue
+		}
+		roots[key] = node
+	}
+
+	*r = roots
+	return nil
+}
+
+// Keys returns the keys of the roots, with the built in roots first in the
+// order Chromium shows them followed by the remaining roots sorted by key
+func (r ChromiumBookmarkRoots) Keys() []string {
+	keys := make([]string, 0, len(r))
+	for _, key := range chromiumRootOrder {
+		if _, ok := r[key]; ok {
+			keys = append(keys, key)
+		}
+	}
+
+	var extra []string
+	for key := range r {
+		if _, ok := chromiumRootNames[key]; !ok {
+			extra = append(extra, key)
+		}
+	}
+	sort.Strings(extra)
+
+	return append(keys, extra...)
+}
+
+// DisplayName returns the folder name shown for the root with the given key
+func (r ChromiumBookmarkRoots) DisplayName(key string) string {
+	if name := r[key].Name; name != "" {
+		return name
+	}
+	if name, ok := chromiumRootNames[key]; ok {
+		return name
+	}
+	return key
+}
+
+// ChromiumBookmarkNode represents a node in the bookmarks tree
+type ChromiumBookmarkNode struct {
+	DateAdded    json.Number            `json:"date_added"`
+	DateLastUsed json.Number            `json:"date_last_used"`
+	DateModified json.Number            `json:"date_modified,omitempty"`
+	GUID         string                 `json:"guid"`
+	ID           string                 `json:"id"`
+	MetaInfo     map[string]string      `json:"meta_info,omitempty"`
+	Name         string                 `json:"name"`
+	Type         string                 `json:"type"`
+	URL          string                 `json:"url,omitempty"`
+	Children     []ChromiumBookmarkNode `json:"children,omitempty"`
+}
+
+// chromiumLocalState represents the parts of the "Local State" file that
+// describe the browser's profiles
+type chromiumLocalState struct {
+	Profile struct {
+		InfoCache map[string]struct {
+			Name string `json:"name"`
+		} `json:"info_cache"`
+		ProfilesOrder []string `json:"profiles_order"`
+	} `json:"profile"`
+}
+
+// Profiles lists the profiles recorded in the browser's "Local State" file,
+// in the order the browser shows them
+func (c *Chromium) Profiles() ([]Profile, error) {
+	localStatePath, err := c.getLocalStatePath()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(localStatePath)
+	if err != nil {
+		return nil, err
+	}
+
+	var localState chromiumLocalState
+	if err := json.Unmarshal(data, &localState); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal %s: %w", localStatePath, err)
+	}
+
+	infoCache := localState.Profile.InfoCache
+	profiles := make([]Profile, 0, len(infoCache))
+	seen := make(map[string]bool, len(infoCache))
+
+	for _, dir := range localState.Profile.ProfilesOrder {
+		info, ok := infoCache[dir]
+		if !ok || seen[dir] {
+			continue
+		}
+		seen[dir] = true
+		profiles = append(profiles, Profile{Name: info.Name, Dir: dir})
+	}
+
+	// profiles_order is not written by every vendor, so anything it misses is
+	// appended in directory order
+	remaining := make([]string, 0, len(infoCache))
+	for dir := range infoCache {
+		if !seen[dir] {
+			remaining = append(remaining, dir)
+		}
+	}
+	sort.Strings(remaining)
+	for _, dir := range remaining {
+		profiles = append(profiles, Profile{Name: infoCache[dir].Name, Dir: dir})
+	}
+
+	return profiles, nil
+}
+
+// resolveProfile maps a profile name or directory to the profile it refers
+// to. When the browser's profiles can't be read the value is assumed to be a
+// directory name.
+func (c *Chromium) resolveProfile(profile string) Profile {
+	profiles, err := c.Profiles()
+	if err != nil {
+		c.logger.Debug("Failed to read profiles, using profile as directory", "browser", c.name, "profile", profile, "error", err)
+		return Profile{Name: profile, Dir: profile}
+	}
+
+	if match, ok := findProfile(profiles, profile); ok {
+		return match
+	}
+
+	return Profile{Name: profile, Dir: profile}
+}
+
+// GetBookmarksPath is exposed for testing
+func (c *Chromium) GetBookmarksPath(profile string) (string, error) {
+	return c.getBookmarksPath(profile)
+}
+
+func (c *Chromium) GetBookmarks(ctx context.Context, profile string) ([]Bookmark, error) {
+	resolvedProfile := c.resolveProfile(profile)
+
+	bookmarksPath, err := c.getBookmarksPath(resolvedProfile.Dir)
+	if err != nil {
+		c.logger.Debug("Failed to get bookmarks path", "browser", c.name, "error", err)
+		return nil, err
+	}
+
+	// the path provider may have been slow (e.g. a network home directory)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(bookmarksPath)
+	if err != nil {
+		c.logger.Debug("Failed to read bookmarks file", "browser", c.name, "path", bookmarksPath, "error", err)
+		if os.IsNotExist(err) {
+			// profiles that never had a bookmark saved have no Bookmarks file
+			err = fmt.Errorf("%w: %w", err, ErrNotInstalled)
+		}
+		return nil, &BrowserError{Browser: c.name, Profile: resolvedProfile.Name, Path: bookmarksPath, Err: err}
+	}
+
+	var chromeBookmarks ChromiumBookmarks
+	if err := json.Unmarshal(data, &chromeBookmarks); err != nil {
+		c.logger.Debug("Failed to unmarshal bookmarks JSON", "browser", c.name, "path", bookmarksPath, "error", err)
+		return nil, &BrowserError{Browser: c.name, Profile: resolvedProfile.Name, Path: bookmarksPath, Err: err}
+	}
+
+	var bookmarks []Bookmark
+
+	for _, key := range chromeBookmarks.Roots.Keys() {
+		processBookmarkNodes(&bookmarks, chromeBookmarks.Roots[key], []string{chromeBookmarks.Roots.DisplayName(key)})
+	}
+
+	for i := range bookmarks {
+		bookmarks[i].Profile = resolvedProfile.Name
+	}
+
+	return bookmarks, nil
+}
+
+// processBookmarkNodes recursively processes the bookmarks in folder.
+// Chromium rarely records date_modified on bookmarks, it stays zero when
+// missing.
+func processBookmarkNodes(bookmarks *[]Bookmark, folder ChromiumBookmarkNode, folders []string) {
+	folderModified, err := chromiumTime(folder.DateModified)
+	if err != nil {
+		chromiumLogger.Debug("Failed to convert date_modified to int64", "value", folder.DateModified, "error", err)
+	}
+
+	for _, node := range folder.Children {
+		if node.Type == "url" {
+			dateAdded, err := chromiumTime(node.DateAdded)
+			if err != nil {
+				chromiumLogger.Error("Failed to convert date_added to int64", "value", node.DateAdded, "error", err)
+				continue
+			}
+
+			// date_last_used is missing from bookmarks written by older versions
+			dateLastUsed, err := chromiumTime(node.DateLastUsed)
+			if err != nil {
+				chromiumLogger.Debug("Failed to convert date_last_used to int64", "value", node.DateLastUsed, "error", err)
+			}
+
+			dateModified, err := chromiumTime(node.DateModified)
+			if err != nil {
+				chromiumLogger.Debug("Failed to convert date_modified to int64", "value", node.DateModified, "error", err)
+			}
+
+			*bookmarks = append(*bookmarks, Bookmark{
+				ID:                 node.ID,
+				GUID:               node.GUID,
+				Title:              node.Name,
+				URL:                node.URL,
+				DateAdded:          dateAdded,
+				DateLastUsed:       dateLastUsed,
+				DateModified:       dateModified,
+				FolderDateModified: folderModified,
+				Folders:            folders,
+				FolderPath:         filepath.Join(folders...),
+				MetaInfo:           node.MetaInfo,
+			})
+		} else if node.Type == "folder" && len(node.Children) > 0 {
+			// Recurse into folder, clipped so siblings don't share the names
+			processBookmarkNodes(bookmarks, node, append(slices.Clip(folders), node.Name))
+		}
+	}
+}
+
+// chromiumTime converts a Chromium timestamp, microseconds since 1601-01-01,
+// to a time.Time. Missing and "0" timestamps mean the event never happened
+// and are returned as the zero time.
+func chromiumTime(value json.Number) (time.Time, error) {
+	if value == "" || value == "0" {
+		return time.Time{}, nil
+	}
+
+	microseconds, err := value.Int64()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return chromiumMicros(microseconds), nil
+}
+
+// chromiumMicros converts microseconds since 1601-01-01 (base::Time's
+// internal value) to a time.Time
+func chromiumMicros(microseconds int64) time.Time {
+	// Windows epoch adjustment (difference between 1601 and 1970 in microseconds)
+	windowsToUnixEpochDiff := int64(11644473600 * 1000000)
+	unixMicroseconds := microseconds - windowsToUnixEpochDiff
+	return time.Unix(0, unixMicroseconds*1000) // convert to nanoseconds
+}
+
+var chromiumBrowserVendor = chromiumVendor{
+	name:    "Chromium",
+	windows: []string{"Chromium", "User Data"},
+	darwin:  []string{"Chromium"},
+	linux:   []string{"chromium"},
+}
+
+func init() {
+	chromiumLogger.Info("Registering Chromium browser")
+	RegisterBrowser(NewChromium())
+}
+
+// NewChromium creates a new instance of the open source Chromium browser with
+// the default path provider
+func NewChromium() *Chromium {
+	return newChromium(chromiumBrowserVendor)
+}
@@ -0,0 +1,692 @@
+package browser
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf16"
+
+	"github.com/google/uuid"
+)
+
+// EditOptions controls how changes to bookmarks are written
+type EditOptions struct {
+	// Force writes while the browser is running. The browser keeps its
+	// bookmarks in memory and will overwrite the change the next time it
+	// saves them.
+	Force bool
+}
+
+// BookmarkEditor is implemented by browsers whose bookmarks can be changed.
+// Bookmarks (and folders) are referred to by their ID, GUID or, for
+// bookmarks only, their URL. Folder paths are "/" separated and start with
+// the name of a root folder, e.g. "Bookmark Bar/Work". Each method returns
+// the bookmark as it is after the change.
+type BookmarkEditor interface {
+	Name() string
+	AddBookmark(ctx context.Context, profile, folderPath, title, url string, opts EditOptions) (Bookmark, error)
+	AddBookmarks(ctx context.Context, profile, folderPath string, bookmarks []Bookmark, opts EditOptions) ([]Bookmark, error)
+	RemoveBookmark(ctx context.Context, profile, ref string, opts EditOptions) (Bookmark, error)
+	MoveBookmark(ctx context.Context, profile, ref, folderPath string, opts EditOptions) (Bookmark, error)
+	RenameBookmark(ctx context.Context, profile, ref, title string, opts EditOptions) (Bookmark, error)
+}
+
+// chromiumBackupSuffix is appended to the Bookmarks path for the copy kept
+// before each change. Chromium keeps its own "Bookmarks.bak", which it
+// overwrites whenever it starts.
+const chromiumBackupSuffix = ".tamjaweb.bak"
+
+// chromiumLockFiles are created in the user data directory while the
+// browser runs, "SingletonLock" on Linux and macOS and "lockfile" on Windows
+var chromiumLockFiles = []string{"SingletonLock", "lockfile"}
+
+// AddBookmark adds a bookmark to the end of the folder at folderPath,
+// creating any folders that are missing
+func (c *Chromium) AddBookmark(ctx context.Context, profile, folderPath, title, url string, opts EditOptions) (Bookmark, error) {
+	return c.editBookmarks(ctx, profile, opts, func(doc *chromiumBookmarksDocument) (Bookmark, error) {
+		return doc.add(SplitFolderPath(folderPath), Bookmark{Title: title, URL: url})
+	})
+}
+
+// AddBookmarks adds bookmarks (e.g. imported from another browser) in a
+// single write. Each bookmark's FolderPath is created below folderPath and
+// its dates are kept when set.
+func (c *Chromium) AddBookmarks(ctx context.Context, profile, folderPath string, bookmarks []Bookmark, opts EditOptions) ([]Bookmark, error) {
+	var added []Bookmark
+	result, err := c.editBookmarks(ctx, profile, opts, func(doc *chromiumBookmarksDocument) (Bookmark, error) {
+		for _, bookmark := range bookmarks {
+			result, err := doc.add(append(SplitFolderPath(folderPath), bookmark.FolderNames()...), bookmark)
+			if err != nil {
+				return Bookmark{}, fmt.Errorf("%s: %w", bookmark.URL, err)
+			}
+			added = append(added, result)
+		}
+		return Bookmark{}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range added {
+		added[i].Profile = result.Profile
+	}
+
+	return added, nil
+}
+
+// RemoveBookmark removes a bookmark, or a folder with everything in it
+func (c *Chromium) RemoveBookmark(ctx context.Context, profile, ref string, opts EditOptions) (Bookmark, error) {
+	return c.editBookmarks(ctx, profile, opts, func(doc *chromiumBookmarksDocument) (Bookmark, error) {
+		return doc.remove(ref)
+	})
+}
+
+// MoveBookmark moves a bookmark, or a folder, to the end of the folder at
+// folderPath, creating any folders that are missing
+func (c *Chromium) MoveBookmark(ctx context.Context, profile, ref, folderPath string, opts EditOptions) (Bookmark, error) {
+	return c.editBookmarks(ctx, profile, opts, func(doc *chromiumBookmarksDocument) (Bookmark, error) {
+		return doc.move(ref, SplitFolderPath(folderPath))
+	})
+}
+
+// RenameBookmark changes the title of a bookmark, or the name of a folder
+func (c *Chromium) RenameBookmark(ctx context.Context, profile, ref, title string, opts EditOptions) (Bookmark, error) {
+	return c.editBookmarks(ctx, profile, opts, func(doc *chromiumBookmarksDocument) (Bookmark, error) {
+		return doc.rename(ref, title)
+	})
+}
+
+// editBookmarks applies edit to the profile's Bookmarks file and writes it
+// back with a fresh checksum, keeping a backup of the previous version
+func (c *Chromium) editBookmarks(ctx context.Context, profile string, opts EditOptions, edit func(doc *chromiumBookmarksDocument) (Bookmark, error)) (Bookmark, error) {
+	if profile == AllProfiles {
+		return Bookmark{}, fmt.Errorf("bookmarks can only be changed in a single profile, not %q", AllProfiles)
+	}
+
+	resolvedProfile := c.resolveProfile(profile)
+	fail := func(path string, err error) (Bookmark, error) {
+		return Bookmark{}, &BrowserError{Browser: c.name, Profile: resolvedProfile.Name, Path: path, Err: err}
+	}
+
+	bookmarksPath, err := c.getBookmarksPath(resolvedProfile.Dir)
+	if err != nil {
+		return fail("", err)
+	}
+
+	if !opts.Force {
+		profileDir, err := c.getProfileDir(resolvedProfile.Dir)
+		if err != nil {
+			return fail("", err)
+		}
+		if lockPath, ok := chromiumProfileLock(profileDir); ok {
+			return fail(lockPath, ErrProfileInUse)
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return Bookmark{}, err
+	}
+
+	original, err := os.ReadFile(bookmarksPath)
+	if err != nil {
+		return fail(bookmarksPath, err)
+	}
+
+	doc, err := parseChromiumBookmarksDocument(original)
+	if err != nil {
+		return fail(bookmarksPath, err)
+	}
+	doc.now = time.Now()
+
+	bookmark, err := edit(doc)
+	if err != nil {
+		return fail(bookmarksPath, err)
+	}
+	bookmark.Profile = resolvedProfile.Name
+
+	data, err := doc.marshal()
+	if err != nil {
+		return fail(bookmarksPath, err)
+	}
+
+	if err := writeFileWithBackup(bookmarksPath, bookmarksPath+chromiumBackupSuffix, original, data); err != nil {
+		return fail(bookmarksPath, err)
+	}
+
+	c.logger.Debug("Wrote bookmarks", "browser", c.name, "path", bookmarksPath, "checksum", doc.checksum())
+
+	return bookmark, nil
+}
+
+// chromiumProfileLock returns the lock file of a running browser, looked for
+// in the profile directory and the user data directory above it
+func chromiumProfileLock(profileDir string) (string, bool) {
+	for _, dir := range []string{profileDir, filepath.Dir(profileDir)} {
+		for _, name := range chromiumLockFiles {
+			path := filepath.Join(dir, name)
+			// SingletonLock is a symlink to "<host>-<pid>", so it never resolves
+			if _, err := os.Lstat(path); err == nil {
+				return path, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+// writeFileWithBackup replaces the file at path with data, first saving
+// original to backupPath. The new file is written next to path and renamed
+// over it, so readers see either the old or the new file, never a partial one.
+func writeFileWithBackup(path, backupPath string, original, data []byte) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(backupPath, original, info.Mode().Perm()); err != nil {
+		return fmt.Errorf("failed to write backup: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+"-*.tmp")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		// a no-op once the file has been renamed
+		_ = os.Remove(tmp.Name())
+	}()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), info.Mode().Perm()); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// chromiumEditNode is a node of the bookmarks tree that keeps every field of
+// the file, including the ones tamjaweb doesn't know about, so that writing
+// it back only changes what was edited
+type chromiumEditNode struct {
+	ID       string
+	GUID     string
+	Name     string
+	Type     string
+	URL      string
+	Children []*chromiumEditNode
+	fields   map[string]json.RawMessage
+}
+
+func (n *chromiumEditNode) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &n.fields); err != nil {
+		return err
+	}
+
+	for key, value := range map[string]*string{"id": &n.ID, "guid": &n.GUID, "name": &n.Name, "type": &n.Type, "url": &n.URL} {
+		raw, ok := n.fields[key]
+		if !ok {
+			continue
+		}
+		if err := json.Unmarshal(raw, value); err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+	}
+
+	if raw, ok := n.fields["children"]; ok {
+		if err := json.Unmarshal(raw, &n.Children); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (n *chromiumEditNode) MarshalJSON() ([]byte, error) {
+	fields := make(map[string]any, len(n.fields)+6)
+	for key, value := range n.fields {
+		fields[key] = value
+	}
+
+	fields["id"] = n.ID
+	fields["guid"] = n.GUID
+	fields["name"] = n.Name
+	fields["type"] = n.Type
+	if n.Type == "url" {
+		fields["url"] = n.URL
+		delete(fields, "children")
+	} else {
+		// Chromium rejects folders without a children list
+		fields["children"] = n.Children
+		if n.Children == nil {
+			fields["children"] = []*chromiumEditNode{}
+		}
+	}
+
+	return marshalChromiumJSON(fields, "")
+}
+
+// setTime sets one of the node's timestamp fields
+func (n *chromiumEditNode) setTime(key string, t time.Time) {
+	if n.fields == nil {
+		n.fields = map[string]json.RawMessage{}
+	}
+	n.fields[key] = json.RawMessage(strconv.Quote(strconv.FormatInt(chromiumTimestamp(t), 10)))
+}
+
+// time reads one of the node's timestamp fields
+func (n *chromiumEditNode) time(key string) time.Time {
+	var value json.Number
+	if err := json.Unmarshal(n.fields[key], &value); err != nil {
+		return time.Time{}
+	}
+
+	t, _ := chromiumTime(value)
+	return t
+}
+
+// bookmark converts the node, held by folder in folders, to the Bookmark it
+// is read as
+func (n *chromiumEditNode) bookmark(folder *chromiumEditNode, folders []string) Bookmark {
+	return Bookmark{
+		ID:                 n.ID,
+		GUID:               n.GUID,
+		Title:              n.Name,
+		URL:                n.URL,
+		DateAdded:          n.time("date_added"),
+		DateLastUsed:       n.time("date_last_used"),
+		DateModified:       n.time("date_modified"),
+		FolderDateModified: folder.time("date_modified"),
+		Folders:            folders,
+		FolderPath:         filepath.Join(folders...),
+	}
+}
+
+// chromiumBookmarksDocument is a Bookmarks file being edited
+type chromiumBookmarksDocument struct {
+	fields     map[string]json.RawMessage
+	rootFields map[string]json.RawMessage
+	// roots holds the built in roots, the only ones Chromium checksums and
+	// the only ones that can be edited
+	roots map[string]*chromiumEditNode
+	// vendorRoots are the other roots that are bookmark nodes, only read so
+	// that new ids don't clash with theirs
+	vendorRoots []*chromiumEditNode
+	// lastID is the highest id in use, 0 until nextID first scans for it
+	lastID int64
+	// now is used for the timestamps of the edit
+	now time.Time
+}
+
+func parseChromiumBookmarksDocument(data []byte) (*chromiumBookmarksDocument, error) {
+	doc := &chromiumBookmarksDocument{roots: map[string]*chromiumEditNode{}}
+
+	if err := json.Unmarshal(data, &doc.fields); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(doc.fields["roots"], &doc.rootFields); err != nil {
+		return nil, fmt.Errorf("roots: %w", err)
+	}
+
+	for key, raw := range doc.rootFields {
+		var root chromiumEditNode
+		err := json.Unmarshal(raw, &root)
+
+		if _, ok := chromiumRootNames[key]; !ok {
+			if err == nil {
+				doc.vendorRoots = append(doc.vendorRoots, &root)
+			}
+			continue
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("roots.%s: %w", key, err)
+		}
+		doc.roots[key] = &root
+	}
+
+	if len(doc.roots) == 0 {
+		return nil, errors.New("no bookmark roots found")
+	}
+
+	return doc, nil
+}
+
+// marshal encodes the document with a fresh checksum, formatted the way
+// Chromium writes it
+func (d *chromiumBookmarksDocument) marshal() ([]byte, error) {
+	rootFields := make(map[string]any, len(d.rootFields))
+	for key, value := range d.rootFields {
+		rootFields[key] = value
+	}
+	for key, root := range d.roots {
+		rootFields[key] = root
+	}
+
+	fields := make(map[string]any, len(d.fields))
+	for key, value := range d.fields {
+		fields[key] = value
+	}
+	fields["roots"] = rootFields
+	fields["checksum"] = d.checksum()
+
+	return marshalChromiumJSON(fields, "   ")
+}
+
+// marshalChromiumJSON encodes v without escaping HTML characters, which
+// Chromium doesn't do either
+func marshalChromiumJSON(v any, indent string) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if indent != "" {
+		encoder.SetIndent("", indent)
+	}
+
+	if err := encoder.Encode(v); err != nil {
+		return nil, err
+	}
+
+	if indent == "" {
+		return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+	}
+	return buf.Bytes(), nil
+}
+
+// checksum computes the checksum Chromium verifies when loading the file: an
+// MD5 of the id, title (as UTF-16) and type of every node in the built in
+// roots, plus the URL of bookmarks
+func (d *chromiumBookmarksDocument) checksum() string {
+	hash := md5.New()
+
+	var walk func(node *chromiumEditNode)
+	walk = func(node *chromiumEditNode) {
+		hash.Write([]byte(node.ID))
+		for _, unit := range utf16.Encode([]rune(node.Name)) {
+			_ = binary.Write(hash, binary.LittleEndian, unit)
+		}
+
+		if node.Type == "url" {
+			hash.Write([]byte("url"))
+			hash.Write([]byte(node.URL))
+			return
+		}
+
+		hash.Write([]byte("folder"))
+		for _, child := range node.Children {
+			walk(child)
+		}
+	}
+
+	for _, key := range chromiumRootOrder {
+		if root, ok := d.roots[key]; ok {
+			walk(root)
+		}
+	}
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// nextID returns an id one higher than any in use, which is how Chromium
+// assigns them
+func (d *chromiumBookmarksDocument) nextID() string {
+	if d.lastID == 0 {
+		var walk func(node *chromiumEditNode)
+		walk = func(node *chromiumEditNode) {
+			if id, err := strconv.ParseInt(node.ID, 10, 64); err == nil && id > d.lastID {
+				d.lastID = id
+			}
+			for _, child := range node.Children {
+				walk(child)
+			}
+		}
+		for _, root := range d.roots {
+			walk(root)
+		}
+		for _, root := range d.vendorRoots {
+			walk(root)
+		}
+	}
+
+	d.lastID++
+	return strconv.FormatInt(d.lastID, 10)
+}
+
+// rootName returns the folder name the root with the given key is read as
+func (d *chromiumBookmarksDocument) rootName(key string) string {
+	if name := d.roots[key].Name; name != "" {
+		return name
+	}
+	return chromiumRootNames[key]
+}
+
+// folder finds the folder named by parts, creating missing folders below the
+// root when create is set. It also returns the folder's names the way they
+// are read back.
+func (d *chromiumBookmarksDocument) folder(parts []string, create bool) (*chromiumEditNode, []string, error) {
+	if len(parts) == 0 {
+		return nil, nil, errors.New("folder is required")
+	}
+	folderPath := filepath.Join(parts...)
+
+	var folder *chromiumEditNode
+	var path []string
+	for _, key := range chromiumRootOrder {
+		if _, ok := d.roots[key]; !ok {
+			continue
+		}
+		// the name in the file is localised, so the English name and key work too
+		if strings.EqualFold(parts[0], d.rootName(key)) || strings.EqualFold(parts[0], chromiumRootNames[key]) || parts[0] == key {
+			folder, path = d.roots[key], []string{d.rootName(key)}
+			break
+		}
+	}
+	if folder == nil {
+		var names []string
+		for _, key := range chromiumRootOrder {
+			if _, ok := d.roots[key]; ok {
+				names = append(names, d.rootName(key))
+			}
+		}
+		return nil, nil, fmt.Errorf("folder %q must start with one of %s", folderPath, strings.Join(names, ", "))
+	}
+
+	for _, part := range parts[1:] {
+		var next *chromiumEditNode
+		for _, child := range folder.Children {
+			if child.Type == "folder" && child.Name == part {
+				next = child
+				break
+			}
+		}
+
+		if next == nil {
+			if !create {
+				return nil, nil, fmt.Errorf("folder %q not found", folderPath)
+			}
+			next = &chromiumEditNode{
+				ID:   d.nextID(),
+				GUID: uuid.NewString(),
+				Name: part,
+				Type: "folder",
+			}
+			next.setTime("date_added", d.now)
+			next.setTime("date_modified", d.now)
+			folder.Children = append(folder.Children, next)
+			folder.setTime("date_modified", d.now)
+		}
+
+		folder, path = next, append(path, part)
+	}
+
+	return folder, path, nil
+}
+
+// chromiumNodeMatch is a node found by find
+type chromiumNodeMatch struct {
+	node    *chromiumEditNode
+	parent  *chromiumEditNode
+	folders []string
+}
+
+// find finds the node ref refers to, see BookmarkEditor. Roots can't be
+// edited, so they are never found.
+func (d *chromiumBookmarksDocument) find(ref string) (chromiumNodeMatch, error) {
+	var byID, byURL []chromiumNodeMatch
+
+	var walk func(parent *chromiumEditNode, folders []string)
+	walk = func(parent *chromiumEditNode, folders []string) {
+		for _, child := range parent.Children {
+			match := chromiumNodeMatch{node: child, parent: parent, folders: folders}
+			if child.ID == ref || strings.EqualFold(child.GUID, ref) {
+				byID = append(byID, match)
+			}
+			if child.Type == "url" && child.URL == ref {
+				byURL = append(byURL, match)
+			}
+			if child.Type == "folder" {
+				walk(child, append(slices.Clip(folders), child.Name))
+			}
+		}
+	}
+	for _, key := range chromiumRootOrder {
+		if root, ok := d.roots[key]; ok {
+			walk(root, []string{d.rootName(key)})
+		}
+	}
+
+	switch {
+	case len(byID) > 0:
+		return byID[0], nil
+	case len(byURL) == 1:
+		return byURL[0], nil
+	case len(byURL) > 1:
+		ids := make([]string, len(byURL))
+		for i, match := range byURL {
+			ids[i] = match.node.ID
+		}
+		return chromiumNodeMatch{}, fmt.Errorf("%d bookmarks have the URL %s, use one of their ids instead: %s", len(byURL), ref, strings.Join(ids, ", "))
+	default:
+		return chromiumNodeMatch{}, fmt.Errorf("no bookmark or folder matches %q", ref)
+	}
+}
+
+// add adds a new bookmark with the title, URL and dates of bookmark to the
+// folder named by folders
+func (d *chromiumBookmarksDocument) add(folders []string, bookmark Bookmark) (Bookmark, error) {
+	if bookmark.URL == "" {
+		return Bookmark{}, errors.New("url is required")
+	}
+
+	folder, path, err := d.folder(folders, true)
+	if err != nil {
+		return Bookmark{}, err
+	}
+
+	node := &chromiumEditNode{
+		ID:   d.nextID(),
+		GUID: uuid.NewString(),
+		Name: bookmark.Title,
+		Type: "url",
+		URL:  bookmark.URL,
+	}
+
+	dateAdded := bookmark.DateAdded
+	if dateAdded.IsZero() {
+		dateAdded = d.now
+	}
+	node.setTime("date_added", dateAdded)
+
+	if bookmark.DateLastUsed.IsZero() {
+		node.fields["date_last_used"] = json.RawMessage(`"0"`)
+	} else {
+		node.setTime("date_last_used", bookmark.DateLastUsed)
+	}
+
+	folder.Children = append(folder.Children, node)
+	folder.setTime("date_modified", d.now)
+
+	return node.bookmark(folder, path), nil
+}
+
+func (d *chromiumBookmarksDocument) remove(ref string) (Bookmark, error) {
+	match, err := d.find(ref)
+	if err != nil {
+		return Bookmark{}, err
+	}
+
+	match.parent.Children = slices.DeleteFunc(match.parent.Children, func(node *chromiumEditNode) bool {
+		return node == match.node
+	})
+	match.parent.setTime("date_modified", d.now)
+
+	return match.node.bookmark(match.parent, match.folders), nil
+}
+
+func (d *chromiumBookmarksDocument) move(ref string, folders []string) (Bookmark, error) {
+	match, err := d.find(ref)
+	if err != nil {
+		return Bookmark{}, err
+	}
+
+	folder, path, err := d.folder(folders, true)
+	if err != nil {
+		return Bookmark{}, err
+	}
+
+	// folders created inside the moved folder are never written, as nothing
+	// is when the edit fails
+	if match.node.Type == "folder" && (folder == match.node || match.node.contains(folder)) {
+		return Bookmark{}, fmt.Errorf("can't move folder %q into itself", match.node.Name)
+	}
+
+	match.parent.Children = slices.DeleteFunc(match.parent.Children, func(node *chromiumEditNode) bool {
+		return node == match.node
+	})
+	match.parent.setTime("date_modified", d.now)
+	folder.Children = append(folder.Children, match.node)
+	folder.setTime("date_modified", d.now)
+
+	return match.node.bookmark(folder, path), nil
+}
+
+func (d *chromiumBookmarksDocument) rename(ref, title string) (Bookmark, error) {
+	match, err := d.find(ref)
+	if err != nil {
+		return Bookmark{}, err
+	}
+
+	match.node.Name = title
+
+	return match.node.bookmark(match.parent, match.folders), nil
+}
+
+// contains reports whether node is somewhere below n
+func (n *chromiumEditNode) contains(node *chromiumEditNode) bool {
+	for _, child := range n.Children {
+		if child == node || child.contains(node) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,402 @@
+package browser
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChromiumBookmarksChecksum(t *testing.T) {
+	doc, err := parseChromiumBookmarksDocument([]byte(`{
+		"checksum": "stale",
+		"roots": {
+			"bookmark_bar": {
+				"children": [
+					{"id": "4", "name": "Café", "type": "url", "url": "https://example.com/"}
+				],
+				"id": "1", "name": "Bookmarks bar", "type": "folder"
+			},
+			"other": {"children": [], "id": "2", "name": "Other bookmarks", "type": "folder"},
+			"synced": {"children": [], "id": "3", "name": "Mobile bookmarks", "type": "folder"},
+			"trash": {"children": [], "id": "5", "name": "Trash", "type": "folder"}
+		},
+		"version": 1
+	}`))
+	require.NoError(t, err)
+
+	// titles are hashed as UTF-16, ids, types and URLs as UTF-8 and vendor
+	// roots are left out
+	hash := md5.New()
+	for _, part := range []string{
+		"1", "B\x00o\x00o\x00k\x00m\x00a\x00r\x00k\x00s\x00 \x00b\x00a\x00r\x00", "folder",
+		"4", "C\x00a\x00f\x00\xe9\x00", "url", "https://example.com/",
+		"2", "O\x00t\x00h\x00e\x00r\x00 \x00b\x00o\x00o\x00k\x00m\x00a\x00r\x00k\x00s\x00", "folder",
+		"3", "M\x00o\x00b\x00i\x00l\x00e\x00 \x00b\x00o\x00o\x00k\x00m\x00a\x00r\x00k\x00s\x00", "folder",
+	} {
+		hash.Write([]byte(part))
+	}
+
+	assert.Equal(t, hex.EncodeToString(hash.Sum(nil)), doc.checksum())
+}
+
+func TestChromiumBookmarksChecksumMatchesChromium(t *testing.T) {
+	// written by Chrome 140, with non ASCII titles and a nested folder
+	data, err := os.ReadFile(filepath.Join("testdata", "chromium", "Bookmarks"))
+	require.NoError(t, err)
+
+	var written struct {
+		Checksum string `json:"checksum"`
+	}
+	require.NoError(t, json.Unmarshal(data, &written))
+
+	doc, err := parseChromiumBookmarksDocument(data)
+	require.NoError(t, err)
+
+	assert.Equal(t, written.Checksum, doc.checksum())
+}
+
+func TestChromiumEditBookmarks(t *testing.T) {
+	testCases := []struct {
+		name     string
+		edit     func(c *Chromium) (Bookmark, error)
+		expected Bookmark
+		// bookmarks is the title and folder of every bookmark after the edit
+		bookmarks [][2]string
+	}{
+		{
+			name: "Add",
+			edit: func(c *Chromium) (Bookmark, error) {
+				return c.AddBookmark(context.Background(), "Default", "Bookmark Bar/Work", "Go", "https://go.dev", EditOptions{})
+			},
+			expected: Bookmark{ID: "5", Title: "Go", URL: "https://go.dev", FolderPath: filepath.Join("Bookmark Bar", "Work")},
+			bookmarks: [][2]string{
+				{"Example Site", "Bookmark Bar"},
+				{"GitHub", filepath.Join("Bookmark Bar", "Work")},
+				{"Go", filepath.Join("Bookmark Bar", "Work")},
+				{"Other Site", "Other Bookmarks"},
+			},
+		},
+		{
+			name: "Add to new folder",
+			edit: func(c *Chromium) (Bookmark, error) {
+				return c.AddBookmark(context.Background(), "Default", "other bookmarks/Reading/Later", "Go", "https://go.dev", EditOptions{})
+			},
+			expected: Bookmark{ID: "7", Title: "Go", URL: "https://go.dev", FolderPath: filepath.Join("Other Bookmarks", "Reading", "Later")},
+			bookmarks: [][2]string{
+				{"Example Site", "Bookmark Bar"},
+				{"GitHub", filepath.Join("Bookmark Bar", "Work")},
+				{"Other Site", "Other Bookmarks"},
+				{"Go", filepath.Join("Other Bookmarks", "Reading", "Later")},
+			},
+		},
+		{
+			name: "Remove by URL",
+			edit: func(c *Chromium) (Bookmark, error) {
+				return c.RemoveBookmark(context.Background(), "Default", "https://github.com", EditOptions{})
+			},
+			expected: Bookmark{ID: "2", GUID: "guid2", Title: "GitHub", URL: "https://github.com", FolderPath: filepath.Join("Bookmark Bar", "Work")},
+			bookmarks: [][2]string{
+				{"Example Site", "Bookmark Bar"},
+				{"Other Site", "Other Bookmarks"},
+			},
+		},
+		{
+			name: "Remove folder",
+			edit: func(c *Chromium) (Bookmark, error) {
+				return c.RemoveBookmark(context.Background(), "Default", "guid3", EditOptions{})
+			},
+			expected: Bookmark{ID: "3", GUID: "guid3", Title: "Work", FolderPath: "Bookmark Bar"},
+			bookmarks: [][2]string{
+				{"Example Site", "Bookmark Bar"},
+				{"Other Site", "Other Bookmarks"},
+			},
+		},
+		{
+			name: "Move",
+			edit: func(c *Chromium) (Bookmark, error) {
+				return c.MoveBookmark(context.Background(), "Default", "4", "Bookmark Bar/Work", EditOptions{})
+			},
+			expected: Bookmark{ID: "4", GUID: "guid4", Title: "Other Site", URL: "https://othersite.com", FolderPath: filepath.Join("Bookmark Bar", "Work")},
+			bookmarks: [][2]string{
+				{"Example Site", "Bookmark Bar"},
+				{"GitHub", filepath.Join("Bookmark Bar", "Work")},
+				{"Other Site", filepath.Join("Bookmark Bar", "Work")},
+			},
+		},
+		{
+			name: "Move folder",
+			edit: func(c *Chromium) (Bookmark, error) {
+				return c.MoveBookmark(context.Background(), "Default", "3", "Other Bookmarks", EditOptions{})
+			},
+			expected: Bookmark{ID: "3", GUID: "guid3", Title: "Work", FolderPath: "Other Bookmarks"},
+			bookmarks: [][2]string{
+				{"Example Site", "Bookmark Bar"},
+				{"Other Site", "Other Bookmarks"},
+				{"GitHub", filepath.Join("Other Bookmarks", "Work")},
+			},
+		},
+		{
+			name: "Rename",
+			edit: func(c *Chromium) (Bookmark, error) {
+				return c.RenameBookmark(context.Background(), "Default", "1", "Example", EditOptions{})
+			},
+			expected: Bookmark{ID: "1", GUID: "guid1", Title: "Example", URL: "https://example.com", FolderPath: "Bookmark Bar"},
+			bookmarks: [][2]string{
+				{"Example", "Bookmark Bar"},
+				{"GitHub", filepath.Join("Bookmark Bar", "Work")},
+				{"Other Site", "Other Bookmarks"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dirs := platformDirs{goos: "linux", homeDir: t.TempDir()}
+			path := writeBookmarksFixture(t, chromiumBrowserVendor, dirs, "Default")
+			chromium := newTestChromium(chromiumBrowserVendor, path)
+
+			bookmark, err := tc.edit(chromium)
+			require.NoError(t, err)
+
+			assert.Equal(t, "Default", bookmark.Profile)
+			assert.Equal(t, tc.expected.ID, bookmark.ID)
+			if tc.expected.GUID != "" {
+				assert.Equal(t, tc.expected.GUID, bookmark.GUID)
+			} else {
+				assert.Len(t, bookmark.GUID, 36, "New bookmarks should get a GUID")
+			}
+			assert.Equal(t, tc.expected.Title, bookmark.Title)
+			assert.Equal(t, tc.expected.URL, bookmark.URL)
+			assert.Equal(t, tc.expected.FolderPath, bookmark.FolderPath)
+			if tc.name != "Rename" {
+				assert.WithinDuration(t, time.Now(), bookmark.FolderDateModified, time.Minute, "Should report the date of the folder it changed")
+			}
+
+			bookmarks, err := chromium.GetBookmarks(context.Background(), "Default")
+			require.NoError(t, err)
+
+			var titles [][2]string
+			for _, bookmark := range bookmarks {
+				titles = append(titles, [2]string{bookmark.Title, bookmark.FolderPath})
+			}
+			assert.Equal(t, tc.bookmarks, titles)
+
+			assertValidChromiumBookmarksFile(t, path)
+
+			backup, err := os.ReadFile(path + chromiumBackupSuffix)
+			require.NoError(t, err)
+			assert.Equal(t, createSampleBookmarksJSON(), string(backup), "Should back up the file before changing it")
+		})
+	}
+}
+
+func TestChromiumEditBookmarksKeepsUnknownFields(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "Bookmarks")
+	err := os.WriteFile(path, []byte(createAllRootsBookmarksJSON()), 0600)
+	require.NoError(t, err)
+
+	chromium := newTestChromium(chromiumBrowserVendor, path)
+
+	bookmark, err := chromium.AddBookmark(context.Background(), "Default", "Bookmarks bar", "Go <dev>", "https://go.dev/?a=1&b=2", EditOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "9", bookmark.ID, "Should number after the highest id, including vendor roots")
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"url": "https://go.dev/?a=1&b=2"`, "Should not escape HTML characters")
+
+	var file struct {
+		Roots map[string]json.RawMessage `json:"roots"`
+	}
+	err = json.Unmarshal(data, &file)
+	require.NoError(t, err)
+	assert.JSONEq(t, `"42"`, string(file.Roots["sync_transaction_version"]))
+	assert.JSONEq(t, `{"children": [], "name": "Trash", "type": "folder"}`, string(file.Roots["trash"]))
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm(), "Should keep the file's permissions")
+
+	bookmarks, err := chromium.GetBookmarks(context.Background(), "Default")
+	require.NoError(t, err)
+	assert.Len(t, bookmarks, 7)
+	assert.Equal(t, "Go <dev>", bookmarks[2].Title)
+	assert.False(t, bookmarks[2].DateAdded.IsZero())
+
+	assertValidChromiumBookmarksFile(t, path)
+}
+
+func TestChromiumEditBookmarksErrors(t *testing.T) {
+	testCases := []struct {
+		name     string
+		edit     func(c *Chromium) (Bookmark, error)
+		expected string
+	}{
+		{
+			name: "Unknown root",
+			edit: func(c *Chromium) (Bookmark, error) {
+				return c.AddBookmark(context.Background(), "Default", "Toolbar/Work", "Go", "https://go.dev", EditOptions{})
+			},
+			expected: `folder "Toolbar/Work" must start with one of Bookmark Bar, Other Bookmarks`,
+		},
+		{
+			name: "Unknown bookmark",
+			edit: func(c *Chromium) (Bookmark, error) {
+				return c.RemoveBookmark(context.Background(), "Default", "42", EditOptions{})
+			},
+			expected: `no bookmark or folder matches "42"`,
+		},
+		{
+			name: "Move folder into itself",
+			edit: func(c *Chromium) (Bookmark, error) {
+				return c.MoveBookmark(context.Background(), "Default", "3", "Bookmark Bar/Work/Archive", EditOptions{})
+			},
+			expected: `can't move folder "Work" into itself`,
+		},
+		{
+			name: "All profiles",
+			edit: func(c *Chromium) (Bookmark, error) {
+				return c.RenameBookmark(context.Background(), AllProfiles, "1", "Example", EditOptions{})
+			},
+			expected: `bookmarks can only be changed in a single profile, not "all"`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dirs := platformDirs{goos: "linux", homeDir: t.TempDir()}
+			path := writeBookmarksFixture(t, chromiumBrowserVendor, dirs, "Default")
+
+			_, err := tc.edit(newTestChromium(chromiumBrowserVendor, path))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.expected)
+
+			data, err := os.ReadFile(path)
+			require.NoError(t, err)
+			assert.Equal(t, createSampleBookmarksJSON(), string(data), "Should leave the file alone")
+		})
+	}
+}
+
+func TestChromiumEditBookmarksAmbiguousURL(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "Bookmarks")
+	err := os.WriteFile(path, []byte(createSampleBookmarksJSON()), 0644)
+	require.NoError(t, err)
+
+	chromium := newTestChromium(chromiumBrowserVendor, path)
+	_, err = chromium.AddBookmark(context.Background(), "Default", "Other Bookmarks", "GitHub again", "https://github.com", EditOptions{})
+	require.NoError(t, err)
+
+	_, err = chromium.RemoveBookmark(context.Background(), "Default", "https://github.com", EditOptions{})
+	assert.ErrorContains(t, err, "2 bookmarks have the URL https://github.com, use one of their ids instead: 2, 5")
+}
+
+func TestChromiumEditBookmarksProfileInUse(t *testing.T) {
+	dirs := platformDirs{goos: "linux", homeDir: t.TempDir()}
+	path := writeBookmarksFixture(t, chromiumBrowserVendor, dirs, "Default")
+
+	userDataDir, err := chromiumBrowserVendor.userDataDirForPlatform(dirs)
+	require.NoError(t, err)
+	// a dangling symlink, like the one a running browser leaves
+	err = os.Symlink("host-12345", filepath.Join(userDataDir, "SingletonLock"))
+	require.NoError(t, err)
+
+	chromium := newTestChromium(chromiumBrowserVendor, path)
+
+	_, err = chromium.RenameBookmark(context.Background(), "Default", "1", "Example", EditOptions{})
+	assert.ErrorIs(t, err, ErrProfileInUse)
+
+	bookmark, err := chromium.RenameBookmark(context.Background(), "Default", "1", "Example", EditOptions{Force: true})
+	require.NoError(t, err)
+	assert.Equal(t, "Example", bookmark.Title)
+}
+
+// assertValidChromiumBookmarksFile checks that the file at path has the
+// checksum Chromium expects
+func assertValidChromiumBookmarksFile(t *testing.T, path string) {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	doc, err := parseChromiumBookmarksDocument(data)
+	require.NoError(t, err)
+
+	var file struct {
+		Checksum string `json:"checksum"`
+	}
+	err = json.Unmarshal(data, &file)
+	require.NoError(t, err)
+
+	assert.Equal(t, doc.checksum(), file.Checksum)
+}
+
+func TestChromiumEditNodeTimestamps(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	node := &chromiumEditNode{}
+	node.setTime("date_added", now)
+
+	assert.Equal(t, `"13361716800000000"`, string(node.fields["date_added"]))
+	assert.Equal(t, now, node.time("date_added").UTC())
+	assert.True(t, node.time("date_modified").IsZero())
+}
+
+func TestChromiumAddBookmarks(t *testing.T) {
+	dirs := platformDirs{goos: "linux", homeDir: t.TempDir()}
+	path := writeBookmarksFixture(t, chromiumBrowserVendor, dirs, "Default")
+	chromium := newTestChromium(chromiumBrowserVendor, path)
+
+	dateAdded := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
+	added, err := chromium.AddBookmarks(context.Background(), "Default", "Other Bookmarks/Imported", []Bookmark{
+		{Title: "Go", URL: "https://go.dev", DateAdded: dateAdded, FolderPath: filepath.Join("Bookmarks bar", "Languages")},
+		{Title: "Rust", URL: "https://rust-lang.org", FolderPath: filepath.Join("Bookmarks bar", "Languages")},
+		{Title: "Unfiled", URL: "https://unfiled.example.com"},
+	}, EditOptions{})
+	require.NoError(t, err)
+
+	require.Len(t, added, 3)
+	assert.Equal(t, []string{"8", "9", "10"}, []string{added[0].ID, added[1].ID, added[2].ID}, "The three new folders take ids 5 to 7")
+	assert.Equal(t, "Default", added[0].Profile)
+
+	bookmarks, err := chromium.GetBookmarks(context.Background(), "Default")
+	require.NoError(t, err)
+	require.Len(t, bookmarks, 6)
+
+	assert.Equal(t, "Go", bookmarks[3].Title)
+	assert.Equal(t, filepath.Join("Other Bookmarks", "Imported", "Bookmarks bar", "Languages"), bookmarks[3].FolderPath)
+	assert.Equal(t, dateAdded, bookmarks[3].DateAdded.UTC(), "Should keep the date the bookmark was added")
+	assert.Equal(t, "Rust", bookmarks[4].Title)
+	assert.False(t, bookmarks[4].DateAdded.IsZero())
+	assert.Equal(t, "Unfiled", bookmarks[5].Title)
+	assert.Equal(t, filepath.Join("Other Bookmarks", "Imported"), bookmarks[5].FolderPath)
+
+	assertValidChromiumBookmarksFile(t, path)
+}
+
+func TestChromiumAddBookmarksFolderWithSeparator(t *testing.T) {
+	dirs := platformDirs{goos: "linux", homeDir: t.TempDir()}
+	path := writeBookmarksFixture(t, chromiumBrowserVendor, dirs, "Default")
+	chromium := newTestChromium(chromiumBrowserVendor, path)
+
+	added, err := chromium.AddBookmarks(context.Background(), "Default", "Other Bookmarks", []Bookmark{
+		{Title: "Go", URL: "https://go.dev", Folders: []string{"Dev/Tools"}, FolderPath: "Dev/Tools"},
+	}, EditOptions{})
+	require.NoError(t, err)
+
+	require.Len(t, added, 1)
+	assert.Equal(t, []string{"Other Bookmarks", "Dev/Tools"}, added[0].Folders)
+
+	bookmarks, err := chromium.GetBookmarks(context.Background(), "Default")
+	require.NoError(t, err)
+	require.Len(t, bookmarks, 4)
+	assert.Equal(t, []string{"Other Bookmarks", "Dev/Tools"}, bookmarks[3].Folders, "Should create a single folder")
+}
@@ -0,0 +1,87 @@
+package browser
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// GetHistory returns the pages visited within window from the profile's
+// History database, most recently visited first
+func (c *Chromium) GetHistory(ctx context.Context, profile string, window HistoryWindow) ([]HistoryEntry, error) {
+	resolvedProfile := c.resolveProfile(profile)
+
+	profileDir, err := c.getProfileDir(resolvedProfile.Dir)
+	if err != nil {
+		c.logger.Debug("Failed to get profile directory", "browser", c.name, "error", err)
+		return nil, err
+	}
+
+	historyPath := filepath.Join(profileDir, "History")
+	if _, err := os.Stat(historyPath); os.IsNotExist(err) {
+		return nil, fmt.Errorf("%s history not found at %s: %w", c.name, historyPath, ErrNotInstalled)
+	}
+
+	entries, err := readChromiumHistory(ctx, historyPath, window)
+	if err != nil {
+		c.logger.Debug("Failed to read history", "browser", c.name, "path", historyPath, "error", err)
+		return nil, &BrowserError{Browser: c.name, Profile: resolvedProfile.Name, Path: historyPath, Err: err}
+	}
+
+	for i := range entries {
+		entries[i].Profile = resolvedProfile.Name
+	}
+
+	return entries, nil
+}
+
+// readChromiumHistory sums up the visits of each url within window from a
+// copy of the History database
+func readChromiumHistory(ctx context.Context, historyPath string, window HistoryWindow) ([]HistoryEntry, error) {
+	db, cleanup, err := openSQLiteCopy(historyPath)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT u.url, IFNULL(u.title, ''), COUNT(v.id), MAX(v.visit_time)
+		FROM urls u
+		JOIN visits v ON v.url = u.id
+		WHERE u.hidden = 0 AND v.visit_time >= ?1 AND v.visit_time < ?2
+		GROUP BY u.id
+		ORDER BY MAX(v.visit_time) DESC
+	`,
+		historyBound(window.Since, 0, chromiumTimestamp),
+		historyBound(window.Until, math.MaxInt64, chromiumTimestamp),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query visits: %w", err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var entries []HistoryEntry
+	for rows.Next() {
+		var entry HistoryEntry
+		var lastVisit int64
+		if err := rows.Scan(&entry.URL, &entry.Title, &entry.VisitCount, &lastVisit); err != nil {
+			return nil, fmt.Errorf("failed to scan visits row: %w", err)
+		}
+		entry.LastVisit = chromiumMicros(lastVisit)
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
+
+// chromiumTimestamp converts t to microseconds since 1601-01-01, the inverse
+// of chromiumMicros
+func chromiumTimestamp(t time.Time) int64 {
+	windowsToUnixEpochDiff := int64(11644473600 * 1000000)
+	return t.UnixMicro() + windowsToUnixEpochDiff
+}
@@ -0,0 +1,126 @@
+package browser
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetChromiumHistory(t *testing.T) {
+	profileDir := t.TempDir()
+	createHistoryFixture(t, filepath.Join(profileDir, "History"))
+
+	chrome := newTestChromium(chromeVendor, filepath.Join(profileDir, "Bookmarks"))
+
+	entries, err := chrome.GetHistory(context.Background(), "Default", HistoryWindow{})
+	require.NoError(t, err)
+
+	assert.Equal(t, []HistoryEntry{
+		{
+			Title:      "Go",
+			URL:        "https://go.dev/",
+			LastVisit:  time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC).Local(),
+			VisitCount: 2,
+			Profile:    "Default",
+		},
+		{
+			Title:      "GitHub",
+			URL:        "https://github.com/",
+			LastVisit:  time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC).Local(),
+			VisitCount: 1,
+			Profile:    "Default",
+		},
+	}, entries, "Should skip hidden urls and order by last visit")
+}
+
+func TestGetChromiumHistoryWindow(t *testing.T) {
+	profileDir := t.TempDir()
+	createHistoryFixture(t, filepath.Join(profileDir, "History"))
+
+	chrome := newTestChromium(chromeVendor, filepath.Join(profileDir, "Bookmarks"))
+
+	entries, err := chrome.GetHistory(context.Background(), "Default", HistoryWindow{
+		Since: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+		Until: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, 2, len(entries))
+	assert.Equal(t, "https://go.dev/", entries[0].URL)
+	assert.Equal(t, 1, entries[0].VisitCount)
+	assert.Equal(t, time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC), entries[0].LastVisit.UTC())
+	assert.Equal(t, "https://github.com/", entries[1].URL)
+}
+
+func TestGetChromiumHistoryMissing(t *testing.T) {
+	chrome := newTestChromium(chromeVendor, filepath.Join(t.TempDir(), "Bookmarks"))
+
+	_, err := chrome.GetHistory(context.Background(), "Default", HistoryWindow{})
+	assert.ErrorIs(t, err, ErrNotInstalled)
+}
+
+func TestChromiumTimestamp(t *testing.T) {
+	date := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, int64(13353768000000000), chromiumTimestamp(date))
+	assert.True(t, date.Equal(chromiumMicros(chromiumTimestamp(date))))
+}
+
+// createHistoryFixture generates a minimal History database with the parts
+// of the urls/visits schema that are read
+func createHistoryFixture(t *testing.T, path string) {
+	t.Helper()
+
+	db, err := sql.Open("sqlite", path)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, db.Close())
+	}()
+
+	at := func(year int, month time.Month) int64 {
+		return chromiumTimestamp(time.Date(year, month, 1, 0, 0, 0, 0, time.UTC))
+	}
+
+	statements := []string{
+		`CREATE TABLE urls (id INTEGER PRIMARY KEY AUTOINCREMENT, url LONGVARCHAR, title LONGVARCHAR, visit_count INTEGER DEFAULT 0 NOT NULL, typed_count INTEGER DEFAULT 0 NOT NULL, last_visit_time INTEGER NOT NULL, hidden INTEGER DEFAULT 0 NOT NULL)`,
+		`CREATE TABLE visits (id INTEGER PRIMARY KEY, url INTEGER NOT NULL, visit_time INTEGER NOT NULL, from_visit INTEGER, transition INTEGER DEFAULT 0 NOT NULL, segment_id INTEGER, visit_duration INTEGER DEFAULT 0 NOT NULL)`,
+	}
+	for _, statement := range statements {
+		_, err := db.Exec(statement)
+		require.NoError(t, err)
+	}
+
+	urls := []struct {
+		id     int
+		url    string
+		title  string
+		hidden int
+	}{
+		{1, "https://github.com/", "GitHub", 0},
+		{2, "https://go.dev/", "Go", 0},
+		{3, "https://ads.example.com/frame", "", 1},
+	}
+	for _, u := range urls {
+		_, err := db.Exec(`INSERT INTO urls (id, url, title, last_visit_time, hidden) VALUES (?, ?, ?, 0, ?)`, u.id, u.url, u.title, u.hidden)
+		require.NoError(t, err)
+	}
+
+	visits := []struct {
+		url  int
+		time int64
+	}{
+		{1, at(2024, time.March)},
+		{2, at(2024, time.April)},
+		{2, at(2024, time.June)},
+		{3, at(2024, time.June)},
+	}
+	for _, v := range visits {
+		_, err := db.Exec(`INSERT INTO visits (url, visit_time) VALUES (?, ?)`, v.url, v.time)
+		require.NoError(t, err)
+	}
+}
@@ -0,0 +1,100 @@
+package browser
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// Session files are named after the time they were created, newer versions
+// keep them in a Sessions directory while older versions use fixed names in
+// the profile directory
+const (
+	chromiumSessionPrefix        = "Session_"
+	chromiumTabsPrefix           = "Tabs_"
+	chromiumLegacySessionFile    = "Current Session"
+	chromiumLegacyTabRestoreFile = "Current Tabs"
+)
+
+// GetTabs returns the tabs of the profile's last session, followed by its
+// recently closed tabs. The session is read as last written, so a running
+// browser may be a few seconds ahead of it.
+func (c *Chromium) GetTabs(ctx context.Context, profile string) ([]Tab, error) {
+	resolvedProfile := c.resolveProfile(profile)
+
+	profileDir, err := c.getProfileDir(resolvedProfile.Dir)
+	if err != nil {
+		c.logger.Debug("Failed to get profile directory", "browser", c.name, "error", err)
+		return nil, err
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	sessionPath, ok := latestChromiumSessionFile(profileDir, chromiumSessionPrefix, chromiumLegacySessionFile)
+	if !ok {
+		return nil, &BrowserError{
+			Browser: c.name,
+			Profile: resolvedProfile.Name,
+			Path:    profileDir,
+			Err:     fmt.Errorf("no session found: %w", ErrNotInstalled),
+		}
+	}
+
+	commands, err := readSNSSFile(sessionPath)
+	if err != nil {
+		c.logger.Debug("Failed to read session", "browser", c.name, "path", sessionPath, "error", err)
+		return nil, &BrowserError{Browser: c.name, Profile: resolvedProfile.Name, Path: sessionPath, Err: err}
+	}
+	tabs := parseChromiumSession(commands)
+
+	// recently closed tabs are a bonus, the session is still useful without them
+	if tabsPath, ok := latestChromiumSessionFile(profileDir, chromiumTabsPrefix, chromiumLegacyTabRestoreFile); ok {
+		commands, err := readSNSSFile(tabsPath)
+		if err != nil {
+			c.logger.Debug("Failed to read closed tabs", "browser", c.name, "path", tabsPath, "error", err)
+		} else {
+			tabs = append(tabs, parseChromiumTabRestore(commands)...)
+		}
+	}
+
+	for i := range tabs {
+		tabs[i].Profile = resolvedProfile.Name
+	}
+
+	return tabs, nil
+}
+
+// latestChromiumSessionFile returns the newest Sessions/<prefix>* file in
+// profileDir, falling back to the legacy file name
+func latestChromiumSessionFile(profileDir, prefix, legacyName string) (string, bool) {
+	matches, _ := filepath.Glob(filepath.Join(profileDir, "Sessions", prefix+"*"))
+	if len(matches) > 0 {
+		// the suffix is a fixed width timestamp, so names sort by age
+		sort.Strings(matches)
+		return matches[len(matches)-1], true
+	}
+
+	legacyPath := filepath.Join(profileDir, legacyName)
+	if _, err := os.Stat(legacyPath); err == nil {
+		return legacyPath, true
+	}
+
+	return "", false
+}
+
+// readSNSSFile reads the commands of the SNSS file at path
+func readSNSSFile(path string) ([]snssCommand, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	return readSNSSCommands(file)
+}
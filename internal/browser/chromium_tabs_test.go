@@ -0,0 +1,91 @@
+package browser
+
+import (
+	"context"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetChromiumTabs(t *testing.T) {
+	chrome := newTestChromium(chromeVendor, filepath.Join("testdata", "chromium", "Bookmarks"))
+
+	tabs, err := chrome.GetTabs(context.Background(), "Default")
+	require.NoError(t, err)
+
+	require.Equal(t, 5, len(tabs), "Should have 3 open and 2 closed tabs")
+
+	var open, closed []string
+	for _, tab := range tabs {
+		assert.Equal(t, "Default", tab.Profile)
+		if tab.Closed {
+			closed = append(closed, tab.URL)
+		} else {
+			open = append(open, tab.URL)
+		}
+	}
+
+	assert.Equal(t, []string{"https://github.com/", "https://example.com/two", "https://go.dev/doc/"}, open)
+	assert.Equal(t, []string{"https://news.example.com/story", "https://closed.example.com/"}, closed)
+}
+
+func TestGetChromiumTabsLegacySession(t *testing.T) {
+	profileDir := t.TempDir()
+	session, err := os.ReadFile(sessionFixture)
+	require.NoError(t, err)
+	err = os.WriteFile(filepath.Join(profileDir, "Current Session"), session, 0644)
+	require.NoError(t, err)
+
+	chrome := newTestChromium(chromeVendor, filepath.Join(profileDir, "Bookmarks"))
+
+	tabs, err := chrome.GetTabs(context.Background(), "Default")
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, len(tabs), "Should read Current Session without any closed tabs")
+}
+
+func TestLatestChromiumSessionFile(t *testing.T) {
+	profileDir := t.TempDir()
+	sessionsDir := filepath.Join(profileDir, "Sessions")
+	err := os.MkdirAll(sessionsDir, 0755)
+	require.NoError(t, err)
+
+	for _, name := range []string{"Session_13353768000000000", "Session_13353854400000000", "Tabs_13353768000000000"} {
+		err := os.WriteFile(filepath.Join(sessionsDir, name), nil, 0644)
+		require.NoError(t, err)
+	}
+
+	path, ok := latestChromiumSessionFile(profileDir, chromiumSessionPrefix, chromiumLegacySessionFile)
+	assert.True(t, ok)
+	assert.Equal(t, filepath.Join(sessionsDir, "Session_13353854400000000"), path)
+
+	_, ok = latestChromiumSessionFile(t.TempDir(), chromiumSessionPrefix, chromiumLegacySessionFile)
+	assert.False(t, ok)
+}
+
+func TestGetChromiumTabsNoSession(t *testing.T) {
+	chrome := newTestChromium(chromeVendor, filepath.Join(t.TempDir(), "Bookmarks"))
+
+	_, err := chrome.GetTabs(context.Background(), "Default")
+	assert.ErrorIs(t, err, ErrNotInstalled)
+}
+
+func TestGetAllTabs(t *testing.T) {
+	chrome := newTestChromium(chromeVendor, filepath.Join("testdata", "chromium", "Bookmarks"))
+	withRegisteredBrowsers(t,
+		chrome,
+		&fakeBrowser{name: "NoTabs"},
+		newTestChromium(braveVendor, filepath.Join(t.TempDir(), "Bookmarks")),
+	)
+
+	tabs, err := GetAllTabs(context.Background(), "Default")
+	require.NoError(t, err, "Should skip browsers without a session")
+
+	assert.Equal(t, []string{"Chrome"}, slices.Sorted(maps.Keys(tabs)))
+	assert.Equal(t, 5, len(tabs["Chrome"]))
+}
@@ -0,0 +1,562 @@
+package browser
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetChromiumBookmarksPathForPlatform(t *testing.T) {
+	testCases := []struct {
+		name     string
+		dirs     platformDirs
+		profile  string
+		expected string
+	}{
+		{
+			name:     "Windows path",
+			dirs:     platformDirs{goos: "windows", homeDir: `C:\Users\testuser`, localAppData: `C:\Users\testuser\AppData\Local`},
+			profile:  "Default",
+			expected: filepath.Join(`C:\Users\testuser\AppData\Local`, "Chromium", "User Data", "Default", "Bookmarks"),
+		},
+		{
+			name:     "macOS path",
+			dirs:     platformDirs{goos: "darwin", homeDir: "/Users/testuser"},
+			profile:  "Default",
+			expected: filepath.Join("/Users/testuser", "Library", "Application Support", "Chromium", "Default", "Bookmarks"),
+		},
+		{
+			name:     "Linux path",
+			dirs:     platformDirs{goos: "linux", homeDir: "/home/testuser"},
+			profile:  "Profile 1",
+			expected: filepath.Join("/home/testuser", ".config", "chromium", "Profile 1", "Bookmarks"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			path, err := chromiumBrowserVendor.bookmarksPathForPlatform(tc.dirs, tc.profile)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, path)
+		})
+	}
+}
+
+func TestGetChromiumBookmarksWithFixture(t *testing.T) {
+	dirs := platformDirs{goos: "linux", homeDir: t.TempDir()}
+	bookmarksFile := writeBookmarksFixture(t, chromiumBrowserVendor, dirs, "Default")
+
+	bookmarks, err := newTestChromium(chromiumBrowserVendor, bookmarksFile).GetBookmarks(context.Background(), "Default")
+	require.NoError(t, err)
+
+	assertSampleBookmarks(t, bookmarks)
+}
+
+func TestChromiumProfiles(t *testing.T) {
+	dirs := platformDirs{goos: "linux", homeDir: t.TempDir()}
+	writeLocalStateFixture(t, chromiumBrowserVendor, dirs)
+
+	profiles, err := newFixtureChromium(chromiumBrowserVendor, dirs).Profiles()
+	require.NoError(t, err)
+
+	assert.Equal(t, []Profile{
+		{Name: "Work", Dir: "Profile 3"},
+		{Name: "Personal", Dir: "Default"},
+		{Name: "Side Project", Dir: "Profile 1"},
+	}, profiles)
+}
+
+func TestChromiumGetBookmarksResolvesProfileName(t *testing.T) {
+	dirs := platformDirs{goos: "linux", homeDir: t.TempDir()}
+	writeLocalStateFixture(t, chromiumBrowserVendor, dirs)
+	writeBookmarksFixture(t, chromiumBrowserVendor, dirs, "Profile 3")
+
+	chromium := newFixtureChromium(chromiumBrowserVendor, dirs)
+
+	testCases := []struct {
+		name    string
+		profile string
+	}{
+		{name: "By name", profile: "Work"},
+		{name: "By name ignoring case", profile: "work"},
+		{name: "By directory", profile: "Profile 3"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			bookmarks, err := chromium.GetBookmarks(context.Background(), tc.profile)
+			require.NoError(t, err)
+
+			assertSampleBookmarks(t, bookmarks)
+			for _, bookmark := range bookmarks {
+				assert.Equal(t, "Work", bookmark.Profile)
+			}
+		})
+	}
+
+	_, err := chromium.GetBookmarks(context.Background(), "Personal")
+	assert.Error(t, err, "Personal has no Bookmarks file")
+}
+
+func TestChromiumGetBookmarksAllProfiles(t *testing.T) {
+	dirs := platformDirs{goos: "linux", homeDir: t.TempDir()}
+	writeLocalStateFixture(t, chromiumBrowserVendor, dirs)
+	writeBookmarksFixture(t, chromiumBrowserVendor, dirs, "Default")
+	writeBookmarksFixture(t, chromiumBrowserVendor, dirs, "Profile 3")
+
+	withRegisteredBrowsers(t, newFixtureChromium(chromiumBrowserVendor, dirs))
+
+	allBookmarks, err := GetAllBookmarks(context.Background(), AllProfiles)
+	require.NoError(t, err, "Side Project has no Bookmarks file, which is not a failure")
+
+	bookmarks := allBookmarks["Chromium"]
+	require.Equal(t, 6, len(bookmarks), "Should include bookmarks from both profiles with a Bookmarks file")
+	assertSampleBookmarks(t, bookmarks[:3])
+	assertSampleBookmarks(t, bookmarks[3:])
+	assert.Equal(t, "Work", bookmarks[0].Profile)
+	assert.Equal(t, "Personal", bookmarks[3].Profile)
+}
+
+func TestChromiumBookmarkRootsKeys(t *testing.T) {
+	var bookmarks ChromiumBookmarks
+	err := json.Unmarshal([]byte(createAllRootsBookmarksJSON()), &bookmarks)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"bookmark_bar", "other", "synced", "trash", "workspaces"}, bookmarks.Roots.Keys())
+	assert.Equal(t, "Bookmarks bar", bookmarks.Roots.DisplayName("bookmark_bar"))
+	assert.Equal(t, "Mobile bookmarks", bookmarks.Roots.DisplayName("synced"))
+	assert.Equal(t, "workspaces", bookmarks.Roots.DisplayName("workspaces"), "Falls back to the key when unnamed")
+}
+
+func TestGetChromiumBookmarksKeepsEveryURLNode(t *testing.T) {
+	bookmarksJSON := createAllRootsBookmarksJSON()
+
+	bookmarksFile := filepath.Join(t.TempDir(), "Bookmarks")
+	err := os.WriteFile(bookmarksFile, []byte(bookmarksJSON), 0644)
+	require.NoError(t, err)
+
+	bookmarks, err := newTestChromium(chromiumBrowserVendor, bookmarksFile).GetBookmarks(context.Background(), "Default")
+	require.NoError(t, err)
+
+	var raw any
+	err = json.Unmarshal([]byte(bookmarksJSON), &raw)
+	require.NoError(t, err)
+
+	urls := collectURLNodes(raw)
+	require.Equal(t, 6, len(urls), "Fixture should contain 6 url nodes")
+
+	parsedURLs := make([]string, 0, len(bookmarks))
+	for _, bookmark := range bookmarks {
+		parsedURLs = append(parsedURLs, bookmark.URL)
+	}
+	assert.ElementsMatch(t, urls, parsedURLs, "Every url node should become a bookmark")
+
+	folders := make(map[string]string, len(bookmarks))
+	for _, bookmark := range bookmarks {
+		folders[bookmark.URL] = bookmark.FolderPath
+	}
+	assert.Equal(t, "Bookmarks bar", folders["https://example.com"])
+	assert.Equal(t, filepath.Join("Bookmarks bar", "Work"), folders["https://github.com"])
+	assert.Equal(t, "Other bookmarks", folders["https://othersite.com"])
+	assert.Equal(t, "Mobile bookmarks", folders["https://mobile.example.com"])
+	assert.Equal(t, filepath.Join("Mobile bookmarks", "Reading"), folders["https://news.example.com"])
+	assert.Equal(t, "workspaces", folders["https://workspace.example.com"])
+}
+
+// collectURLNodes walks decoded JSON and returns the url of every node with
+// "type": "url", wherever it is in the document
+func collectURLNodes(value any) []string {
+	var urls []string
+
+	switch v := value.(type) {
+	case map[string]any:
+		if v["type"] == "url" {
+			if url, ok := v["url"].(string); ok {
+				urls = append(urls, url)
+			}
+		}
+		for _, child := range v {
+			urls = append(urls, collectURLNodes(child)...)
+		}
+	case []any:
+		for _, child := range v {
+			urls = append(urls, collectURLNodes(child)...)
+		}
+	}
+
+	return urls
+}
+
+func TestChromiumGetBookmarksMissingFile(t *testing.T) {
+	chromium := newTestChromium(chromiumBrowserVendor, filepath.Join(t.TempDir(), "Default", "Bookmarks"))
+
+	_, err := chromium.GetBookmarks(context.Background(), "Default")
+	assert.Error(t, err)
+}
+
+func TestProcessBookmarkNodes(t *testing.T) {
+	testCases := []struct {
+		name           string
+		nodes          []ChromiumBookmarkNode
+		folders        []string
+		expectedCount  int
+		expectedTitles []string
+		expectedURLs   []string
+		expectedPaths  []string
+	}{
+		{
+			name: "Simple URL bookmarks",
+			nodes: []ChromiumBookmarkNode{
+				{
+					Type:      "url",
+					Name:      "Example Site",
+					URL:       "https://example.com",
+					DateAdded: json.Number("13214422057039153"),
+				},
+			},
+			folders:        []string{"Bookmark Bar"},
+			expectedCount:  1,
+			expectedTitles: []string{"Example Site"},
+			expectedURLs:   []string{"https://example.com"},
+			expectedPaths:  []string{"Bookmark Bar"},
+		},
+		{
+			name: "Nested folder structure",
+			nodes: []ChromiumBookmarkNode{
+				{
+					Type: "folder",
+					Name: "Work",
+					Children: []ChromiumBookmarkNode{
+						{
+							Type:      "url",
+							Name:      "GitHub",
+							URL:       "https://github.com",
+							DateAdded: json.Number("13214422057039154"),
+						},
+					},
+				},
+			},
+			folders:        []string{"Bookmark Bar"},
+			expectedCount:  1,
+			expectedTitles: []string{"GitHub"},
+			expectedURLs:   []string{"https://github.com"},
+			expectedPaths:  []string{filepath.Join("Bookmark Bar", "Work")},
+		},
+		{
+			name: "Invalid DateAdded",
+			nodes: []ChromiumBookmarkNode{
+				{
+					Type:      "url",
+					Name:      "Bad Date",
+					URL:       "https://example.com",
+					DateAdded: json.Number("invalid"),
+				},
+			},
+			folders:       []string{"Bookmark Bar"},
+			expectedCount: 0,
+		},
+		{
+			name: "Never used bookmark",
+			nodes: []ChromiumBookmarkNode{
+				{
+					Type:         "url",
+					Name:         "Unused",
+					URL:          "https://unused.example.com",
+					DateAdded:    json.Number("13214422057039153"),
+					DateLastUsed: json.Number("0"),
+				},
+			},
+			folders:        []string{"Bookmark Bar"},
+			expectedCount:  1,
+			expectedTitles: []string{"Unused"},
+			expectedURLs:   []string{"https://unused.example.com"},
+			expectedPaths:  []string{"Bookmark Bar"},
+		},
+		{
+			name: "Empty folder",
+			nodes: []ChromiumBookmarkNode{
+				{
+					Type:     "folder",
+					Name:     "Empty Folder",
+					Children: []ChromiumBookmarkNode{},
+				},
+			},
+			folders:       []string{"Bookmark Bar"},
+			expectedCount: 0,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var bookmarks []Bookmark
+			processBookmarkNodes(&bookmarks, ChromiumBookmarkNode{Children: tc.nodes}, tc.folders)
+
+			assert.Equal(t, tc.expectedCount, len(bookmarks))
+
+			for i := range tc.expectedCount {
+				assert.Equal(t, tc.expectedTitles[i], bookmarks[i].Title)
+				assert.Equal(t, tc.expectedURLs[i], bookmarks[i].URL)
+				assert.Equal(t, tc.expectedPaths[i], bookmarks[i].FolderPath)
+			}
+		})
+	}
+}
+
+func TestProcessBookmarkNodesDateModified(t *testing.T) {
+	var bookmarks []Bookmark
+	processBookmarkNodes(&bookmarks, ChromiumBookmarkNode{Children: []ChromiumBookmarkNode{
+		{
+			Type:         "folder",
+			Name:         "Work",
+			DateModified: json.Number("13214422057039157"),
+			Children: []ChromiumBookmarkNode{
+				{Type: "url", Name: "Edited", URL: "https://edited.example.com", DateAdded: json.Number("13214422057039153"), DateModified: json.Number("13214422057039155")},
+				{Type: "url", Name: "Unedited", URL: "https://unedited.example.com", DateAdded: json.Number("13214422057039153")},
+			},
+		},
+		{Type: "url", Name: "Top", URL: "https://top.example.com", DateAdded: json.Number("13214422057039153")},
+	}}, []string{"Bookmark Bar"})
+
+	require.Len(t, bookmarks, 3)
+	folderModified := time.Date(2019, 10, 1, 16, 47, 37, 39157000, time.UTC)
+	assert.Equal(t, time.Date(2019, 10, 1, 16, 47, 37, 39155000, time.UTC), bookmarks[0].DateModified.UTC())
+	assert.Equal(t, folderModified, bookmarks[0].FolderDateModified.UTC())
+	assert.True(t, bookmarks[1].DateModified.IsZero(), "Should not take the folder's date_modified")
+	assert.Equal(t, folderModified, bookmarks[1].FolderDateModified.UTC())
+	assert.True(t, bookmarks[2].FolderDateModified.IsZero(), "The root folder has no date_modified")
+}
+
+// writeBookmarksFixture writes the sample Bookmarks JSON to the location the
+// vendor uses for profile on the given platform and returns its path
+func writeBookmarksFixture(t *testing.T, v chromiumVendor, dirs platformDirs, profile string) string {
+	t.Helper()
+
+	path, err := v.bookmarksPathForPlatform(dirs, profile)
+	require.NoError(t, err)
+
+	err = os.MkdirAll(filepath.Dir(path), 0755)
+	require.NoError(t, err)
+
+	err = os.WriteFile(path, []byte(createSampleBookmarksJSON()), 0644)
+	require.NoError(t, err)
+
+	return path
+}
+
+// newTestChromium creates a Chromium instance for the vendor that always reads
+// bookmarks from path and has no profiles
+func newTestChromium(v chromiumVendor, path string) *Chromium {
+	c := newChromium(v)
+	c.getBookmarksPath = func(profile string) (string, error) {
+		return path, nil
+	}
+	c.getLocalStatePath = func() (string, error) {
+		return "", errors.New("no local state")
+	}
+	c.getProfileDir = func(profile string) (string, error) {
+		return filepath.Dir(path), nil
+	}
+	return c
+}
+
+// newFixtureChromium creates a Chromium instance for the vendor that resolves
+// all of its files as if it was running on the given platform
+func newFixtureChromium(v chromiumVendor, dirs platformDirs) *Chromium {
+	c := newChromium(v)
+	c.getBookmarksPath = func(profile string) (string, error) {
+		return v.bookmarksPathForPlatform(dirs, profile)
+	}
+	c.getLocalStatePath = func() (string, error) {
+		userDataDir, err := v.userDataDirForPlatform(dirs)
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(userDataDir, "Local State"), nil
+	}
+	c.getProfileDir = func(profile string) (string, error) {
+		return v.profileDirForPlatform(dirs, profile)
+	}
+	return c
+}
+
+// writeLocalStateFixture writes a "Local State" file describing three
+// profiles, of which only "Profile 1" is missing from profiles_order
+func writeLocalStateFixture(t *testing.T, v chromiumVendor, dirs platformDirs) {
+	t.Helper()
+
+	userDataDir, err := v.userDataDirForPlatform(dirs)
+	require.NoError(t, err)
+
+	err = os.MkdirAll(userDataDir, 0755)
+	require.NoError(t, err)
+
+	localState := `{
+		"profile": {
+			"info_cache": {
+				"Default": {"name": "Personal"},
+				"Profile 1": {"name": "Side Project"},
+				"Profile 3": {"name": "Work"}
+			},
+			"last_used": "Profile 3",
+			"profiles_order": ["Profile 3", "Default"]
+		}
+	}`
+
+	err = os.WriteFile(filepath.Join(userDataDir, "Local State"), []byte(localState), 0644)
+	require.NoError(t, err)
+}
+
+// assertSampleBookmarks validates bookmarks parsed from createSampleBookmarksJSON
+func assertSampleBookmarks(t *testing.T, bookmarks []Bookmark) {
+	t.Helper()
+
+	assert.Equal(t, 3, len(bookmarks), "Should have 3 bookmarks total")
+
+	// Check bookmark bar entry
+	assert.Equal(t, "Example Site", bookmarks[0].Title)
+	assert.Equal(t, "https://example.com", bookmarks[0].URL)
+	assert.Equal(t, "Bookmark Bar", bookmarks[0].FolderPath)
+	assert.Equal(t, "1", bookmarks[0].ID)
+	assert.Equal(t, "guid1", bookmarks[0].GUID)
+	assert.Equal(t, time.Date(2019, 10, 1, 16, 47, 37, 39153000, time.UTC), bookmarks[0].DateLastUsed.UTC())
+	assert.Equal(t, map[string]string{"last_visited_desktop": "13214422057039153"}, bookmarks[0].MetaInfo)
+
+	// Check nested folder entry
+	assert.Equal(t, "GitHub", bookmarks[1].Title)
+	assert.Equal(t, "https://github.com", bookmarks[1].URL)
+	assert.Equal(t, filepath.Join("Bookmark Bar", "Work"), bookmarks[1].FolderPath)
+	assert.Equal(t, "guid2", bookmarks[1].GUID)
+	assert.True(t, bookmarks[1].DateModified.IsZero(), "Should not take the folder's date_modified")
+	assert.Equal(t, time.Date(2019, 10, 1, 16, 47, 37, 39157000, time.UTC), bookmarks[1].FolderDateModified.UTC())
+
+	// Check "Other Bookmarks" entry
+	assert.Equal(t, "Other Site", bookmarks[2].Title)
+	assert.Equal(t, "https://othersite.com", bookmarks[2].URL)
+	assert.Equal(t, "Other Bookmarks", bookmarks[2].FolderPath)
+}
+
+// Helper function to create sample bookmarks JSON
+func createSampleBookmarksJSON() string {
+	return `{
+		"checksum": "test-checksum",
+		"roots": {
+			"bookmark_bar": {
+				"children": [
+					{
+						"date_added": "13214422057039153",
+						"date_last_used": "13214422057039153",
+						"guid": "guid1",
+						"id": "1",
+						"meta_info": {
+							"last_visited_desktop": "13214422057039153"
+						},
+						"name": "Example Site",
+						"type": "url",
+						"url": "https://example.com"
+					},
+					{
+						"children": [
+							{
+								"date_added": "13214422057039154",
+								"date_last_used": "13214422057039154",
+								"guid": "guid2",
+								"id": "2",
+								"name": "GitHub",
+								"type": "url",
+								"url": "https://github.com"
+							}
+						],
+						"date_added": "13214422057039155",
+						"date_last_used": "13214422057039155",
+						"date_modified": "13214422057039157",
+						"guid": "guid3",
+						"id": "3",
+						"name": "Work",
+						"type": "folder"
+					}
+				],
+				"name": "Bookmark Bar",
+				"type": "folder"
+			},
+			"other": {
+				"children": [
+					{
+						"date_added": "13214422057039156",
+						"date_last_used": "13214422057039156",
+						"guid": "guid4",
+						"id": "4",
+						"name": "Other Site",
+						"type": "url",
+						"url": "https://othersite.com"
+					}
+				],
+				"name": "Other Bookmarks",
+				"type": "folder"
+			}
+		},
+		"version": 1
+	}`
+}
+
+// Helper function to create bookmarks JSON using the synced root, vendor
+// specific roots and a root that is not a bookmark node
+func createAllRootsBookmarksJSON() string {
+	return `{
+		"checksum": "test-checksum",
+		"roots": {
+			"bookmark_bar": {
+				"children": [
+					{"date_added": "13214422057039153", "guid": "guid1", "id": "1", "name": "Example Site", "type": "url", "url": "https://example.com"},
+					{
+						"children": [
+							{"date_added": "13214422057039154", "guid": "guid2", "id": "2", "name": "GitHub", "type": "url", "url": "https://github.com"}
+						],
+						"date_added": "13214422057039155", "guid": "guid3", "id": "3", "name": "Work", "type": "folder"
+					}
+				],
+				"name": "Bookmarks bar",
+				"type": "folder"
+			},
+			"other": {
+				"children": [
+					{"date_added": "13214422057039156", "guid": "guid4", "id": "4", "name": "Other Site", "type": "url", "url": "https://othersite.com"}
+				],
+				"name": "Other bookmarks",
+				"type": "folder"
+			},
+			"synced": {
+				"children": [
+					{"date_added": "13214422057039157", "guid": "guid5", "id": "5", "name": "Mobile Site", "type": "url", "url": "https://mobile.example.com"},
+					{
+						"children": [
+							{"date_added": "13214422057039158", "guid": "guid6", "id": "6", "name": "News", "type": "url", "url": "https://news.example.com"}
+						],
+						"date_added": "13214422057039159", "guid": "guid7", "id": "7", "name": "Reading", "type": "folder"
+					}
+				],
+				"name": "Mobile bookmarks",
+				"type": "folder"
+			},
+			"trash": {
+				"children": [],
+				"name": "Trash",
+				"type": "folder"
+			},
+			"workspaces": {
+				"children": [
+					{"date_added": "13214422057039160", "guid": "guid8", "id": "8", "name": "Workspace Site", "type": "url", "url": "https://workspace.example.com"}
+				],
+				"type": "folder"
+			},
+			"sync_transaction_version": "42"
+		},
+		"version": 1
+	}`
+}
@@ -0,0 +1,24 @@
+package browser
+
+import (
+	"github.com/malleatus/tamjaweb/internal/logger"
+)
+
+var edgeLogger = logger.New("browser:edge")
+
+var edgeVendor = chromiumVendor{
+	name:    "Edge",
+	windows: []string{"Microsoft", "Edge", "User Data"},
+	darwin:  []string{"Microsoft Edge"},
+	linux:   []string{"microsoft-edge"},
+}
+
+func init() {
+	edgeLogger.Info("Registering Microsoft Edge browser")
+	RegisterBrowser(NewEdge())
+}
+
+// NewEdge creates a new Microsoft Edge browser instance with the default path provider
+func NewEdge() *Chromium {
+	return newChromium(edgeVendor)
+}
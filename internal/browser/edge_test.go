@@ -0,0 +1,57 @@
+package browser
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetEdgeBookmarksPathForPlatform(t *testing.T) {
+	testCases := []struct {
+		name     string
+		dirs     platformDirs
+		profile  string
+		expected string
+	}{
+		{
+			name:     "Windows path",
+			dirs:     platformDirs{goos: "windows", homeDir: `C:\Users\testuser`, localAppData: `C:\Users\testuser\AppData\Local`},
+			profile:  "Default",
+			expected: filepath.Join(`C:\Users\testuser\AppData\Local`, "Microsoft", "Edge", "User Data", "Default", "Bookmarks"),
+		},
+		{
+			name:     "macOS path",
+			dirs:     platformDirs{goos: "darwin", homeDir: "/Users/testuser"},
+			profile:  "Default",
+			expected: filepath.Join("/Users/testuser", "Library", "Application Support", "Microsoft Edge", "Default", "Bookmarks"),
+		},
+		{
+			name:     "Linux path",
+			dirs:     platformDirs{goos: "linux", homeDir: "/home/testuser"},
+			profile:  "Default",
+			expected: filepath.Join("/home/testuser", ".config", "microsoft-edge", "Default", "Bookmarks"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			path, err := edgeVendor.bookmarksPathForPlatform(tc.dirs, tc.profile)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, path)
+		})
+	}
+}
+
+func TestGetEdgeBookmarksWithFixture(t *testing.T) {
+	dirs := platformDirs{goos: "linux", homeDir: t.TempDir()}
+	bookmarksFile := writeBookmarksFixture(t, edgeVendor, dirs, "Default")
+
+	bookmarks, err := newTestChromium(edgeVendor, bookmarksFile).GetBookmarks(context.Background(), "Default")
+	require.NoError(t, err)
+
+	assert.Equal(t, "Edge", NewEdge().Name())
+	assertSampleBookmarks(t, bookmarks)
+}
@@ -0,0 +1,132 @@
+package browser
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ErrNotInstalled is wrapped by errors for browsers (or profiles) that have no
+// data on this machine. These are expected, every user has some of the
+// registered browsers missing, and are not reported as failures.
+var ErrNotInstalled = errors.New("not installed")
+
+// BrowserError describes a failure to load data from a single browser profile
+type BrowserError struct {
+	Browser string
+	Profile string
+	Path    string
+	Err     error
+}
+
+func (e *BrowserError) Error() string {
+	var sb strings.Builder
+
+	sb.WriteString(e.Browser)
+	if e.Profile != "" {
+		fmt.Fprintf(&sb, " (%s)", e.Profile)
+	}
+	sb.WriteString(": ")
+	if e.Path != "" {
+		fmt.Fprintf(&sb, "%s: ", e.Path)
+	}
+	sb.WriteString(e.Err.Error())
+
+	return sb.String()
+}
+
+func (e *BrowserError) Unwrap() error {
+	return e.Err
+}
+
+// BrowserErrors collects the failures of every browser profile that could not
+// be loaded
+type BrowserErrors []*BrowserError
+
+func (e BrowserErrors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, err := range e {
+		messages = append(messages, err.Error())
+	}
+
+	return fmt.Sprintf("failed to load %d browser profile(s): %s", len(e), strings.Join(messages, "; "))
+}
+
+func (e BrowserErrors) Unwrap() []error {
+	errs := make([]error, 0, len(e))
+	for _, err := range e {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// toBrowserErrors flattens err into BrowserErrors, filling in the browser and
+// profile for errors that don't already carry them
+func toBrowserErrors(browserName, profile string, err error) BrowserErrors {
+	var browserErrs BrowserErrors
+	if errors.As(err, &browserErrs) {
+		return browserErrs
+	}
+
+	var browserErr *BrowserError
+	if !errors.As(err, &browserErr) {
+		browserErr = &BrowserError{Err: err}
+	}
+
+	if browserErr.Browser == "" {
+		browserErr.Browser = browserName
+	}
+	if browserErr.Profile == "" {
+		browserErr.Profile = profile
+	}
+
+	return BrowserErrors{browserErr}
+}
+
+// ErrorReporter prints a summary of the browser profiles that failed to load
+// to W. The failures are only turned into an error by Err with Strict, once
+// everything that could be loaded was, so that partial results are still
+// shown before failing.
+type ErrorReporter struct {
+	W      io.Writer
+	Strict bool
+	// failed counts the profiles that failed, what lists what they failed
+	// to load
+	failed int
+	what   []string
+}
+
+// Report prints the profiles err says failed to load what (e.g. "bookmarks").
+// Errors that aren't BrowserErrors are returned as they are.
+func (r *ErrorReporter) Report(err error, what string) error {
+	var browserErrs BrowserErrors
+	if !errors.As(err, &browserErrs) || len(browserErrs) == 0 {
+		return err
+	}
+
+	r.failed += len(browserErrs)
+	r.what = append(r.what, what)
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Warning: failed to load %s from %d browser profile(s):\n", what, len(browserErrs))
+	for _, browserErr := range browserErrs {
+		fmt.Fprintf(&sb, "  - %s\n", browserErr)
+	}
+
+	_, err = io.WriteString(r.W, sb.String())
+	return err
+}
+
+// Err returns an error for the reported failures with Strict, nil otherwise
+func (r *ErrorReporter) Err() error {
+	if !r.Strict || r.failed == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("failed to load %s from %d browser profile(s)", strings.Join(r.what, ", "), r.failed)
+}
+
+// ErrProfileInUse is returned when changing the data of a profile the browser
+// has open, which the browser would overwrite
+var ErrProfileInUse = errors.New("profile is in use by a running browser")
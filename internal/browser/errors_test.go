@@ -0,0 +1,63 @@
+package browser
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorReporter(t *testing.T) {
+	bookmarksErr := BrowserErrors{
+		{Browser: "Chrome", Profile: "Work", Path: "/home/user/.config/google-chrome/Profile 3/Bookmarks", Err: errors.New("unexpected end of JSON input")},
+		{Browser: "Firefox", Profile: "default-release", Err: errors.New("permission denied")},
+	}
+	historyErr := BrowserErrors{
+		{Browser: "Firefox", Profile: "default-release", Err: errors.New("database is locked")},
+	}
+
+	testCases := []struct {
+		name        string
+		strict      bool
+		expectedErr string
+	}{
+		{name: "Only warns"},
+		{name: "Strict", strict: true, expectedErr: "failed to load bookmarks, history from 3 browser profile(s)"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var w bytes.Buffer
+			reporter := ErrorReporter{W: &w, Strict: tc.strict}
+
+			require.NoError(t, reporter.Report(bookmarksErr, "bookmarks"))
+			require.NoError(t, reporter.Report(nil, "tabs"))
+			require.NoError(t, reporter.Report(historyErr, "history"))
+
+			assert.Equal(t, `Warning: failed to load bookmarks from 2 browser profile(s):
+  - Chrome (Work): /home/user/.config/google-chrome/Profile 3/Bookmarks: unexpected end of JSON input
+  - Firefox (default-release): permission denied
+Warning: failed to load history from 1 browser profile(s):
+  - Firefox (default-release): database is locked
+`, w.String())
+
+			if tc.expectedErr == "" {
+				assert.NoError(t, reporter.Err())
+			} else {
+				assert.EqualError(t, reporter.Err(), tc.expectedErr)
+			}
+		})
+	}
+}
+
+func TestErrorReporterOtherErrors(t *testing.T) {
+	var w bytes.Buffer
+	reporter := ErrorReporter{W: &w, Strict: true}
+
+	err := errors.New("context canceled")
+	assert.Equal(t, err, reporter.Report(err, "bookmarks"))
+	assert.Empty(t, w.String())
+	assert.NoError(t, reporter.Err(), "Should only fail for browsers that failed to load")
+}
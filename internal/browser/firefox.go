@@ -0,0 +1,412 @@
+package browser
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/malleatus/tamjaweb/internal/logger"
+)
+
+var firefoxLogger = logger.New("browser:firefox")
+
+// firefoxTypeBookmark is the moz_bookmarks.type of a url bookmark
+const firefoxTypeBookmark = 1
+
+// GUIDs of the built in folders that are not shown as part of a folder path.
+// Tags are modelled as folders, but they are not where bookmarks live.
+const (
+	firefoxRootFolder = "root________"
+	firefoxTagsFolder = "tags________"
+)
+
+// firefoxRootNames maps the GUIDs of Firefox's built in bookmark roots to the
+// names Firefox displays for them
+var firefoxRootNames = map[string]string{
+	"menu________": "Bookmarks Menu",
+	"toolbar_____": "Bookmarks Toolbar",
+	"unfiled_____": "Other Bookmarks",
+	"mobile______": "Mobile Bookmarks",
+}
+
+type Firefox struct {
+	getProfilesDir func() (string, error)
+}
+
+func init() {
+	firefoxLogger.Info("Registering Firefox browser")
+	RegisterBrowser(NewFirefox())
+}
+
+// NewFirefox creates a new Firefox browser instance with the default path provider
+func NewFirefox() *Firefox {
+	return &Firefox{
+		getProfilesDir: getFirefoxProfilesDir,
+	}
+}
+
+func (f *Firefox) Name() string {
+	return "Firefox"
+}
+
+// Profiles lists the profiles recorded in profiles.ini
+func (f *Firefox) Profiles() ([]Profile, error) {
+	firefoxProfiles, err := f.readProfiles()
+	if err != nil {
+		return nil, err
+	}
+
+	profiles := make([]Profile, 0, len(firefoxProfiles))
+	for _, p := range firefoxProfiles {
+		profiles = append(profiles, Profile{Name: p.Name, Dir: filepath.Base(p.Path)})
+	}
+
+	return profiles, nil
+}
+
+// GetPlacesPath is exposed for testing
+func (f *Firefox) GetPlacesPath(profile string) (string, error) {
+	firefoxProfile, err := f.findProfile(profile)
+	if err != nil {
+		return "", err
+	}
+
+	return firefoxProfile.placesPath()
+}
+
+// firefoxBookmarkRow is a single row of moz_bookmarks joined with moz_places
+type firefoxBookmarkRow struct {
+	ID           int64
+	Parent       int64
+	Type         int
+	GUID         string
+	Title        string
+	URL          string
+	DateAdded    int64
+	LastModified int64
+	LastVisit    int64
+}
+
+func (f *Firefox) GetBookmarks(ctx context.Context, profile string) ([]Bookmark, error) {
+	firefoxProfile, err := f.findProfile(profile)
+	if err != nil {
+		firefoxLogger.Debug("Failed to find Firefox profile", "profile", profile, "error", err)
+		return nil, err
+	}
+
+	placesPath, err := firefoxProfile.placesPath()
+	if err != nil {
+		firefoxLogger.Debug("Failed to get Firefox places path", "error", err)
+		return nil, err
+	}
+
+	rows, err := readFirefoxBookmarkRows(ctx, placesPath)
+	if err != nil {
+		firefoxLogger.Debug("Failed to read Firefox bookmarks", "path", placesPath, "error", err)
+		return nil, &BrowserError{Browser: f.Name(), Profile: firefoxProfile.Name, Path: placesPath, Err: err}
+	}
+
+	bookmarks := buildFirefoxBookmarks(rows)
+	for i := range bookmarks {
+		bookmarks[i].Profile = firefoxProfile.Name
+	}
+
+	return bookmarks, nil
+}
+
+// readFirefoxBookmarkRows reads every bookmark and folder from a copy of places.sqlite
+func readFirefoxBookmarkRows(ctx context.Context, placesPath string) ([]firefoxBookmarkRow, error) {
+	db, cleanup, err := openSQLiteCopy(placesPath)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT b.id, b.parent, b.type, IFNULL(b.guid, ''), IFNULL(b.title, ''), IFNULL(p.url, ''), IFNULL(b.dateAdded, 0),
+			IFNULL(b.lastModified, 0), IFNULL(p.last_visit_date, 0)
+		FROM moz_bookmarks b
+		LEFT JOIN moz_places p ON b.fk = p.id
+		ORDER BY b.parent, b.position
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query moz_bookmarks: %w", err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var result []firefoxBookmarkRow
+	for rows.Next() {
+		var row firefoxBookmarkRow
+		if err := rows.Scan(&row.ID, &row.Parent, &row.Type, &row.GUID, &row.Title, &row.URL, &row.DateAdded, &row.LastModified, &row.LastVisit); err != nil {
+			return nil, fmt.Errorf("failed to scan moz_bookmarks row: %w", err)
+		}
+		result = append(result, row)
+	}
+
+	return result, rows.Err()
+}
+
+// buildFirefoxBookmarks converts moz_bookmarks rows into bookmarks, rebuilding
+// each bookmark's folder path from its parent chain
+func buildFirefoxBookmarks(rows []firefoxBookmarkRow) []Bookmark {
+	byID := make(map[int64]firefoxBookmarkRow, len(rows))
+	for _, row := range rows {
+		byID[row.ID] = row
+	}
+
+	var bookmarks []Bookmark
+	for _, row := range rows {
+		if row.Type != firefoxTypeBookmark || row.URL == "" {
+			continue
+		}
+
+		// place: URLs are saved searches (e.g. "Most Visited"), not pages
+		if strings.HasPrefix(row.URL, "place:") {
+			continue
+		}
+
+		folders, ok := firefoxFolders(byID, row.Parent)
+		if !ok {
+			// lives under the tags root
+			continue
+		}
+
+		bookmarks = append(bookmarks, Bookmark{
+			ID:    strconv.FormatInt(row.ID, 10),
+			GUID:  row.GUID,
+			Title: row.Title,
+			URL:   row.URL,
+			// PRTime is microseconds since the unix epoch
+			DateAdded: firefoxTime(row.DateAdded),
+			// Firefox tracks visits per page, not per bookmark
+			DateLastUsed:       firefoxTime(row.LastVisit),
+			DateModified:       firefoxTime(row.LastModified),
+			FolderDateModified: firefoxTime(byID[row.Parent].LastModified),
+			Folders:            folders,
+			FolderPath:         filepath.Join(folders...),
+		})
+	}
+
+	return bookmarks
+}
+
+// firefoxTime converts a PRTime to a time.Time, 0 meaning never
+func firefoxTime(prTime int64) time.Time {
+	if prTime == 0 {
+		return time.Time{}
+	}
+	return time.UnixMicro(prTime)
+}
+
+// firefoxFolders walks up from the folder with the given id to the root,
+// returning the folder names outermost first. Returns false when the folder
+// is part of the tags tree.
+func firefoxFolders(byID map[int64]firefoxBookmarkRow, id int64) ([]string, bool) {
+	var parts []string
+
+	// guard against cycles in a corrupt database
+	for range len(byID) {
+		folder, ok := byID[id]
+		if !ok {
+			break
+		}
+
+		if folder.GUID == firefoxTagsFolder {
+			return nil, false
+		}
+		if folder.GUID == firefoxRootFolder {
+			break
+		}
+
+		name := folder.Title
+		if rootName, ok := firefoxRootNames[folder.GUID]; ok {
+			name = rootName
+		}
+		parts = append([]string{name}, parts...)
+
+		id = folder.Parent
+	}
+
+	return parts, true
+}
+
+// firefoxProfile is a profile entry from profiles.ini
+type firefoxProfile struct {
+	Name    string
+	Path    string
+	Default bool
+}
+
+// firefoxProfilesDirForPlatform returns the directory containing profiles.ini for the specified platform
+func firefoxProfilesDirForPlatform(dirs platformDirs) (string, error) {
+	switch dirs.goos {
+	case "windows":
+		return filepath.Join(dirs.appData, "Mozilla", "Firefox"), nil
+	case "darwin":
+		return filepath.Join(dirs.homeDir, "Library", "Application Support", "Firefox"), nil
+	case "linux":
+		return filepath.Join(dirs.homeDir, ".mozilla", "firefox"), nil
+	default:
+		return "", fmt.Errorf("unsupported operating system: %s: %w", dirs.goos, ErrNotInstalled)
+	}
+}
+
+// readFirefoxProfiles parses the profiles.ini in profilesDir. Profile paths
+// are resolved to absolute paths.
+func readFirefoxProfiles(profilesDir string) ([]firefoxProfile, error) {
+	file, err := os.Open(filepath.Join(profilesDir, "profiles.ini"))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	sections := map[string]map[string]string{}
+	var order []string
+	var current map[string]string
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, ";") || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			name := line[1 : len(line)-1]
+			current = map[string]string{}
+			sections[name] = current
+			order = append(order, name)
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if ok && current != nil {
+			current[strings.TrimSpace(key)] = strings.TrimSpace(value)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read profiles.ini: %w", err)
+	}
+
+	resolve := func(path, isRelative string) string {
+		path = filepath.FromSlash(path)
+		if isRelative == "1" && !filepath.IsAbs(path) {
+			return filepath.Join(profilesDir, path)
+		}
+		return path
+	}
+
+	// Since Firefox 67 each installation has its own default profile, which
+	// takes precedence over the legacy Default=1 marker
+	installDefault := ""
+	for _, name := range order {
+		if strings.HasPrefix(name, "Install") && sections[name]["Default"] != "" {
+			installDefault = resolve(sections[name]["Default"], "1")
+			break
+		}
+	}
+
+	var profiles []firefoxProfile
+	for _, name := range order {
+		section := sections[name]
+		if !strings.HasPrefix(name, "Profile") || section["Path"] == "" {
+			continue
+		}
+
+		profile := firefoxProfile{
+			Name: section["Name"],
+			Path: resolve(section["Path"], section["IsRelative"]),
+		}
+		if installDefault != "" {
+			profile.Default = profile.Path == installDefault
+		} else {
+			profile.Default = section["Default"] == "1"
+		}
+		profiles = append(profiles, profile)
+	}
+
+	return profiles, nil
+}
+
+// findFirefoxProfile picks the profile matching name. "Default" selects the
+// profile Firefox starts with, any other value is matched against the
+// profile's name or directory name.
+func findFirefoxProfile(profiles []firefoxProfile, name string) (firefoxProfile, error) {
+	if len(profiles) == 0 {
+		return firefoxProfile{}, fmt.Errorf("no Firefox profiles found: %w", ErrNotInstalled)
+	}
+
+	if name == "Default" {
+		for _, profile := range profiles {
+			if profile.Default {
+				return profile, nil
+			}
+		}
+		return profiles[0], nil
+	}
+
+	for _, profile := range profiles {
+		if strings.EqualFold(profile.Name, name) || filepath.Base(profile.Path) == name {
+			return profile, nil
+		}
+	}
+
+	return firefoxProfile{}, fmt.Errorf("Firefox profile %q not found: %w", name, ErrNotInstalled)
+}
+
+// placesPath returns the path to the profile's places.sqlite
+func (p firefoxProfile) placesPath() (string, error) {
+	path := filepath.Join(p.Path, "places.sqlite")
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return "", fmt.Errorf("Firefox bookmarks not found at %s: %w", path, ErrNotInstalled)
+	}
+
+	return path, nil
+}
+
+// readProfiles reads the profiles from the browser's profiles.ini
+func (f *Firefox) readProfiles() ([]firefoxProfile, error) {
+	profilesDir, err := f.getProfilesDir()
+	if err != nil {
+		return nil, err
+	}
+
+	profiles, err := readFirefoxProfiles(profilesDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("Firefox profiles not found at %s: %w", profilesDir, ErrNotInstalled)
+		}
+		return nil, err
+	}
+
+	return profiles, nil
+}
+
+// findProfile reads the browser's profiles and picks the one matching name
+func (f *Firefox) findProfile(name string) (firefoxProfile, error) {
+	profiles, err := f.readProfiles()
+	if err != nil {
+		return firefoxProfile{}, err
+	}
+
+	return findFirefoxProfile(profiles, name)
+}
+
+// getFirefoxProfilesDir returns the directory containing profiles.ini based on OS
+func getFirefoxProfilesDir() (string, error) {
+	dirs, err := currentPlatformDirs()
+	if err != nil {
+		return "", err
+	}
+
+	return firefoxProfilesDirForPlatform(dirs)
+}
@@ -0,0 +1,77 @@
+package browser
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+)
+
+// GetHistory returns the pages visited within window from the profile's
+// places.sqlite, most recently visited first
+func (f *Firefox) GetHistory(ctx context.Context, profile string, window HistoryWindow) ([]HistoryEntry, error) {
+	firefoxProfile, err := f.findProfile(profile)
+	if err != nil {
+		firefoxLogger.Debug("Failed to find Firefox profile", "profile", profile, "error", err)
+		return nil, err
+	}
+
+	placesPath, err := firefoxProfile.placesPath()
+	if err != nil {
+		firefoxLogger.Debug("Failed to get Firefox places path", "error", err)
+		return nil, err
+	}
+
+	entries, err := readFirefoxHistory(ctx, placesPath, window)
+	if err != nil {
+		firefoxLogger.Debug("Failed to read Firefox history", "path", placesPath, "error", err)
+		return nil, &BrowserError{Browser: f.Name(), Profile: firefoxProfile.Name, Path: placesPath, Err: err}
+	}
+
+	for i := range entries {
+		entries[i].Profile = firefoxProfile.Name
+	}
+
+	return entries, nil
+}
+
+// readFirefoxHistory sums up the visits of each place within window from a
+// copy of places.sqlite
+func readFirefoxHistory(ctx context.Context, placesPath string, window HistoryWindow) ([]HistoryEntry, error) {
+	db, cleanup, err := openSQLiteCopy(placesPath)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT p.url, IFNULL(p.title, ''), COUNT(v.id), MAX(v.visit_date)
+		FROM moz_places p
+		JOIN moz_historyvisits v ON v.place_id = p.id
+		WHERE p.hidden = 0 AND v.visit_date >= ?1 AND v.visit_date < ?2
+		GROUP BY p.id
+		ORDER BY MAX(v.visit_date) DESC
+	`,
+		historyBound(window.Since, 0, time.Time.UnixMicro),
+		historyBound(window.Until, math.MaxInt64, time.Time.UnixMicro),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query moz_historyvisits: %w", err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var entries []HistoryEntry
+	for rows.Next() {
+		var entry HistoryEntry
+		var lastVisit int64
+		if err := rows.Scan(&entry.URL, &entry.Title, &entry.VisitCount, &lastVisit); err != nil {
+			return nil, fmt.Errorf("failed to scan moz_historyvisits row: %w", err)
+		}
+		entry.LastVisit = firefoxTime(lastVisit)
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
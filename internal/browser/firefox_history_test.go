@@ -0,0 +1,78 @@
+package browser
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetFirefoxHistory(t *testing.T) {
+	firefox := newTestFirefox(t)
+
+	testCases := []struct {
+		name           string
+		window         HistoryWindow
+		expectedURLs   []string
+		expectedCounts []int
+	}{
+		{
+			name:           "All history",
+			expectedURLs:   []string{"https://example.com/", "https://github.com/"},
+			expectedCounts: []int{3, 1},
+		},
+		{
+			name:           "Since",
+			window:         HistoryWindow{Since: time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)},
+			expectedURLs:   []string{"https://example.com/"},
+			expectedCounts: []int{2},
+		},
+		{
+			name: "Until",
+			window: HistoryWindow{
+				Until: time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC),
+			},
+			expectedURLs:   []string{"https://github.com/", "https://example.com/"},
+			expectedCounts: []int{1, 1},
+		},
+		{
+			name: "Empty window",
+			window: HistoryWindow{
+				Since: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			entries, err := firefox.GetHistory(context.Background(), "Default", tc.window)
+			require.NoError(t, err)
+
+			var urls []string
+			var counts []int
+			for _, entry := range entries {
+				urls = append(urls, entry.URL)
+				counts = append(counts, entry.VisitCount)
+				assert.Equal(t, "default-release", entry.Profile)
+			}
+
+			assert.Equal(t, tc.expectedURLs, urls, "Should skip hidden places")
+			assert.Equal(t, tc.expectedCounts, counts)
+		})
+	}
+}
+
+func TestGetFirefoxHistoryLastVisit(t *testing.T) {
+	firefox := newTestFirefox(t)
+
+	entries, err := firefox.GetHistory(context.Background(), "Default", HistoryWindow{
+		Until: time.Date(2024, 5, 15, 0, 0, 0, 0, time.UTC),
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, 2, len(entries))
+	assert.Equal(t, "Example", entries[0].Title)
+	assert.Equal(t, time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), entries[0].LastVisit.UTC(), "Should only consider visits within the window")
+}
@@ -0,0 +1,199 @@
+package browser
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// Firefox keeps writing its session to recovery.jsonlz4 while running and
+// moves it to sessionstore.jsonlz4 when it shuts down cleanly
+var firefoxSessionFiles = []string{
+	filepath.Join("sessionstore-backups", "recovery.jsonlz4"),
+	"sessionstore.jsonlz4",
+}
+
+// firefoxSession represents the parts of the session store that describe tabs
+type firefoxSession struct {
+	Windows       []firefoxSessionWindow `json:"windows"`
+	ClosedWindows []firefoxSessionWindow `json:"_closedWindows"`
+}
+
+type firefoxSessionWindow struct {
+	Tabs []firefoxSessionTab `json:"tabs"`
+	// Selected is the 1 based index of the selected tab
+	Selected   int                       `json:"selected"`
+	ClosedTabs []firefoxSessionClosedTab `json:"_closedTabs"`
+	// ClosedAt is set for closed windows, in milliseconds since the unix epoch
+	ClosedAt int64 `json:"closedAt"`
+}
+
+type firefoxSessionTab struct {
+	Entries []firefoxSessionEntry `json:"entries"`
+	// Index is the 1 based index of the entry the tab is showing
+	Index int `json:"index"`
+	// LastAccessed is in milliseconds since the unix epoch
+	LastAccessed int64 `json:"lastAccessed"`
+	Pinned       bool  `json:"pinned"`
+}
+
+type firefoxSessionClosedTab struct {
+	State    firefoxSessionTab `json:"state"`
+	ClosedAt int64             `json:"closedAt"`
+}
+
+type firefoxSessionEntry struct {
+	URL   string `json:"url"`
+	Title string `json:"title"`
+}
+
+// current returns the entry the tab is showing
+func (t firefoxSessionTab) current() (firefoxSessionEntry, bool) {
+	if len(t.Entries) == 0 {
+		return firefoxSessionEntry{}, false
+	}
+
+	if t.Index < 1 || t.Index > len(t.Entries) {
+		return t.Entries[len(t.Entries)-1], true
+	}
+	return t.Entries[t.Index-1], true
+}
+
+// GetTabs returns the tabs of the profile's session, followed by the tabs
+// closed in open windows and the tabs of closed windows
+func (f *Firefox) GetTabs(ctx context.Context, profile string) ([]Tab, error) {
+	firefoxProfile, err := f.findProfile(profile)
+	if err != nil {
+		firefoxLogger.Debug("Failed to find Firefox profile", "profile", profile, "error", err)
+		return nil, err
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	sessionPath, err := firefoxProfile.sessionPath()
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := readFirefoxSession(sessionPath)
+	if err != nil {
+		firefoxLogger.Debug("Failed to read Firefox session", "path", sessionPath, "error", err)
+		return nil, &BrowserError{Browser: f.Name(), Profile: firefoxProfile.Name, Path: sessionPath, Err: err}
+	}
+
+	tabs := buildFirefoxTabs(session)
+	for i := range tabs {
+		tabs[i].Profile = firefoxProfile.Name
+	}
+
+	return tabs, nil
+}
+
+// sessionPath returns the path to the profile's session store
+func (p firefoxProfile) sessionPath() (string, error) {
+	for _, name := range firefoxSessionFiles {
+		path := filepath.Join(p.Path, name)
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+
+	return "", fmt.Errorf("Firefox session not found in %s: %w", p.Path, ErrNotInstalled)
+}
+
+// readFirefoxSession decompresses and decodes a session store file
+func readFirefoxSession(path string) (firefoxSession, error) {
+	var session firefoxSession
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return session, err
+	}
+
+	decoded, err := decodeMozLz4(data)
+	if err != nil {
+		return session, err
+	}
+
+	if err := json.Unmarshal(decoded, &session); err != nil {
+		return session, fmt.Errorf("failed to unmarshal session: %w", err)
+	}
+
+	return session, nil
+}
+
+// buildFirefoxTabs converts the session's windows into tabs. Open tabs come
+// first ordered by window, then closed tabs most recently closed first.
+func buildFirefoxTabs(session firefoxSession) []Tab {
+	var tabs []Tab
+
+	for w, window := range session.Windows {
+		for i, tab := range window.Tabs {
+			entry, ok := tab.current()
+			if !ok {
+				continue
+			}
+
+			tabs = append(tabs, Tab{
+				Window:     w + 1,
+				Index:      i,
+				Title:      entry.Title,
+				URL:        entry.URL,
+				Pinned:     tab.Pinned,
+				Active:     i == window.Selected-1,
+				LastActive: firefoxMillis(tab.LastAccessed),
+			})
+		}
+	}
+
+	var closed []Tab
+	addClosed := func(tab firefoxSessionTab, closedAt int64) {
+		entry, ok := tab.current()
+		if !ok {
+			return
+		}
+
+		closed = append(closed, Tab{
+			Title:      entry.Title,
+			URL:        entry.URL,
+			Pinned:     tab.Pinned,
+			LastActive: firefoxMillis(closedAt),
+			Closed:     true,
+		})
+	}
+
+	for _, window := range session.Windows {
+		for _, closedTab := range window.ClosedTabs {
+			addClosed(closedTab.State, closedTab.ClosedAt)
+		}
+	}
+	for _, window := range session.ClosedWindows {
+		for _, tab := range window.Tabs {
+			addClosed(tab, window.ClosedAt)
+		}
+	}
+
+	sort.SliceStable(closed, func(i, j int) bool {
+		return closed[i].LastActive.After(closed[j].LastActive)
+	})
+	for i := range closed {
+		closed[i].Index = i
+	}
+
+	return append(tabs, closed...)
+}
+
+// firefoxMillis converts milliseconds since the unix epoch to a time.Time, 0
+// meaning never
+func firefoxMillis(millis int64) time.Time {
+	if millis == 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(millis)
+}
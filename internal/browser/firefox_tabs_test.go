@@ -0,0 +1,150 @@
+package browser
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var firefoxSessionFixture = filepath.Join("testdata", "firefox", "recovery.jsonlz4")
+
+func TestDecodeMozLz4(t *testing.T) {
+	data, err := os.ReadFile(firefoxSessionFixture)
+	require.NoError(t, err)
+
+	decoded, err := decodeMozLz4(data)
+	require.NoError(t, err)
+	assert.True(t, json.Valid(decoded), "Should decode to the session JSON")
+
+	testCases := []struct {
+		name          string
+		data          []byte
+		expectedError string
+	}{
+		{
+			name:          "Not mozLz4",
+			data:          []byte(`{"windows": []}`),
+			expectedError: "not a mozLz4 file",
+		},
+		{
+			name:          "Plain LZ4 frame",
+			data:          []byte{0x04, 0x22, 0x4d, 0x18, 0x64, 0x40, 0xa7, 0x00, 0x00, 0x00, 0x00, 0x00},
+			expectedError: "not a mozLz4 file",
+		},
+		{
+			name:          "Truncated block",
+			data:          data[:len(data)/2],
+			expectedError: "failed to decompress mozLz4 block",
+		},
+		{
+			name:          "Corrupt size",
+			data:          append([]byte(mozLz4Magic+"\xff\xff\xff\xff"), data[len(mozLz4Magic)+4:]...),
+			expectedError: "mozLz4 header claims 4294967295 bytes",
+		},
+		{
+			name:          "Size beyond the block",
+			data:          []byte(mozLz4Magic + "\x00\x00\x01\x00" + "\x10a"),
+			expectedError: "mozLz4 header claims 65536 bytes for a 2 byte block",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := decodeMozLz4(tc.data)
+			assert.ErrorContains(t, err, tc.expectedError)
+		})
+	}
+}
+
+func TestGetFirefoxTabs(t *testing.T) {
+	firefox := newTestFirefox(t)
+	writeFirefoxSessionFixture(t, firefox, filepath.Join("sessionstore-backups", "recovery.jsonlz4"))
+
+	tabs, err := firefox.GetTabs(context.Background(), "Default")
+	require.NoError(t, err)
+
+	require.Equal(t, 5, len(tabs), "Should skip tabs without entries")
+
+	assert.Equal(t, Tab{
+		Window:     1,
+		Index:      0,
+		Title:      "GitHub",
+		URL:        "https://github.com/",
+		Pinned:     true,
+		LastActive: time.Date(2024, 3, 1, 11, 0, 0, 0, time.UTC).Local(),
+		Profile:    "default-release",
+	}, tabs[0])
+
+	assert.Equal(t, "Example", tabs[1].Title, "Should show the current entry, not the latest")
+	assert.True(t, tabs[1].Active)
+
+	assert.Equal(t, "Documentation — Go", tabs[2].Title)
+	assert.Equal(t, 2, tabs[2].Window)
+	assert.True(t, tabs[2].Active)
+
+	assert.Equal(t, "Story", tabs[3].Title, "Should list the most recently closed tab first")
+	assert.True(t, tabs[3].Closed)
+	assert.Equal(t, time.Date(2024, 3, 1, 14, 0, 0, 0, time.UTC), tabs[3].LastActive.UTC())
+
+	assert.Equal(t, "Closed Example", tabs[4].Title)
+	assert.True(t, tabs[4].Closed)
+	assert.Equal(t, 1, tabs[4].Index)
+}
+
+func TestGetFirefoxTabsAfterShutdown(t *testing.T) {
+	firefox := newTestFirefox(t)
+	writeFirefoxSessionFixture(t, firefox, "sessionstore.jsonlz4")
+
+	tabs, err := firefox.GetTabs(context.Background(), "Default")
+	require.NoError(t, err)
+
+	assert.Equal(t, 5, len(tabs))
+}
+
+func TestGetFirefoxTabsNoSession(t *testing.T) {
+	firefox := newTestFirefox(t)
+
+	_, err := firefox.GetTabs(context.Background(), "Default")
+	assert.ErrorIs(t, err, ErrNotInstalled)
+}
+
+func TestGetFirefoxTabsCorruptSession(t *testing.T) {
+	firefox := newTestFirefox(t)
+	profile, err := firefox.findProfile("Default")
+	require.NoError(t, err)
+
+	sessionPath := filepath.Join(profile.Path, "sessionstore.jsonlz4")
+	err = os.WriteFile(sessionPath, []byte("mozLz40\x00garbage"), 0644)
+	require.NoError(t, err)
+
+	_, err = firefox.GetTabs(context.Background(), "Default")
+
+	var browserErr *BrowserError
+	require.ErrorAs(t, err, &browserErr)
+	assert.Equal(t, sessionPath, browserErr.Path)
+	assert.NotErrorIs(t, err, ErrNotInstalled)
+}
+
+// writeFirefoxSessionFixture copies the session fixture to name within the
+// default profile of firefox
+func writeFirefoxSessionFixture(t *testing.T, firefox *Firefox, name string) {
+	t.Helper()
+
+	profile, err := firefox.findProfile("Default")
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(firefoxSessionFixture)
+	require.NoError(t, err)
+
+	path := filepath.Join(profile.Path, name)
+	err = os.MkdirAll(filepath.Dir(path), 0755)
+	require.NoError(t, err)
+	err = os.WriteFile(path, data, 0644)
+	require.NoError(t, err)
+}
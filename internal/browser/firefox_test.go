@@ -0,0 +1,293 @@
+package browser
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetFirefoxProfilesDirForPlatform(t *testing.T) {
+	testCases := []struct {
+		name        string
+		dirs        platformDirs
+		expected    string
+		expectError bool
+	}{
+		{
+			name:     "Windows path",
+			dirs:     platformDirs{goos: "windows", homeDir: `C:\Users\testuser`, appData: `C:\Users\testuser\AppData\Roaming`},
+			expected: filepath.Join(`C:\Users\testuser\AppData\Roaming`, "Mozilla", "Firefox"),
+		},
+		{
+			name:     "macOS path",
+			dirs:     platformDirs{goos: "darwin", homeDir: "/Users/testuser"},
+			expected: filepath.Join("/Users/testuser", "Library", "Application Support", "Firefox"),
+		},
+		{
+			name:     "Linux path",
+			dirs:     platformDirs{goos: "linux", homeDir: "/home/testuser"},
+			expected: filepath.Join("/home/testuser", ".mozilla", "firefox"),
+		},
+		{
+			name:        "Unsupported OS",
+			dirs:        platformDirs{goos: "solaris", homeDir: "/home/testuser"},
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			path, err := firefoxProfilesDirForPlatform(tc.dirs)
+
+			if tc.expectError {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), "unsupported operating system")
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expected, path)
+			}
+		})
+	}
+}
+
+func TestReadFirefoxProfiles(t *testing.T) {
+	profilesDir := t.TempDir()
+	writeProfilesIni(t, profilesDir)
+
+	profiles, err := readFirefoxProfiles(profilesDir)
+	require.NoError(t, err)
+
+	assert.Equal(t, []firefoxProfile{
+		{
+			Name:    "default",
+			Path:    filepath.Join(profilesDir, "Profiles", "abcd1234.default"),
+			Default: false,
+		},
+		{
+			Name:    "default-release",
+			Path:    filepath.Join(profilesDir, "Profiles", "efgh5678.default-release"),
+			Default: true,
+		},
+		{
+			Name:    "Work",
+			Path:    "/elsewhere/work",
+			Default: false,
+		},
+	}, profiles)
+
+	testCases := []struct {
+		name         string
+		profile      string
+		expectedPath string
+		expectError  bool
+	}{
+		{
+			name:         "Default resolves to the install default",
+			profile:      "Default",
+			expectedPath: filepath.Join(profilesDir, "Profiles", "efgh5678.default-release"),
+		},
+		{
+			name:         "Match by name",
+			profile:      "work",
+			expectedPath: "/elsewhere/work",
+		},
+		{
+			name:         "Match by directory name",
+			profile:      "abcd1234.default",
+			expectedPath: filepath.Join(profilesDir, "Profiles", "abcd1234.default"),
+		},
+		{
+			name:        "Unknown profile",
+			profile:     "missing",
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			profile, err := findFirefoxProfile(profiles, tc.profile)
+
+			if tc.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedPath, profile.Path)
+			}
+		})
+	}
+}
+
+func TestFirefoxProfiles(t *testing.T) {
+	firefox := newTestFirefox(t)
+
+	profiles, err := firefox.Profiles()
+	require.NoError(t, err)
+
+	assert.Equal(t, []Profile{
+		{Name: "default", Dir: "abcd1234.default"},
+		{Name: "default-release", Dir: "efgh5678.default-release"},
+		{Name: "Work", Dir: "work"},
+	}, profiles)
+}
+
+func TestGetFirefoxBookmarksWithFixture(t *testing.T) {
+	firefox := newTestFirefox(t)
+
+	bookmarks, err := firefox.GetBookmarks(context.Background(), "Default")
+	require.NoError(t, err)
+
+	require.Equal(t, 3, len(bookmarks), "Should skip tags and place: queries")
+
+	assert.Equal(t, "Example Site", bookmarks[0].Title)
+	assert.Equal(t, "https://example.com/", bookmarks[0].URL)
+	assert.Equal(t, "Bookmarks Toolbar", bookmarks[0].FolderPath)
+	assert.Equal(t, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), bookmarks[0].DateAdded.UTC())
+	assert.Equal(t, time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC), bookmarks[0].DateLastUsed.UTC())
+	assert.Equal(t, "10", bookmarks[0].ID)
+	assert.Equal(t, "bookmark0001", bookmarks[0].GUID)
+
+	assert.Equal(t, "Other Site", bookmarks[1].Title)
+	assert.Equal(t, "https://othersite.com/", bookmarks[1].URL)
+	assert.Equal(t, "Other Bookmarks", bookmarks[1].FolderPath)
+
+	assert.Equal(t, "GitHub", bookmarks[2].Title)
+	assert.Equal(t, "https://github.com/", bookmarks[2].URL)
+	assert.Equal(t, filepath.Join("Bookmarks Toolbar", "Work", "Code"), bookmarks[2].FolderPath)
+	assert.True(t, bookmarks[2].DateLastUsed.IsZero(), "Should leave pages that were never visited unused")
+	assert.Equal(t, time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC), bookmarks[2].FolderDateModified.UTC())
+
+	for _, bookmark := range bookmarks {
+		assert.Equal(t, "default-release", bookmark.Profile)
+	}
+}
+
+func TestGetFirefoxBookmarksMissingFile(t *testing.T) {
+	firefox := newTestFirefox(t)
+
+	_, err := firefox.GetBookmarks(context.Background(), "default")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Firefox bookmarks not found")
+}
+
+// newTestFirefox creates a Firefox instance reading from a temporary profiles
+// directory, where only the default-release profile has a places.sqlite
+func newTestFirefox(t *testing.T) *Firefox {
+	t.Helper()
+
+	profilesDir := t.TempDir()
+	writeProfilesIni(t, profilesDir)
+
+	profileDir := filepath.Join(profilesDir, "Profiles", "efgh5678.default-release")
+	err := os.MkdirAll(profileDir, 0755)
+	require.NoError(t, err)
+	createPlacesFixture(t, filepath.Join(profileDir, "places.sqlite"))
+
+	return &Firefox{
+		getProfilesDir: func() (string, error) {
+			return profilesDir, nil
+		},
+	}
+}
+
+// writeProfilesIni writes a profiles.ini into profilesDir that mixes legacy
+// Default=1 markers with an install specific default
+func writeProfilesIni(t *testing.T, profilesDir string) {
+	t.Helper()
+
+	ini := `[Install4F96D1932A9F858E]
+Default=Profiles/efgh5678.default-release
+Locked=1
+
+[Profile0]
+Name=default
+IsRelative=1
+Path=Profiles/abcd1234.default
+Default=1
+
+[Profile1]
+Name=default-release
+IsRelative=1
+Path=Profiles/efgh5678.default-release
+
+[Profile2]
+Name=Work
+IsRelative=0
+Path=/elsewhere/work
+
+[General]
+StartWithLastProfile=1
+Version=2
+`
+	err := os.WriteFile(filepath.Join(profilesDir, "profiles.ini"), []byte(ini), 0644)
+	require.NoError(t, err)
+}
+
+// createPlacesFixture generates a minimal places.sqlite with the parts of the
+// moz_bookmarks/moz_places schema that are read
+func createPlacesFixture(t *testing.T, path string) {
+	t.Helper()
+
+	db, err := sql.Open("sqlite", path)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, db.Close())
+	}()
+
+	dateAdded := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC).UnixMicro()
+
+	statements := []string{
+		`CREATE TABLE moz_places (id INTEGER PRIMARY KEY, url LONGVARCHAR, title LONGVARCHAR, visit_count INTEGER DEFAULT 0, hidden INTEGER DEFAULT 0 NOT NULL, last_visit_date INTEGER, frecency INTEGER DEFAULT -1)`,
+		`CREATE TABLE moz_historyvisits (id INTEGER PRIMARY KEY, from_visit INTEGER, place_id INTEGER, visit_date INTEGER, visit_type INTEGER, session INTEGER)`,
+		`CREATE TABLE moz_bookmarks (id INTEGER PRIMARY KEY, type INTEGER, fk INTEGER DEFAULT NULL, parent INTEGER, position INTEGER, title LONGVARCHAR, dateAdded INTEGER, lastModified INTEGER, guid TEXT)`,
+
+		`INSERT INTO moz_places (id, url, title, last_visit_date) VALUES
+			(1, 'https://example.com/', 'Example', 1717200000000000),
+			(2, 'https://github.com/', 'GitHub', NULL),
+			(3, 'https://othersite.com/', 'Other', NULL),
+			(4, 'place:sort=8&maxResults=10', NULL, NULL)`,
+		`INSERT INTO moz_places (id, url, title, hidden, last_visit_date) VALUES
+			(5, 'https://example.com/frame', 'Embedded Frame', 1, 1717200000000000)`,
+
+		// example.com was visited on 2024-01-01, 2024-05-01 and 2024-06-01,
+		// github.com on 2024-03-01
+		`INSERT INTO moz_historyvisits (id, from_visit, place_id, visit_date, visit_type) VALUES
+			(1, 0, 1, 1704067200000000, 1),
+			(2, 0, 1, 1714521600000000, 1),
+			(3, 0, 1, 1717200000000000, 2),
+			(4, 0, 2, 1709251200000000, 1),
+			(5, 3, 5, 1717200000000000, 8)`,
+
+		`INSERT INTO moz_bookmarks (id, type, fk, parent, position, title, dateAdded, guid) VALUES
+			(1, 2, NULL, 0, 0, '', 0, 'root________'),
+			(2, 2, NULL, 1, 0, 'menu', 0, 'menu________'),
+			(3, 2, NULL, 1, 1, 'toolbar', 0, 'toolbar_____'),
+			(4, 2, NULL, 1, 2, 'tags', 0, 'tags________'),
+			(5, 2, NULL, 1, 3, 'unfiled', 0, 'unfiled_____'),
+			(6, 2, NULL, 1, 4, 'mobile', 0, 'mobile______'),
+			(10, 1, 1, 3, 0, 'Example Site', ?1, 'bookmark0001'),
+			(11, 2, NULL, 3, 1, 'Work', ?1, 'folder000001'),
+			(12, 2, NULL, 11, 0, 'Code', ?1, 'folder000002'),
+			(13, 1, 2, 12, 0, 'GitHub', ?1, 'bookmark0002'),
+			(14, 1, 3, 5, 0, 'Other Site', ?1, 'bookmark0003'),
+			(15, 1, 4, 2, 0, 'Most Visited', ?1, 'bookmark0004'),
+			(16, 2, NULL, 4, 0, 'golang', ?1, 'tag000000001'),
+			(17, 1, 2, 16, 0, NULL, ?1, 'tagentry0001')`,
+		`UPDATE moz_bookmarks SET lastModified = 1717200000000000 WHERE id = 12`,
+	}
+
+	for _, statement := range statements {
+		var args []any
+		if strings.Contains(statement, "?1") {
+			args = append(args, dateAdded)
+		}
+		_, err := db.Exec(statement, args...)
+		require.NoError(t, err)
+	}
+}
@@ -0,0 +1,56 @@
+package browser
+
+import (
+	"context"
+	"time"
+)
+
+// HistoryEntry is a page from a browser's history, with its visits summed up
+// over the requested HistoryWindow
+type HistoryEntry struct {
+	Title string
+	URL   string
+	// LastVisit is the most recent visit within the window
+	LastVisit time.Time
+	// VisitCount is the number of visits within the window
+	VisitCount int
+	Profile    string
+}
+
+// HistoryWindow limits history to visits at or after Since and before Until.
+// A zero time leaves that side open.
+type HistoryWindow struct {
+	Since time.Time
+	Until time.Time
+}
+
+// HistorySource is implemented by browsers that can read their history
+type HistorySource interface {
+	Name() string
+	GetHistory(ctx context.Context, profile string, window HistoryWindow) ([]HistoryEntry, error)
+}
+
+// GetAllHistory returns the history of every registered browser that is a
+// HistorySource. History is loaded the same way as GetAllBookmarks loads
+// bookmarks, including how failures are reported.
+func GetAllHistory(ctx context.Context, profile string, window HistoryWindow) (map[string][]HistoryEntry, error) {
+	var sources []Browser
+	for _, browser := range RegisteredBrowsers {
+		if _, ok := browser.(HistorySource); ok {
+			sources = append(sources, browser)
+		}
+	}
+
+	return loadAll(ctx, sources, profile, func(ctx context.Context, browser Browser, profile string) ([]HistoryEntry, error) {
+		return browser.(HistorySource).GetHistory(ctx, profile, window)
+	})
+}
+
+// historyBound converts one side of a HistoryWindow to a browser timestamp
+// using toTimestamp, using open when the time is zero
+func historyBound(t time.Time, open int64, toTimestamp func(time.Time) int64) int64 {
+	if t.IsZero() {
+		return open
+	}
+	return toTimestamp(t)
+}
@@ -0,0 +1,164 @@
+package browser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// LoadWorkers limits how many browser profiles are loaded at the same time
+var LoadWorkers = 4
+
+// LoadTimeout is how long a single browser profile may take to load before
+// it is reported as failed
+var LoadTimeout = 10 * time.Second
+
+// loadJob is a single browser profile to load data from
+type loadJob struct {
+	browser Browser
+	// profile is passed to the backend
+	profile string
+	// profileName is used when reporting errors
+	profileName string
+}
+
+// loadResult is the outcome of a loadJob
+type loadResult[T any] struct {
+	items []T
+	err   error
+}
+
+// loadFunc loads the items of a single browser profile
+type loadFunc[T any] func(ctx context.Context, browser Browser, profile string) ([]T, error)
+
+// loadAll loads the items of every browser profile in browsers. Browsers
+// that are not installed are skipped, any other failure is returned as
+// BrowserErrors alongside the items that could be loaded. Both are ordered by
+// browsers and then by profile.
+func loadAll[T any](ctx context.Context, browsers []Browser, profile string, load loadFunc[T]) (map[string][]T, error) {
+	result := make(map[string][]T)
+	var errs BrowserErrors
+
+	jobs, planErrs := planLoadJobs(browsers, profile)
+	errs = append(errs, planErrs...)
+
+	for i, loaded := range runLoadJobs(ctx, jobs, load) {
+		job := jobs[i]
+		if loaded.err != nil {
+			errs = append(errs, toBrowserErrors(job.browser.Name(), job.profileName, loaded.err)...)
+			continue
+		}
+		result[job.browser.Name()] = append(result[job.browser.Name()], loaded.items...)
+	}
+
+	var broken BrowserErrors
+	for _, browserErr := range errs {
+		if errors.Is(browserErr, ErrNotInstalled) {
+			browserLogger.Debug("Skipping browser that is not installed", "browser", browserErr.Browser, "error", browserErr)
+			continue
+		}
+		broken = append(broken, browserErr)
+	}
+
+	if len(broken) > 0 {
+		return result, broken
+	}
+
+	return result, nil
+}
+
+// planLoadJobs returns a job for each of browsers, expanding AllProfiles into
+// one job per profile. Browsers whose profiles can't be listed are returned as
+// errors instead.
+func planLoadJobs(browsers []Browser, profile string) ([]loadJob, BrowserErrors) {
+	var jobs []loadJob
+	var errs BrowserErrors
+
+	for _, browser := range browsers {
+		if profile != AllProfiles {
+			jobs = append(jobs, loadJob{browser: browser, profile: profile, profileName: profile})
+			continue
+		}
+
+		lister, ok := browser.(ProfileLister)
+		if !ok {
+			jobs = append(jobs, loadJob{browser: browser, profile: "Default", profileName: "Default"})
+			continue
+		}
+
+		profiles, err := lister.Profiles()
+		if err != nil {
+			errs = append(errs, toBrowserErrors(browser.Name(), profile, err)...)
+			continue
+		}
+
+		for _, p := range profiles {
+			jobs = append(jobs, loadJob{browser: browser, profile: p.Dir, profileName: p.Name})
+		}
+	}
+
+	return jobs, errs
+}
+
+// runLoadJobs runs jobs on a pool of LoadWorkers workers, returning the
+// results in the same order as jobs. Jobs that have not started when ctx is
+// done fail with ctx's error.
+func runLoadJobs[T any](ctx context.Context, jobs []loadJob, load loadFunc[T]) []loadResult[T] {
+	results := make([]loadResult[T], len(jobs))
+
+	workers := max(min(LoadWorkers, len(jobs)), 1)
+	indexes := make(chan int)
+	done := make(chan struct{})
+
+	for range workers {
+		go func() {
+			defer func() {
+				done <- struct{}{}
+			}()
+			for i := range indexes {
+				results[i] = runLoadJob(ctx, jobs[i], load)
+			}
+		}()
+	}
+
+	for i := range jobs {
+		select {
+		case indexes <- i:
+		case <-ctx.Done():
+			results[i] = loadResult[T]{err: ctx.Err()}
+		}
+	}
+	close(indexes)
+
+	for range workers {
+		<-done
+	}
+
+	return results
+}
+
+// runLoadJob loads a single job, giving up once LoadTimeout has passed. A
+// backend that ignores its context keeps running in the background until it
+// returns, but no longer holds up the other browsers.
+func runLoadJob[T any](ctx context.Context, job loadJob, load loadFunc[T]) loadResult[T] {
+	ctx, cancel := context.WithTimeout(ctx, LoadTimeout)
+	defer cancel()
+
+	loaded := make(chan loadResult[T], 1)
+	go func() {
+		items, err := load(ctx, job.browser, job.profile)
+		loaded <- loadResult[T]{items: items, err: err}
+	}()
+
+	select {
+	case result := <-loaded:
+		return result
+	case <-ctx.Done():
+		// the parent being cancelled is not a timeout
+		if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return loadResult[T]{err: ctx.Err()}
+		}
+		return loadResult[T]{err: fmt.Errorf("gave up after %s: %w", LoadTimeout, ctx.Err())}
+	}
+}
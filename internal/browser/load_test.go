@@ -0,0 +1,237 @@
+package browser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// slowBrowser is a Browser that takes delay to load, tracking how many loads
+// are running at once
+type slowBrowser struct {
+	name    string
+	delay   time.Duration
+	err     error
+	running *atomic.Int32
+	maxSeen *atomic.Int32
+}
+
+func (s *slowBrowser) Name() string {
+	return s.name
+}
+
+func (s *slowBrowser) GetBookmarks(ctx context.Context, profile string) ([]Bookmark, error) {
+	current := s.running.Add(1)
+	defer s.running.Add(-1)
+
+	for {
+		seen := s.maxSeen.Load()
+		if current <= seen || s.maxSeen.CompareAndSwap(seen, current) {
+			break
+		}
+	}
+
+	select {
+	case <-time.After(s.delay):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	if s.err != nil {
+		return nil, s.err
+	}
+	return []Bookmark{{Title: s.name, URL: "https://" + s.name + ".example.com"}}, nil
+}
+
+// withLoadSettings overrides LoadWorkers and LoadTimeout for the duration of the test
+func withLoadSettings(t *testing.T, workers int, timeout time.Duration) {
+	t.Helper()
+
+	originalWorkers, originalTimeout := LoadWorkers, LoadTimeout
+	LoadWorkers, LoadTimeout = workers, timeout
+	t.Cleanup(func() {
+		LoadWorkers, LoadTimeout = originalWorkers, originalTimeout
+	})
+}
+
+func TestChromiumGetBookmarksCancelled(t *testing.T) {
+	dirs := platformDirs{goos: "linux", homeDir: t.TempDir()}
+	bookmarksFile := writeBookmarksFixture(t, chromeVendor, dirs, "Default")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := newTestChromium(chromeVendor, bookmarksFile).GetBookmarks(ctx, "Default")
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestGetAllBookmarksTimesOutSlowPathProvider(t *testing.T) {
+	withLoadSettings(t, 4, 50*time.Millisecond)
+
+	dirs := platformDirs{goos: "linux", homeDir: t.TempDir()}
+	bookmarksFile := writeBookmarksFixture(t, chromeVendor, dirs, "Default")
+
+	// a path provider stuck on a slow disk, released once the test is done
+	release := make(chan struct{})
+	t.Cleanup(func() {
+		close(release)
+	})
+
+	stuck := newTestChromium(braveVendor, bookmarksFile)
+	stuck.getBookmarksPath = func(profile string) (string, error) {
+		<-release
+		return bookmarksFile, nil
+	}
+
+	withRegisteredBrowsers(t, stuck, newTestChromium(chromeVendor, bookmarksFile))
+
+	start := time.Now()
+	bookmarks, err := GetAllBookmarks(context.Background(), "Default")
+	assert.Less(t, time.Since(start), time.Second, "Should not wait for the stuck browser")
+
+	assertSampleBookmarks(t, bookmarks["Chrome"])
+	assert.NotContains(t, bookmarks, "Brave")
+
+	var browserErrs BrowserErrors
+	require.ErrorAs(t, err, &browserErrs)
+	require.Equal(t, 1, len(browserErrs))
+	assert.Equal(t, "Brave", browserErrs[0].Browser)
+	assert.ErrorIs(t, browserErrs[0], context.DeadlineExceeded)
+}
+
+func TestGetAllBookmarksCancelledContext(t *testing.T) {
+	running, maxSeen := &atomic.Int32{}, &atomic.Int32{}
+	withRegisteredBrowsers(t,
+		&slowBrowser{name: "one", delay: time.Second, running: running, maxSeen: maxSeen},
+		&slowBrowser{name: "two", delay: time.Second, running: running, maxSeen: maxSeen},
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	bookmarks, err := GetAllBookmarks(ctx, "Default")
+	assert.Empty(t, bookmarks)
+
+	var browserErrs BrowserErrors
+	require.ErrorAs(t, err, &browserErrs)
+	require.Equal(t, 2, len(browserErrs))
+	for _, browserErr := range browserErrs {
+		assert.ErrorIs(t, browserErr, context.Canceled)
+	}
+}
+
+func TestRunLoadJobReportsWhyItGaveUp(t *testing.T) {
+	withLoadSettings(t, 4, 50*time.Millisecond)
+
+	// a backend that ignores its context, released once the test is done
+	release := make(chan struct{})
+	t.Cleanup(func() {
+		close(release)
+	})
+	stuck := func(ctx context.Context, browser Browser, profile string) ([]Bookmark, error) {
+		<-release
+		return nil, nil
+	}
+
+	result := runLoadJob(context.Background(), loadJob{profile: "Default"}, stuck)
+	assert.ErrorIs(t, result.err, context.DeadlineExceeded)
+	assert.ErrorContains(t, result.err, "gave up after 50ms")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	result = runLoadJob(ctx, loadJob{profile: "Default"}, stuck)
+	assert.Equal(t, context.Canceled, result.err, "A cancelled load is not a timeout")
+}
+
+func TestGetAllBookmarksBoundedWorkers(t *testing.T) {
+	withLoadSettings(t, 2, time.Second)
+
+	running, maxSeen := &atomic.Int32{}, &atomic.Int32{}
+	var browsers []Browser
+	for i := range 6 {
+		browsers = append(browsers, &slowBrowser{
+			name:    fmt.Sprintf("browser%d", i),
+			delay:   20 * time.Millisecond,
+			running: running,
+			maxSeen: maxSeen,
+		})
+	}
+	withRegisteredBrowsers(t, browsers...)
+
+	bookmarks, err := GetAllBookmarks(context.Background(), "Default")
+	require.NoError(t, err)
+
+	assert.Equal(t, 6, len(bookmarks))
+	assert.Equal(t, int32(2), maxSeen.Load(), "Should never run more than LoadWorkers at once")
+}
+
+func TestGetAllBookmarksDeterministicOrder(t *testing.T) {
+	withLoadSettings(t, 4, time.Second)
+
+	// later browsers finish first
+	running, maxSeen := &atomic.Int32{}, &atomic.Int32{}
+	withRegisteredBrowsers(t,
+		&slowBrowser{name: "first", delay: 60 * time.Millisecond, err: errors.New("first failed"), running: running, maxSeen: maxSeen},
+		&slowBrowser{name: "second", delay: 40 * time.Millisecond, err: errors.New("second failed"), running: running, maxSeen: maxSeen},
+		&slowBrowser{name: "third", delay: 20 * time.Millisecond, err: errors.New("third failed"), running: running, maxSeen: maxSeen},
+		&slowBrowser{name: "fourth", delay: 0, err: errors.New("fourth failed"), running: running, maxSeen: maxSeen},
+	)
+
+	_, err := GetAllBookmarks(context.Background(), "Default")
+
+	var browserErrs BrowserErrors
+	require.ErrorAs(t, err, &browserErrs)
+
+	var names []string
+	for _, browserErr := range browserErrs {
+		names = append(names, browserErr.Browser)
+	}
+	assert.Equal(t, []string{"first", "second", "third", "fourth"}, names)
+}
+
+func TestGetAllBookmarksAllProfilesInProfileOrder(t *testing.T) {
+	withLoadSettings(t, 4, time.Second)
+
+	dirs := platformDirs{goos: "linux", homeDir: t.TempDir()}
+	writeLocalStateFixture(t, chromiumBrowserVendor, dirs)
+	writeBookmarksFixture(t, chromiumBrowserVendor, dirs, "Default")
+	writeBookmarksFixture(t, chromiumBrowserVendor, dirs, "Profile 1")
+
+	// make the first profile in profiles_order the slowest to read
+	workPath, err := chromiumBrowserVendor.bookmarksPathForPlatform(dirs, "Profile 3")
+	require.NoError(t, err)
+	err = os.MkdirAll(filepath.Dir(workPath), 0755)
+	require.NoError(t, err)
+	err = os.WriteFile(workPath, []byte(createSampleBookmarksJSON()), 0644)
+	require.NoError(t, err)
+
+	chromium := newFixtureChromium(chromiumBrowserVendor, dirs)
+	readPath := chromium.getBookmarksPath
+	chromium.getBookmarksPath = func(profile string) (string, error) {
+		if profile == "Profile 3" {
+			time.Sleep(30 * time.Millisecond)
+		}
+		return readPath(profile)
+	}
+	withRegisteredBrowsers(t, chromium)
+
+	bookmarks, err := GetAllBookmarks(context.Background(), AllProfiles)
+	require.NoError(t, err)
+
+	var profiles []string
+	for _, bookmark := range bookmarks["Chromium"] {
+		if len(profiles) == 0 || profiles[len(profiles)-1] != bookmark.Profile {
+			profiles = append(profiles, bookmark.Profile)
+		}
+	}
+	assert.Equal(t, []string{"Work", "Personal", "Side Project"}, profiles)
+}
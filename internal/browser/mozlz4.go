@@ -0,0 +1,44 @@
+package browser
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/pierrec/lz4/v4"
+)
+
+// mozLz4Magic starts every mozLz4 file. Firefox compresses its session store
+// (and a few other JSON files) as a single LZ4 block behind this header and
+// the uncompressed size.
+const mozLz4Magic = "mozLz40\x00"
+
+// LZ4 compresses at most 255 to 1, so a header claiming a larger size than
+// that is corrupt. Session stores are a few MiB, mozLz4MaxSize caps the
+// allocation for ones that claim a plausible but huge size.
+const (
+	mozLz4MaxRatio = 255
+	mozLz4MaxSize  = 256 << 20
+)
+
+// decodeMozLz4 decompresses the contents of a mozLz4 file
+func decodeMozLz4(data []byte) ([]byte, error) {
+	headerSize := len(mozLz4Magic) + 4
+	if len(data) < headerSize || string(data[:len(mozLz4Magic)]) != mozLz4Magic {
+		return nil, errors.New("not a mozLz4 file")
+	}
+
+	size := binary.LittleEndian.Uint32(data[len(mozLz4Magic):headerSize])
+	block := data[headerSize:]
+	if size > mozLz4MaxSize || uint64(size) > uint64(len(block))*mozLz4MaxRatio {
+		return nil, fmt.Errorf("mozLz4 header claims %d bytes for a %d byte block", size, len(block))
+	}
+	decoded := make([]byte, size)
+
+	n, err := lz4.UncompressBlock(block, decoded)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress mozLz4 block: %w", err)
+	}
+
+	return decoded[:n], nil
+}
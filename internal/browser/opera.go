@@ -0,0 +1,28 @@
+package browser
+
+import (
+	"github.com/malleatus/tamjaweb/internal/logger"
+)
+
+var operaLogger = logger.New("browser:opera")
+
+// Opera keeps its default profile directly in the user data directory, and
+// on Windows it lives under the roaming %APPDATA% directory
+var operaVendor = chromiumVendor{
+	name:           "Opera",
+	windows:        []string{"Opera Software", "Opera Stable"},
+	darwin:         []string{"com.operasoftware.Opera"},
+	linux:          []string{"opera"},
+	windowsRoaming: true,
+	profileInRoot:  true,
+}
+
+func init() {
+	operaLogger.Info("Registering Opera browser")
+	RegisterBrowser(NewOpera())
+}
+
+// NewOpera creates a new Opera browser instance with the default path provider
+func NewOpera() *Chromium {
+	return newChromium(operaVendor)
+}
@@ -0,0 +1,68 @@
+package browser
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetOperaBookmarksPathForPlatform(t *testing.T) {
+	testCases := []struct {
+		name     string
+		dirs     platformDirs
+		profile  string
+		expected string
+	}{
+		{
+			name: "Windows path uses roaming app data",
+			dirs: platformDirs{
+				goos:         "windows",
+				homeDir:      `C:\Users\testuser`,
+				localAppData: `C:\Users\testuser\AppData\Local`,
+				appData:      `C:\Users\testuser\AppData\Roaming`,
+			},
+			profile:  "Default",
+			expected: filepath.Join(`C:\Users\testuser\AppData\Roaming`, "Opera Software", "Opera Stable", "Bookmarks"),
+		},
+		{
+			name:     "macOS path",
+			dirs:     platformDirs{goos: "darwin", homeDir: "/Users/testuser"},
+			profile:  "Default",
+			expected: filepath.Join("/Users/testuser", "Library", "Application Support", "com.operasoftware.Opera", "Bookmarks"),
+		},
+		{
+			name:     "Linux path",
+			dirs:     platformDirs{goos: "linux", homeDir: "/home/testuser"},
+			profile:  "Default",
+			expected: filepath.Join("/home/testuser", ".config", "opera", "Bookmarks"),
+		},
+		{
+			name:     "Non default profile",
+			dirs:     platformDirs{goos: "linux", homeDir: "/home/testuser"},
+			profile:  "_side_profiles/work",
+			expected: filepath.Join("/home/testuser", ".config", "opera", "_side_profiles", "work", "Bookmarks"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			path, err := operaVendor.bookmarksPathForPlatform(tc.dirs, tc.profile)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, path)
+		})
+	}
+}
+
+func TestGetOperaBookmarksWithFixture(t *testing.T) {
+	dirs := platformDirs{goos: "linux", homeDir: t.TempDir()}
+	bookmarksFile := writeBookmarksFixture(t, operaVendor, dirs, "Default")
+
+	bookmarks, err := newTestChromium(operaVendor, bookmarksFile).GetBookmarks(context.Background(), "Default")
+	require.NoError(t, err)
+
+	assert.Equal(t, "Opera", NewOpera().Name())
+	assertSampleBookmarks(t, bookmarks)
+}
@@ -0,0 +1,117 @@
+package browser
+
+import (
+	"bytes"
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+// AllProfiles is the profile name that selects every profile of a browser
+const AllProfiles = "all"
+
+// Profile represents a browser profile
+type Profile struct {
+	// Name is the human readable name shown by the browser (e.g. "Work")
+	Name string
+	// Dir is the name of the profile's directory on disk (e.g. "Profile 3")
+	Dir string
+}
+
+// ProfileLister is implemented by browsers that support multiple profiles
+type ProfileLister interface {
+	Profiles() ([]Profile, error)
+}
+
+// GetAllProfiles returns the profiles of all registered browsers that support
+// them. Browsers that are not installed are skipped, the ones whose profiles
+// can't be read are returned as BrowserErrors alongside the other profiles.
+func GetAllProfiles() (map[string][]Profile, error) {
+	result := make(map[string][]Profile)
+	var errs BrowserErrors
+
+	for _, browser := range RegisteredBrowsers {
+		lister, ok := browser.(ProfileLister)
+		if !ok {
+			continue
+		}
+
+		profiles, err := lister.Profiles()
+		if errors.Is(err, ErrNotInstalled) {
+			browserLogger.Debug("Skipping browser that is not installed", "browser", browser.Name(), "error", err)
+			continue
+		}
+		if err != nil {
+			errs = append(errs, toBrowserErrors(browser.Name(), "", err)...)
+			continue
+		}
+		if len(profiles) > 0 {
+			result[browser.Name()] = profiles
+		}
+	}
+
+	if len(errs) > 0 {
+		return result, errs
+	}
+
+	return result, nil
+}
+
+// PrintProfiles prints the profiles in a tabular format
+func PrintProfiles(profiles map[string][]Profile) (string, error) {
+	if len(profiles) == 0 {
+		return "No profiles found", nil
+	}
+
+	browserNames := make([]string, 0, len(profiles))
+	for browserName := range profiles {
+		browserNames = append(browserNames, browserName)
+	}
+	sort.Strings(browserNames)
+
+	var buf bytes.Buffer
+	table := tablewriter.NewWriter(&buf)
+
+	table.SetHeader([]string{"Browser", "Profile", "Directory"})
+	table.SetAutoWrapText(true)
+	table.SetColumnAlignment([]int{
+		tablewriter.ALIGN_LEFT,
+		tablewriter.ALIGN_LEFT,
+		tablewriter.ALIGN_LEFT,
+	})
+	table.SetColWidth(50)
+
+	for _, browserName := range browserNames {
+		for _, profile := range profiles[browserName] {
+			table.Append([]string{
+				browserName,
+				profile.Name,
+				profile.Dir,
+			})
+		}
+	}
+
+	table.Render()
+
+	return buf.String(), nil
+}
+
+// findProfile finds the profile matching name, which can either be the
+// profile's directory or its human readable name
+func findProfile(profiles []Profile, name string) (Profile, bool) {
+	for _, profile := range profiles {
+		if profile.Dir == name {
+			return profile, true
+		}
+	}
+
+	for _, profile := range profiles {
+		if strings.EqualFold(profile.Name, name) || strings.EqualFold(profile.Dir, name) {
+			return profile, true
+		}
+	}
+
+	return Profile{}, false
+}
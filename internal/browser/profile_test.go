@@ -0,0 +1,89 @@
+package browser
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindProfile(t *testing.T) {
+	profiles := []Profile{
+		{Name: "Personal", Dir: "Default"},
+		{Name: "Default", Dir: "Profile 1"},
+		{Name: "Work", Dir: "Profile 3"},
+	}
+
+	testCases := []struct {
+		name        string
+		profile     string
+		expectedDir string
+		expectFound bool
+	}{
+		{name: "Directory wins over a name", profile: "Default", expectedDir: "Default", expectFound: true},
+		{name: "By name", profile: "Work", expectedDir: "Profile 3", expectFound: true},
+		{name: "By name ignoring case", profile: "personal", expectedDir: "Default", expectFound: true},
+		{name: "By directory ignoring case", profile: "profile 3", expectedDir: "Profile 3", expectFound: true},
+		{name: "Unknown profile", profile: "Missing", expectFound: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			profile, found := findProfile(profiles, tc.profile)
+
+			assert.Equal(t, tc.expectFound, found)
+			assert.Equal(t, tc.expectedDir, profile.Dir)
+		})
+	}
+}
+
+// fakeProfileBrowser is a fakeBrowser that lists canned profiles
+type fakeProfileBrowser struct {
+	fakeBrowser
+	profiles []Profile
+}
+
+func (f *fakeProfileBrowser) Profiles() ([]Profile, error) {
+	return f.profiles, f.err
+}
+
+func TestGetAllProfiles(t *testing.T) {
+	withRegisteredBrowsers(t,
+		&fakeProfileBrowser{fakeBrowser: fakeBrowser{name: "Chrome"}, profiles: []Profile{{Name: "Work", Dir: "Profile 3"}}},
+		&fakeProfileBrowser{fakeBrowser: fakeBrowser{name: "Brave", err: fmt.Errorf("local state not found: %w", ErrNotInstalled)}},
+		&fakeProfileBrowser{fakeBrowser: fakeBrowser{name: "Firefox", err: errors.New("failed to parse profiles.ini")}},
+		&fakeBrowser{name: "Safari"},
+	)
+
+	profiles, err := GetAllProfiles()
+
+	assert.Equal(t, map[string][]Profile{"Chrome": {{Name: "Work", Dir: "Profile 3"}}}, profiles)
+
+	var browserErrs BrowserErrors
+	require.ErrorAs(t, err, &browserErrs)
+	require.Len(t, browserErrs, 1, "Should skip browsers that are not installed")
+	assert.Equal(t, "Firefox: failed to parse profiles.ini", browserErrs[0].Error())
+}
+
+func TestPrintProfiles(t *testing.T) {
+	output, err := PrintProfiles(map[string][]Profile{
+		"Chrome": {
+			{Name: "Work", Dir: "Profile 3"},
+		},
+		"Brave": {
+			{Name: "Personal", Dir: "Default"},
+		},
+	})
+	require.NoError(t, err)
+
+	assert.Regexp(t, `(?s)Brave.*Personal.*Default.*Chrome.*Work.*Profile 3`, output)
+}
+
+func TestPrintProfilesEmpty(t *testing.T) {
+	output, err := PrintProfiles(map[string][]Profile{})
+	require.NoError(t, err)
+
+	assert.Equal(t, "No profiles found", output)
+}
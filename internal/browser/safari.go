@@ -0,0 +1,168 @@
+package browser
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/malleatus/tamjaweb/internal/logger"
+	"howett.net/plist"
+)
+
+var safariLogger = logger.New("browser:safari")
+
+// WebBookmarkType values used in Bookmarks.plist
+const (
+	safariTypeList = "WebBookmarkTypeList"
+	safariTypeLeaf = "WebBookmarkTypeLeaf"
+)
+
+// safariReadingListTitle is the Title of the folder that holds the Reading List
+const safariReadingListTitle = "com.apple.ReadingList"
+
+// safariFolderNames maps the titles of Safari's built in folders to the names
+// Safari displays for them
+var safariFolderNames = map[string]string{
+	"BookmarksBar":         "Favorites",
+	"BookmarksMenu":        "Bookmarks Menu",
+	safariReadingListTitle: "Reading List",
+}
+
+type Safari struct {
+	getBookmarksPath BookmarksPathProvider
+}
+
+func init() {
+	safariLogger.Info("Registering Safari browser")
+	RegisterBrowser(NewSafari())
+}
+
+// NewSafari creates a new Safari browser instance with the default path provider
+func NewSafari() *Safari {
+	return &Safari{
+		getBookmarksPath: getSafariBookmarksPath,
+	}
+}
+
+func (s *Safari) Name() string {
+	return "Safari"
+}
+
+// SafariBookmarkNode represents a node in the Bookmarks.plist tree. The same
+// structure is used for folders (WebBookmarkTypeList) and bookmarks
+// (WebBookmarkTypeLeaf).
+type SafariBookmarkNode struct {
+	WebBookmarkType string               `plist:"WebBookmarkType"`
+	WebBookmarkUUID string               `plist:"WebBookmarkUUID"`
+	Title           string               `plist:"Title"`
+	URLString       string               `plist:"URLString"`
+	Children        []SafariBookmarkNode `plist:"Children"`
+	URIDictionary   struct {
+		Title string `plist:"title"`
+	} `plist:"URIDictionary"`
+	ReadingList *struct {
+		DateAdded time.Time `plist:"DateAdded"`
+	} `plist:"ReadingList"`
+}
+
+// GetBookmarksPath is exposed for testing
+func (s *Safari) GetBookmarksPath(profile string) (string, error) {
+	return s.getBookmarksPath(profile)
+}
+
+func (s *Safari) GetBookmarks(ctx context.Context, profile string) ([]Bookmark, error) {
+	bookmarksPath, err := s.getBookmarksPath(profile)
+	if err != nil {
+		safariLogger.Debug("Failed to get Safari bookmarks path", "error", err)
+		return nil, err
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(bookmarksPath)
+	if err != nil {
+		safariLogger.Debug("Failed to read Safari bookmarks file", "path", bookmarksPath, "error", err)
+		return nil, &BrowserError{Browser: s.Name(), Path: bookmarksPath, Err: err}
+	}
+
+	// plist.NewDecoder detects binary, XML and OpenStep formats on its own
+	var root SafariBookmarkNode
+	if err := plist.NewDecoder(bytes.NewReader(data)).Decode(&root); err != nil {
+		safariLogger.Debug("Failed to decode Safari bookmarks plist", "path", bookmarksPath, "error", err)
+		return nil, &BrowserError{Browser: s.Name(), Path: bookmarksPath, Err: err}
+	}
+
+	var bookmarks []Bookmark
+	processSafariBookmarkNodes(&bookmarks, root.Children, nil)
+
+	return bookmarks, nil
+}
+
+// processSafariBookmarkNodes recursively processes the bookmark nodes
+func processSafariBookmarkNodes(bookmarks *[]Bookmark, nodes []SafariBookmarkNode, folders []string) {
+	for _, node := range nodes {
+		switch node.WebBookmarkType {
+		case safariTypeLeaf:
+			title := node.URIDictionary.Title
+			if title == "" {
+				title = node.Title
+			}
+
+			// Safari only records when Reading List items were added
+			var dateAdded time.Time
+			if node.ReadingList != nil {
+				dateAdded = node.ReadingList.DateAdded
+			}
+
+			*bookmarks = append(*bookmarks, Bookmark{
+				GUID:       node.WebBookmarkUUID,
+				Title:      title,
+				URL:        node.URLString,
+				DateAdded:  dateAdded,
+				Folders:    folders,
+				FolderPath: filepath.Join(folders...),
+			})
+		case safariTypeList:
+			name := node.Title
+			if displayName, ok := safariFolderNames[name]; ok {
+				name = displayName
+			}
+			processSafariBookmarkNodes(bookmarks, node.Children, append(slices.Clip(folders), name))
+		}
+	}
+}
+
+// getSafariBookmarksPathForPlatform returns the path to Safari bookmarks for the specified platform
+func getSafariBookmarksPathForPlatform(dirs platformDirs) (string, error) {
+	if dirs.goos != "darwin" {
+		return "", fmt.Errorf("Safari is not available on %s: %w", dirs.goos, ErrNotInstalled)
+	}
+
+	return filepath.Join(dirs.homeDir, "Library", "Safari", "Bookmarks.plist"), nil
+}
+
+// getSafariBookmarksPath returns the path to Safari bookmarks file. Safari has
+// no profiles that keep separate bookmarks, so profile is ignored.
+func getSafariBookmarksPath(profile string) (string, error) {
+	dirs, err := currentPlatformDirs()
+	if err != nil {
+		return "", err
+	}
+
+	path, err := getSafariBookmarksPathForPlatform(dirs)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return "", fmt.Errorf("Safari browser bookmarks not found at %s: %w", path, ErrNotInstalled)
+	}
+
+	return path, nil
+}
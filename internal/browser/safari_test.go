@@ -0,0 +1,115 @@
+package browser
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetSafariBookmarksPathForPlatform(t *testing.T) {
+	testCases := []struct {
+		name        string
+		dirs        platformDirs
+		expected    string
+		expectError bool
+	}{
+		{
+			name:     "macOS path",
+			dirs:     platformDirs{goos: "darwin", homeDir: "/Users/testuser"},
+			expected: filepath.Join("/Users/testuser", "Library", "Safari", "Bookmarks.plist"),
+		},
+		{
+			name:        "Linux is not available",
+			dirs:        platformDirs{goos: "linux", homeDir: "/home/testuser"},
+			expectError: true,
+		},
+		{
+			name:        "Windows is not available",
+			dirs:        platformDirs{goos: "windows", homeDir: `C:\Users\testuser`},
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			path, err := getSafariBookmarksPathForPlatform(tc.dirs)
+
+			if tc.expectError {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), "Safari is not available")
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expected, path)
+			}
+		})
+	}
+}
+
+func TestGetSafariBookmarksWithFixture(t *testing.T) {
+	testCases := []struct {
+		name    string
+		fixture string
+	}{
+		{
+			name:    "XML plist",
+			fixture: "Bookmarks.xml.plist",
+		},
+		{
+			name:    "Binary plist",
+			fixture: "Bookmarks.binary.plist",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			safari := &Safari{
+				getBookmarksPath: func(profile string) (string, error) {
+					return filepath.Join("testdata", "safari", tc.fixture), nil
+				},
+			}
+
+			bookmarks, err := safari.GetBookmarks(context.Background(), "Default")
+			require.NoError(t, err)
+
+			require.Equal(t, 4, len(bookmarks), "Should skip the History proxy")
+
+			assert.Equal(t, "Example Site", bookmarks[0].Title)
+			assert.Equal(t, "https://example.com/", bookmarks[0].URL)
+			assert.Equal(t, "Favorites", bookmarks[0].FolderPath)
+			assert.Equal(t, "5F1A2B0E-6E4F-4A8C-9C2B-3A5E8D1F0002", bookmarks[0].GUID)
+
+			assert.Equal(t, "GitHub", bookmarks[1].Title)
+			assert.Equal(t, "https://github.com/", bookmarks[1].URL)
+			assert.Equal(t, filepath.Join("Favorites", "Work"), bookmarks[1].FolderPath)
+
+			assert.Equal(t, "Other Site", bookmarks[2].Title)
+			assert.Equal(t, "https://othersite.com/", bookmarks[2].URL)
+			assert.Equal(t, "Bookmarks Menu", bookmarks[2].FolderPath)
+
+			assert.Equal(t, "Read Later", bookmarks[3].Title)
+			assert.Equal(t, "https://blog.example.com/post", bookmarks[3].URL)
+			assert.Equal(t, "Reading List", bookmarks[3].FolderPath)
+			assert.Equal(t, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), bookmarks[3].DateAdded.UTC())
+		})
+	}
+}
+
+func TestGetSafariBookmarksInvalidPlist(t *testing.T) {
+	bookmarksFile := filepath.Join(t.TempDir(), "Bookmarks.plist")
+	err := os.WriteFile(bookmarksFile, []byte("bplist00 truncated"), 0644)
+	require.NoError(t, err)
+
+	safari := &Safari{
+		getBookmarksPath: func(profile string) (string, error) {
+			return bookmarksFile, nil
+		},
+	}
+
+	_, err = safari.GetBookmarks(context.Background(), "Default")
+	assert.Error(t, err)
+}
@@ -0,0 +1,468 @@
+package browser
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+	"unicode/utf16"
+)
+
+// Chromium writes sessions (Sessions/Session_*) and recently closed tabs
+// (Sessions/Tabs_*) as SNSS files: a "SNSS" header followed by commands that
+// each record a single change, e.g. a tab navigating. Replaying the commands
+// in order rebuilds the state of the browser.
+const snssMagic = "SNSS"
+
+// SNSS file versions
+const (
+	snssVersion           = 1
+	snssVersionEncrypted  = 2
+	snssVersionWithMarker = 3
+)
+
+// snssMarkerCommand separates the initial state from later changes in
+// snssVersionWithMarker files. It carries no state, so it's skipped.
+const snssMarkerCommand = 255
+
+// Command ids used in Session_* files (see session_service_commands.cc)
+const (
+	sessionCommandSetTabWindow               = 0
+	sessionCommandSetTabIndexInWindow        = 2
+	sessionCommandUpdateTabNavigation        = 6
+	sessionCommandSetSelectedNavigationIndex = 7
+	sessionCommandSetSelectedTabInIndex      = 8
+	sessionCommandSetWindowType              = 9
+	sessionCommandSetPinnedState             = 12
+	sessionCommandTabClosed                  = 16
+	sessionCommandWindowClosed               = 17
+	sessionCommandLastActiveTime             = 21
+	sessionCommandTabNavigationPathPruned    = 24
+)
+
+// Command ids used in Tabs_* files (see tab_restore_service_impl.cc)
+const (
+	tabRestoreCommandUpdateTabNavigation     = 1
+	tabRestoreCommandRestoredEntry           = 2
+	tabRestoreCommandSelectedNavigationInTab = 4
+	tabRestoreCommandPinnedState             = 5
+)
+
+// snssCommand is a single command read from an SNSS file
+type snssCommand struct {
+	id      uint8
+	payload []byte
+}
+
+// readSNSSCommands reads the commands of an SNSS file. A command cut short at
+// the end of the file, which happens when reading while the browser writes,
+// is dropped.
+func readSNSSCommands(r io.Reader) ([]snssCommand, error) {
+	reader := bufio.NewReader(r)
+
+	header := make([]byte, 8)
+	if _, err := io.ReadFull(reader, header); err != nil {
+		return nil, fmt.Errorf("failed to read SNSS header: %w", err)
+	}
+	if string(header[:4]) != snssMagic {
+		return nil, errors.New("not an SNSS file")
+	}
+
+	switch version := int32(binary.LittleEndian.Uint32(header[4:])); version {
+	case snssVersion, snssVersionWithMarker:
+	case snssVersionEncrypted:
+		return nil, errors.New("encrypted SNSS files are not supported")
+	default:
+		return nil, fmt.Errorf("unsupported SNSS version %d", version)
+	}
+
+	var commands []snssCommand
+	for {
+		var size uint16
+		if err := binary.Read(reader, binary.LittleEndian, &size); err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+				return commands, nil
+			}
+			return nil, err
+		}
+		if size == 0 {
+			return commands, nil
+		}
+
+		data := make([]byte, size)
+		if _, err := io.ReadFull(reader, data); err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+				return commands, nil
+			}
+			return nil, err
+		}
+
+		if data[0] == snssMarkerCommand {
+			continue
+		}
+		commands = append(commands, snssCommand{id: data[0], payload: data[1:]})
+	}
+}
+
+// int32At reads the little endian int32 at offset of a fixed size payload.
+// Returns false when the payload is too short.
+func (c snssCommand) int32At(offset int) (int32, bool) {
+	if len(c.payload) < offset+4 {
+		return 0, false
+	}
+	return int32(binary.LittleEndian.Uint32(c.payload[offset:])), true
+}
+
+// int64At reads the little endian int64 at offset of a fixed size payload
+func (c snssCommand) int64At(offset int) (int64, bool) {
+	if len(c.payload) < offset+8 {
+		return 0, false
+	}
+	return int64(binary.LittleEndian.Uint64(c.payload[offset:])), true
+}
+
+// pickleReader reads the base::Pickle encoded payloads of variable sized
+// commands: a uint32 payload size followed by 4 byte aligned fields
+type pickleReader struct {
+	data   []byte
+	offset int
+}
+
+func newPickleReader(payload []byte) (*pickleReader, error) {
+	if len(payload) < 4 {
+		return nil, errors.New("pickle too short")
+	}
+
+	size := int(binary.LittleEndian.Uint32(payload))
+	data := payload[4:]
+	if size < len(data) {
+		data = data[:size]
+	}
+
+	return &pickleReader{data: data}, nil
+}
+
+// next returns the next n bytes, advancing past their padding
+func (p *pickleReader) next(n int) ([]byte, error) {
+	if n < 0 || p.offset+n > len(p.data) {
+		return nil, errors.New("pickle field out of range")
+	}
+
+	field := p.data[p.offset : p.offset+n]
+	p.offset += (n + 3) &^ 3
+
+	return field, nil
+}
+
+func (p *pickleReader) readInt() (int32, error) {
+	field, err := p.next(4)
+	if err != nil {
+		return 0, err
+	}
+	return int32(binary.LittleEndian.Uint32(field)), nil
+}
+
+func (p *pickleReader) readString() (string, error) {
+	length, err := p.readInt()
+	if err != nil {
+		return "", err
+	}
+
+	field, err := p.next(int(length))
+	if err != nil {
+		return "", err
+	}
+	return string(field), nil
+}
+
+func (p *pickleReader) readString16() (string, error) {
+	length, err := p.readInt()
+	if err != nil {
+		return "", err
+	}
+
+	field, err := p.next(int(length) * 2)
+	if err != nil {
+		return "", err
+	}
+
+	units := make([]uint16, length)
+	for i := range units {
+		units[i] = binary.LittleEndian.Uint16(field[i*2:])
+	}
+	return string(utf16.Decode(units)), nil
+}
+
+// chromiumNavigation is one entry of a tab's back/forward history
+type chromiumNavigation struct {
+	url   string
+	title string
+}
+
+// readChromiumNavigation decodes an UpdateTabNavigation payload, which is the
+// same in Session_* and Tabs_* files. Only the leading fields are read.
+func readChromiumNavigation(c snssCommand) (tabID int32, index int32, nav chromiumNavigation, err error) {
+	pickle, err := newPickleReader(c.payload)
+	if err != nil {
+		return 0, 0, nav, err
+	}
+
+	if tabID, err = pickle.readInt(); err != nil {
+		return 0, 0, nav, err
+	}
+	if index, err = pickle.readInt(); err != nil {
+		return 0, 0, nav, err
+	}
+	if nav.url, err = pickle.readString(); err != nil {
+		return 0, 0, nav, err
+	}
+	if nav.title, err = pickle.readString16(); err != nil {
+		return 0, 0, nav, err
+	}
+
+	return tabID, index, nav, nil
+}
+
+// chromiumSessionTab is the state of a tab rebuilt from session commands
+type chromiumSessionTab struct {
+	id          int32
+	window      int32
+	index       int32
+	pinned      bool
+	lastActive  time.Time
+	selected    int32
+	hasSelected bool
+	navigations map[int32]chromiumNavigation
+}
+
+// current returns the navigation the tab is showing
+func (t *chromiumSessionTab) current() (chromiumNavigation, bool) {
+	if nav, ok := t.navigations[t.selected]; ok && t.hasSelected {
+		return nav, true
+	}
+
+	// without a selected index the latest navigation is shown
+	latest, found := int32(0), false
+	for index := range t.navigations {
+		if !found || index > latest {
+			latest, found = index, true
+		}
+	}
+
+	nav, ok := t.navigations[latest]
+	return nav, ok
+}
+
+// prune removes count navigations starting at index, shifting the ones after
+// it down like Chromium does
+func (t *chromiumSessionTab) prune(index, count int32) {
+	pruned := make(map[int32]chromiumNavigation, len(t.navigations))
+	for i, nav := range t.navigations {
+		switch {
+		case i < index:
+			pruned[i] = nav
+		case i >= index+count:
+			pruned[i-count] = nav
+		}
+	}
+	t.navigations = pruned
+}
+
+// parseChromiumSession replays the commands of a Session_* file into the
+// tabs of the windows that are still open, ordered by window and then tab
+func parseChromiumSession(commands []snssCommand) []Tab {
+	tabs := map[int32]*chromiumSessionTab{}
+	windows := map[int32]int32{} // window id to its selected tab index
+
+	tab := func(id int32) *chromiumSessionTab {
+		if _, ok := tabs[id]; !ok {
+			tabs[id] = &chromiumSessionTab{id: id, navigations: map[int32]chromiumNavigation{}}
+		}
+		return tabs[id]
+	}
+
+	for _, command := range commands {
+		first, ok := command.int32At(0)
+		if !ok {
+			continue
+		}
+
+		switch command.id {
+		case sessionCommandSetTabWindow:
+			if tabID, ok := command.int32At(4); ok {
+				tab(tabID).window = first
+				if _, ok := windows[first]; !ok {
+					windows[first] = -1
+				}
+			}
+		case sessionCommandSetWindowType:
+			if _, ok := windows[first]; !ok {
+				windows[first] = -1
+			}
+		case sessionCommandSetTabIndexInWindow:
+			if index, ok := command.int32At(4); ok {
+				tab(first).index = index
+			}
+		case sessionCommandSetSelectedTabInIndex:
+			if index, ok := command.int32At(4); ok {
+				windows[first] = index
+			}
+		case sessionCommandSetPinnedState:
+			if len(command.payload) > 4 {
+				tab(first).pinned = command.payload[4] != 0
+			}
+		case sessionCommandUpdateTabNavigation:
+			tabID, index, nav, err := readChromiumNavigation(command)
+			if err != nil {
+				chromiumLogger.Debug("Skipping unreadable tab navigation", "error", err)
+				continue
+			}
+			tab(tabID).navigations[index] = nav
+		case sessionCommandSetSelectedNavigationIndex:
+			if index, ok := command.int32At(4); ok {
+				t := tab(first)
+				t.selected, t.hasSelected = index, true
+			}
+		case sessionCommandTabNavigationPathPruned:
+			index, okIndex := command.int32At(4)
+			count, okCount := command.int32At(8)
+			if okIndex && okCount {
+				tab(first).prune(index, count)
+			}
+		case sessionCommandLastActiveTime:
+			// payload is {int32 tab id, padding, int64 time}
+			if micros, ok := command.int64At(8); ok {
+				tab(first).lastActive = chromiumMicros(micros)
+			}
+		case sessionCommandTabClosed:
+			delete(tabs, first)
+		case sessionCommandWindowClosed:
+			delete(windows, first)
+		}
+	}
+
+	windowIDs := make([]int32, 0, len(windows))
+	for id := range windows {
+		windowIDs = append(windowIDs, id)
+	}
+	sort.Slice(windowIDs, func(i, j int) bool { return windowIDs[i] < windowIDs[j] })
+
+	var result []Tab
+	for number, windowID := range windowIDs {
+		var windowTabs []*chromiumSessionTab
+		for _, t := range tabs {
+			if t.window == windowID {
+				windowTabs = append(windowTabs, t)
+			}
+		}
+		sort.Slice(windowTabs, func(i, j int) bool {
+			if windowTabs[i].index != windowTabs[j].index {
+				return windowTabs[i].index < windowTabs[j].index
+			}
+			return windowTabs[i].id < windowTabs[j].id
+		})
+
+		for _, t := range windowTabs {
+			nav, ok := t.current()
+			if !ok {
+				continue
+			}
+
+			result = append(result, Tab{
+				ID:         strconv.Itoa(int(t.id)),
+				Window:     number + 1,
+				Index:      int(t.index),
+				Title:      nav.title,
+				URL:        nav.url,
+				Pinned:     t.pinned,
+				Active:     t.index == windows[windowID],
+				LastActive: t.lastActive,
+			})
+		}
+	}
+
+	return result
+}
+
+// parseChromiumTabRestore replays the commands of a Tabs_* file into the
+// recently closed tabs that have not been restored, most recently closed first
+func parseChromiumTabRestore(commands []snssCommand) []Tab {
+	var order []int32
+	tabs := map[int32]*chromiumSessionTab{}
+
+	for _, command := range commands {
+		first, ok := command.int32At(0)
+		if !ok {
+			continue
+		}
+
+		switch command.id {
+		case tabRestoreCommandSelectedNavigationInTab:
+			// payload is {int32 tab id, int32 index, int64 close time}
+			index, okIndex := command.int32At(4)
+			micros, okTime := command.int64At(8)
+			if !okIndex || !okTime {
+				continue
+			}
+			if _, ok := tabs[first]; !ok {
+				order = append(order, first)
+			}
+			tabs[first] = &chromiumSessionTab{
+				id:          first,
+				selected:    index,
+				hasSelected: true,
+				lastActive:  chromiumMicros(micros),
+				navigations: map[int32]chromiumNavigation{},
+			}
+		case tabRestoreCommandUpdateTabNavigation:
+			tabID, index, nav, err := readChromiumNavigation(command)
+			if err != nil {
+				chromiumLogger.Debug("Skipping unreadable closed tab navigation", "error", err)
+				continue
+			}
+			if t, ok := tabs[tabID]; ok {
+				t.navigations[index] = nav
+			}
+		case tabRestoreCommandPinnedState:
+			if t, ok := tabs[first]; ok {
+				t.pinned = true
+			}
+		case tabRestoreCommandRestoredEntry:
+			delete(tabs, first)
+		}
+	}
+
+	var closed []*chromiumSessionTab
+	for _, id := range order {
+		if t, ok := tabs[id]; ok {
+			closed = append(closed, t)
+		}
+	}
+	sort.SliceStable(closed, func(i, j int) bool {
+		return closed[i].lastActive.After(closed[j].lastActive)
+	})
+
+	var result []Tab
+	for _, t := range closed {
+		nav, ok := t.current()
+		if !ok {
+			continue
+		}
+
+		result = append(result, Tab{
+			ID:         strconv.Itoa(int(t.id)),
+			Index:      len(result),
+			Title:      nav.title,
+			URL:        nav.url,
+			Pinned:     t.pinned,
+			LastActive: t.lastActive,
+			Closed:     true,
+		})
+	}
+
+	return result
+}
@@ -0,0 +1,156 @@
+package browser
+
+import (
+	"bytes"
+	"encoding/binary"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	sessionFixture    = filepath.Join("testdata", "chromium", "Sessions", "Session_13353768000000000")
+	tabRestoreFixture = filepath.Join("testdata", "chromium", "Sessions", "Tabs_13353768000000000")
+)
+
+func TestParseChromiumSession(t *testing.T) {
+	commands, err := readSNSSFile(sessionFixture)
+	require.NoError(t, err)
+
+	tabs := parseChromiumSession(commands)
+
+	assert.Equal(t, []Tab{
+		{
+			ID:     "1",
+			Window: 1,
+			Index:  0,
+			Title:  "GitHub",
+			URL:    "https://github.com/",
+			Pinned: true,
+		},
+		{
+			ID:         "2",
+			Window:     1,
+			Index:      1,
+			Title:      "Example Two",
+			URL:        "https://example.com/two",
+			Active:     true,
+			LastActive: time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC).Local(),
+		},
+		{
+			ID:         "4",
+			Window:     2,
+			Index:      0,
+			Title:      "Documentation — Go",
+			URL:        "https://go.dev/doc/",
+			LastActive: time.Date(2024, 3, 1, 14, 0, 0, 0, time.UTC).Local(),
+		},
+	}, tabs, "Should skip closed tabs and windows and apply changes after the marker")
+}
+
+func TestParseChromiumTabRestore(t *testing.T) {
+	commands, err := readSNSSFile(tabRestoreFixture)
+	require.NoError(t, err)
+
+	tabs := parseChromiumTabRestore(commands)
+
+	require.Equal(t, 2, len(tabs), "Should skip restored tabs")
+
+	assert.Equal(t, "Story", tabs[0].Title)
+	assert.Equal(t, "https://news.example.com/story", tabs[0].URL)
+	assert.True(t, tabs[0].Pinned)
+	assert.True(t, tabs[0].Closed)
+	assert.Equal(t, time.Date(2024, 3, 1, 14, 0, 0, 0, time.UTC), tabs[0].LastActive.UTC())
+
+	assert.Equal(t, "Closed Example", tabs[1].Title)
+	assert.Equal(t, "https://closed.example.com/", tabs[1].URL)
+	assert.Equal(t, 1, tabs[1].Index)
+}
+
+func TestReadSNSSCommands(t *testing.T) {
+	// SetActiveWindow, which only carries a window id
+	const setActiveWindow = 20
+
+	header := func(version int32) []byte {
+		var buf bytes.Buffer
+		buf.WriteString("SNSS")
+		_ = binary.Write(&buf, binary.LittleEndian, version)
+		return buf.Bytes()
+	}
+
+	testCases := []struct {
+		name          string
+		data          []byte
+		expectedCount int
+		expectedError string
+	}{
+		{
+			name:          "Empty session",
+			data:          header(1),
+			expectedCount: 0,
+		},
+		{
+			name:          "Single command",
+			data:          append(header(1), 0x03, 0x00, setActiveWindow, 0x01, 0x00),
+			expectedCount: 1,
+		},
+		{
+			name:          "Truncated command is dropped",
+			data:          append(header(3), 0x03, 0x00, setActiveWindow, 0x01, 0x00, 0x10, 0x00, 0x06),
+			expectedCount: 1,
+		},
+		{
+			name:          "Marker is skipped",
+			data:          append(header(3), 0x03, 0x00, setActiveWindow, 0x01, 0x00, 0x01, 0x00, snssMarkerCommand),
+			expectedCount: 1,
+		},
+		{
+			name:          "Not an SNSS file",
+			data:          []byte("{\"roots\": {}}"),
+			expectedError: "not an SNSS file",
+		},
+		{
+			name:          "Encrypted",
+			data:          header(2),
+			expectedError: "encrypted SNSS files are not supported",
+		},
+		{
+			name:          "Short header",
+			data:          []byte("SNS"),
+			expectedError: "failed to read SNSS header",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			commands, err := readSNSSCommands(bytes.NewReader(tc.data))
+
+			if tc.expectedError != "" {
+				assert.ErrorContains(t, err, tc.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedCount, len(commands))
+		})
+	}
+}
+
+func TestChromiumSessionTabPrune(t *testing.T) {
+	tab := &chromiumSessionTab{navigations: map[int32]chromiumNavigation{
+		0: {url: "https://a.example.com"},
+		1: {url: "https://b.example.com"},
+		2: {url: "https://c.example.com"},
+		3: {url: "https://d.example.com"},
+	}}
+
+	tab.prune(1, 2)
+
+	assert.Equal(t, map[int32]chromiumNavigation{
+		0: {url: "https://a.example.com"},
+		1: {url: "https://d.example.com"},
+	}, tab.navigations)
+}
@@ -0,0 +1,88 @@
+package browser
+
+import (
+	"database/sql"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	// pure Go SQLite driver, keeps the binary cgo-free
+	_ "modernc.org/sqlite"
+)
+
+// openSQLiteCopy copies the database at path (along with its write-ahead log,
+// when present) into a temporary directory and opens the copy read-only. Browsers
+// keep their databases locked while running, so reading a snapshot avoids
+// both lock contention and the risk of touching the live file.
+//
+// The returned cleanup function closes the database and removes the copy.
+func openSQLiteCopy(path string) (*sql.DB, func(), error) {
+	tmpDir, err := os.MkdirTemp("", "tamjaweb-sqlite")
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+
+	removeTmpDir := func() {
+		_ = os.RemoveAll(tmpDir)
+	}
+
+	copyPath := filepath.Join(tmpDir, filepath.Base(path))
+	if err := copyFile(path, copyPath); err != nil {
+		removeTmpDir()
+		return nil, nil, err
+	}
+
+	// uncheckpointed writes only live in the write-ahead log
+	err = copyFile(path+"-wal", copyPath+"-wal")
+	if err != nil && !os.IsNotExist(err) {
+		removeTmpDir()
+		return nil, nil, err
+	}
+
+	// read-only, so a query can't change the snapshot by mistake. The path is
+	// escaped as ? and # in it would otherwise end it early, and given a
+	// leading / on Windows so the drive letter isn't read as a host.
+	uriPath := filepath.ToSlash(copyPath)
+	if !strings.HasPrefix(uriPath, "/") {
+		uriPath = "/" + uriPath
+	}
+	uri := url.URL{Scheme: "file", Path: uriPath, RawQuery: "mode=ro"}
+	db, err := sql.Open("sqlite", uri.String())
+	if err != nil {
+		removeTmpDir()
+		return nil, nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+
+	cleanup := func() {
+		_ = db.Close()
+		removeTmpDir()
+	}
+
+	return db, cleanup, nil
+}
+
+// copyFile copies the file at src to dst
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = in.Close()
+	}()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return fmt.Errorf("failed to copy %s: %w", src, err)
+	}
+
+	return out.Close()
+}
@@ -0,0 +1,68 @@
+package browser
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenSQLiteCopy(t *testing.T) {
+	testCases := []struct {
+		name string
+		// tmpDir is where the copy is made, below a temporary directory
+		tmpDir string
+		file   string
+	}{
+		{name: "Plain path", file: "History"},
+		{name: "Characters URIs treat specially", tmpDir: "a#b?c%20d", file: "History #1 %20"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tc.file)
+
+			if tc.tmpDir != "" {
+				tmpDir := filepath.Join(t.TempDir(), tc.tmpDir)
+				require.NoError(t, os.Mkdir(tmpDir, 0700))
+				t.Setenv("TMPDIR", tmpDir)
+			}
+
+			testOpenSQLiteCopy(t, path)
+		})
+	}
+}
+
+func testOpenSQLiteCopy(t *testing.T, path string) {
+
+	// a browser that is still running, with writes only in the write-ahead log
+	live, err := sql.Open("sqlite", path)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = live.Close()
+	})
+	live.SetMaxOpenConns(1)
+	for _, stmt := range []string{
+		"PRAGMA journal_mode=WAL",
+		"PRAGMA wal_autocheckpoint=0",
+		"CREATE TABLE urls (url TEXT)",
+		"INSERT INTO urls VALUES ('https://example.com')",
+	} {
+		_, err := live.Exec(stmt)
+		require.NoError(t, err)
+	}
+
+	db, cleanup, err := openSQLiteCopy(path)
+	require.NoError(t, err)
+	defer cleanup()
+
+	var url string
+	require.NoError(t, db.QueryRow("SELECT url FROM urls").Scan(&url))
+	assert.Equal(t, "https://example.com", url)
+
+	_, err = db.Exec("INSERT INTO urls VALUES ('https://changed.example.com')")
+	assert.ErrorContains(t, err, "readonly", "Should open the copy read-only")
+}
@@ -0,0 +1,49 @@
+package browser
+
+import (
+	"context"
+	"time"
+)
+
+// Tab represents an open, or recently closed, browser tab
+type Tab struct {
+	// ID is the browser's id for the tab, only unique within a single session
+	ID string
+	// Window numbers the profile's windows from 1, 0 for closed tabs
+	Window int
+	// Index is the tab's position in its window
+	Index  int
+	Title  string
+	URL    string
+	Pinned bool
+	// Active is set for the selected tab of each window
+	Active bool
+	// LastActive is when the tab was last selected, or closed for closed tabs.
+	// Zero when the browser doesn't record it.
+	LastActive time.Time
+	// Closed marks recently closed tabs that the browser can restore
+	Closed  bool
+	Profile string
+}
+
+// TabSource is implemented by browsers that can list their tabs
+type TabSource interface {
+	Name() string
+	GetTabs(ctx context.Context, profile string) ([]Tab, error)
+}
+
+// GetAllTabs returns the tabs of every registered browser that is a
+// TabSource. Tabs are loaded the same way as GetAllBookmarks loads bookmarks,
+// including how failures are reported.
+func GetAllTabs(ctx context.Context, profile string) (map[string][]Tab, error) {
+	var sources []Browser
+	for _, browser := range RegisteredBrowsers {
+		if _, ok := browser.(TabSource); ok {
+			sources = append(sources, browser)
+		}
+	}
+
+	return loadAll(ctx, sources, profile, func(ctx context.Context, browser Browser, profile string) ([]Tab, error) {
+		return browser.(TabSource).GetTabs(ctx, profile)
+	})
+}
@@ -0,0 +1,24 @@
+package browser
+
+import (
+	"github.com/malleatus/tamjaweb/internal/logger"
+)
+
+var vivaldiLogger = logger.New("browser:vivaldi")
+
+var vivaldiVendor = chromiumVendor{
+	name:    "Vivaldi",
+	windows: []string{"Vivaldi", "User Data"},
+	darwin:  []string{"Vivaldi"},
+	linux:   []string{"vivaldi"},
+}
+
+func init() {
+	vivaldiLogger.Info("Registering Vivaldi browser")
+	RegisterBrowser(NewVivaldi())
+}
+
+// NewVivaldi creates a new Vivaldi browser instance with the default path provider
+func NewVivaldi() *Chromium {
+	return newChromium(vivaldiVendor)
+}
@@ -0,0 +1,57 @@
+package browser
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetVivaldiBookmarksPathForPlatform(t *testing.T) {
+	testCases := []struct {
+		name     string
+		dirs     platformDirs
+		profile  string
+		expected string
+	}{
+		{
+			name:     "Windows path",
+			dirs:     platformDirs{goos: "windows", homeDir: `C:\Users\testuser`, localAppData: `C:\Users\testuser\AppData\Local`},
+			profile:  "Default",
+			expected: filepath.Join(`C:\Users\testuser\AppData\Local`, "Vivaldi", "User Data", "Default", "Bookmarks"),
+		},
+		{
+			name:     "macOS path",
+			dirs:     platformDirs{goos: "darwin", homeDir: "/Users/testuser"},
+			profile:  "Default",
+			expected: filepath.Join("/Users/testuser", "Library", "Application Support", "Vivaldi", "Default", "Bookmarks"),
+		},
+		{
+			name:     "Linux path",
+			dirs:     platformDirs{goos: "linux", homeDir: "/home/testuser"},
+			profile:  "Default",
+			expected: filepath.Join("/home/testuser", ".config", "vivaldi", "Default", "Bookmarks"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			path, err := vivaldiVendor.bookmarksPathForPlatform(tc.dirs, tc.profile)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, path)
+		})
+	}
+}
+
+func TestGetVivaldiBookmarksWithFixture(t *testing.T) {
+	dirs := platformDirs{goos: "linux", homeDir: t.TempDir()}
+	bookmarksFile := writeBookmarksFixture(t, vivaldiVendor, dirs, "Default")
+
+	bookmarks, err := newTestChromium(vivaldiVendor, bookmarksFile).GetBookmarks(context.Background(), "Default")
+	require.NoError(t, err)
+
+	assert.Equal(t, "Vivaldi", NewVivaldi().Name())
+	assertSampleBookmarks(t, bookmarks)
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 )
 
@@ -64,7 +65,35 @@ func (c *CacheStore[T]) Write(items []T) error {
 		return fmt.Errorf("failed to encode cache: %w", err)
 	}
 
-	return os.WriteFile(c.filePath, data, 0644)
+	return WriteFile(c.filePath, data)
+}
+
+// WriteFile replaces path with data through a temporary file, so a refresh
+// cut short when the process exits never leaves a half written cache
+func WriteFile(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write cache: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write cache: %w", err)
+	}
+	if err := tmp.Chmod(0644); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write cache: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write cache: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write cache: %w", err)
+	}
+
+	return nil
 }
 
 // UpdateWithFilter updates cache by removing items that match the filter and adding new ones
@@ -98,3 +127,70 @@ func (c *CacheStore[T]) IsOutdated(maxAge time.Duration) (bool, error) {
 	}
 	return time.Since(info.ModTime()) > maxAge, nil
 }
+
+// timestampsPath is where the update times of the cache's keys are stored,
+// next to the cache
+func (c *CacheStore[T]) timestampsPath() string {
+	return strings.TrimSuffix(c.filePath, filepath.Ext(c.filePath)) + ".updated.json"
+}
+
+// readTimestamps reads when each key of the cache was last updated
+func (c *CacheStore[T]) readTimestamps() (map[string]time.Time, error) {
+	timestamps := map[string]time.Time{}
+
+	data, err := os.ReadFile(c.timestampsPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return timestamps, nil
+		}
+		return nil, fmt.Errorf("failed to read cache timestamps: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &timestamps); err != nil {
+		return nil, fmt.Errorf("failed to parse cache timestamps: %w", err)
+	}
+
+	return timestamps, nil
+}
+
+// UpdatedAt returns when the items of key were last updated, see Touch. It
+// is zero when that was never recorded.
+func (c *CacheStore[T]) UpdatedAt(key string) (time.Time, error) {
+	timestamps, err := c.readTimestamps()
+	if err != nil {
+		return time.Time{}, err
+	}
+	return timestamps[key], nil
+}
+
+// Touch records that the items of key were updated at t. Caches shared by
+// several keys (e.g. the stars of several users) use this to expire each
+// key separately, instead of by when the file was last written.
+func (c *CacheStore[T]) Touch(key string, t time.Time) error {
+	timestamps, err := c.readTimestamps()
+	if err != nil {
+		return err
+	}
+	timestamps[key] = t
+
+	data, err := json.MarshalIndent(timestamps, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode cache timestamps: %w", err)
+	}
+
+	return WriteFile(c.timestampsPath(), data)
+}
+
+// IsOutdatedFor checks if the items of key were updated longer ago than
+// maxAge. Keys without an update time fall back to IsOutdated, which is how
+// caches written before keys were timestamped expire.
+func (c *CacheStore[T]) IsOutdatedFor(key string, maxAge time.Duration) (bool, error) {
+	updatedAt, err := c.UpdatedAt(key)
+	if err != nil {
+		return false, err
+	}
+	if updatedAt.IsZero() {
+		return c.IsOutdated(maxAge)
+	}
+	return time.Since(updatedAt) > maxAge, nil
+}
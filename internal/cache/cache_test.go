@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
@@ -172,6 +173,52 @@ func (s *CacheTestSuite) Test_CacheStore_UpdateWithFilter() {
 	s.True(found, "New item should be in the cache")
 }
 
+func (s *CacheTestSuite) Test_CacheStore_Touch() {
+	cache, err := New[TestItem]("touch-test.json")
+	s.NoError(err)
+
+	updatedAt, err := cache.UpdatedAt("alice")
+	s.NoError(err)
+	s.True(updatedAt.IsZero(), "Keys that were never touched have no update time")
+
+	fetchedAt := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	s.NoError(cache.Touch("alice", fetchedAt))
+	s.NoError(cache.Touch("bob", fetchedAt.Add(time.Hour)))
+
+	updatedAt, err = cache.UpdatedAt("alice")
+	s.NoError(err)
+	s.True(fetchedAt.Equal(updatedAt))
+
+	updatedAt, err = cache.UpdatedAt("bob")
+	s.NoError(err)
+	s.True(fetchedAt.Add(time.Hour).Equal(updatedAt), "Each key keeps its own time")
+}
+
+func (s *CacheTestSuite) Test_CacheStore_IsOutdatedFor() {
+	cache, err := New[TestItem]("outdated-test.json")
+	s.NoError(err)
+
+	outdated, err := cache.IsOutdatedFor("alice", time.Hour)
+	s.NoError(err)
+	s.True(outdated, "A missing cache is outdated")
+
+	s.NoError(cache.Write([]TestItem{{ID: 1, Name: "Item 1"}}))
+	outdated, err = cache.IsOutdatedFor("alice", time.Hour)
+	s.NoError(err)
+	s.False(outdated, "Keys without an update time use the file's modification time")
+
+	s.NoError(cache.Touch("alice", time.Now().Add(-2*time.Hour)))
+	s.NoError(cache.Touch("bob", time.Now()))
+
+	outdated, err = cache.IsOutdatedFor("alice", time.Hour)
+	s.NoError(err)
+	s.True(outdated)
+
+	outdated, err = cache.IsOutdatedFor("bob", time.Hour)
+	s.NoError(err)
+	s.False(outdated)
+}
+
 func TestCacheSuite(t *testing.T) {
 	suite.Run(t, new(CacheTestSuite))
 }
@@ -0,0 +1,59 @@
+// Package config reads tamjaweb's config file, config.yaml in the tamjaweb
+// directory of os.UserConfigDir (~/.config/tamjaweb/config.yaml on Linux):
+//
+//	github:
+//	  token: ghp_...
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+type Config struct {
+	GitHub GitHub `yaml:"github"`
+}
+
+type GitHub struct {
+	// Token authenticates requests to the GitHub API, it is only used when
+	// no other token is found (see github.ResolveToken)
+	Token string `yaml:"token"`
+}
+
+// Path returns where the config file is read from
+func Path() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get config directory: %w", err)
+	}
+
+	return filepath.Join(configDir, "tamjaweb", "config.yaml"), nil
+}
+
+// Load reads the config file, a missing file is an empty config
+func Load() (Config, error) {
+	var config Config
+
+	path, err := Path()
+	if err != nil {
+		return config, err
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return config, nil
+	}
+	if err != nil {
+		return config, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return config, fmt.Errorf("failed to parse config %s: %w", path, err)
+	}
+
+	return config, nil
+}
@@ -0,0 +1,54 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeConfig points the config directory at a temporary directory holding
+// contents as the config file, or no config file when contents is empty
+func writeConfig(t *testing.T, contents string) string {
+	t.Helper()
+
+	configDir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", configDir)
+	t.Setenv("HOME", configDir)
+	t.Setenv("AppData", configDir)
+
+	path, err := Path()
+	require.NoError(t, err)
+
+	if contents != "" {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(contents), 0644))
+	}
+
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	writeConfig(t, "github:\n  token: config-token\n")
+
+	config, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, "config-token", config.GitHub.Token)
+}
+
+func TestLoadMissing(t *testing.T) {
+	writeConfig(t, "")
+
+	config, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, Config{}, config)
+}
+
+func TestLoadInvalid(t *testing.T) {
+	path := writeConfig(t, "github: [token\n")
+
+	_, err := Load()
+	assert.ErrorContains(t, err, "failed to parse config "+path)
+}
@@ -0,0 +1,22 @@
+// Package execrunner runs external commands, such as gh and xdg-open, behind
+// an interface so tests can inject a mock
+package execrunner
+
+import "os/exec"
+
+// Runner is an interface for executing commands, so we can inject a mock
+// during tests.
+type Runner interface {
+	Run(name string, args ...string) ([]byte, error)
+}
+
+// RealRunner uses the actual `exec.Command`.
+type RealRunner struct{}
+
+// Default runs every command, tests replace it with a mock
+var Default Runner = &RealRunner{}
+
+func (r *RealRunner) Run(name string, args ...string) ([]byte, error) {
+	cmd := exec.Command(name, args...)
+	return cmd.Output()
+}
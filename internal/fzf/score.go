@@ -0,0 +1,211 @@
+package fzf
+
+import (
+	"strconv"
+	"strings"
+
+	fzflib "github.com/junegunn/fzf/src"
+	"github.com/junegunn/fzf/src/algo"
+	"github.com/junegunn/fzf/src/util"
+)
+
+// term is a word of an extended search term, e.g. 'exact, ^prefix or !negated
+type term struct {
+	match         algo.Algo
+	text          []rune
+	inverse       bool
+	caseSensitive bool
+	normalize     bool
+}
+
+// scorer scores matches the way fzf does. fzf's filter prints the matches
+// without their scores and doesn't export its pattern, so the term is split
+// like fzf's parseTerms and matched with the algorithm, case and
+// normalization fzf parsed from the same options. Parsing them also set up
+// fzf's scoring scheme.
+type scorer struct {
+	// sets of terms joined with |, only one of which has to match
+	sets      [][]term
+	delimiter string
+	nth       string
+	forward   bool
+}
+
+func newScorer(search string, opts FilterOptions, options *fzflib.Options) scorer {
+	defaultMatch, flippedMatch := options.FuzzyAlgo, algo.ExactMatchNaive
+	if !options.Fuzzy {
+		defaultMatch, flippedMatch = algo.ExactMatchNaive, options.FuzzyAlgo
+	}
+
+	var sets [][]term
+	var set []term
+	switchSet, afterBar := false, false
+	// escaped spaces are kept in the term they belong to
+	tokens := strings.FieldsFunc(strings.ReplaceAll(search, "\\ ", "\t"), func(r rune) bool { return r == ' ' })
+	for _, token := range tokens {
+		text := strings.ReplaceAll(token, "\t", " ")
+		lowerText := strings.ToLower(text)
+		t := term{
+			match:         defaultMatch,
+			caseSensitive: options.Case == fzflib.CaseRespect || options.Case == fzflib.CaseSmart && text != lowerText,
+			normalize:     options.Normalize && lowerText == string(algo.NormalizeRunes([]rune(lowerText))),
+		}
+		if !t.caseSensitive {
+			text = lowerText
+		}
+
+		if len(set) > 0 && !afterBar && text == "|" {
+			switchSet, afterBar = false, true
+			continue
+		}
+		afterBar = false
+
+		if strings.HasPrefix(text, "!") {
+			t.inverse = true
+			t.match = algo.ExactMatchNaive
+			text = text[1:]
+		}
+
+		suffix := text != "$" && strings.HasSuffix(text, "$")
+		if suffix {
+			t.match = algo.SuffixMatch
+			text = text[:len(text)-1]
+		}
+
+		switch {
+		case len(text) > 2 && strings.HasPrefix(text, "'") && strings.HasSuffix(text, "'"):
+			t.match = algo.ExactMatchBoundary
+			text = text[1 : len(text)-1]
+		case strings.HasPrefix(text, "'"):
+			if t.inverse {
+				t.match = options.FuzzyAlgo
+			} else {
+				t.match = flippedMatch
+			}
+			text = text[1:]
+		case strings.HasPrefix(text, "^"):
+			if suffix {
+				t.match = algo.EqualMatch
+			} else {
+				t.match = algo.PrefixMatch
+			}
+			text = text[1:]
+		}
+
+		if text == "" {
+			continue
+		}
+
+		if switchSet {
+			sets = append(sets, set)
+			set = nil
+		}
+		t.text = []rune(text)
+		if t.normalize {
+			t.text = algo.NormalizeRunes(t.text)
+		}
+		set = append(set, t)
+		switchSet = true
+	}
+	if len(set) > 0 {
+		sets = append(sets, set)
+	}
+
+	return scorer{sets: sets, delimiter: opts.Delimiter, nth: opts.Nth, forward: forward(opts.Tiebreak)}
+}
+
+// forward reports whether fzf matches from the start of the input with
+// tiebreak, the last of begin, end or pathname decides
+func forward(tiebreak string) bool {
+	criteria := strings.Split(tiebreak, ",")
+	for i := len(criteria) - 1; i >= 0; i-- {
+		switch criteria[i] {
+		case "begin":
+			return true
+		case "end", "pathname":
+			return false
+		}
+	}
+	return true
+}
+
+// score adds up the score of the first matching term of each set. Each term
+// is matched against the first of the selected fields it matches, negated
+// terms don't add to the score.
+func (s scorer) score(input string) int {
+	fields := selectFields(input, s.delimiter, s.nth)
+	chars := make([]util.Chars, len(fields))
+	for i, field := range fields {
+		chars[i] = util.ToChars([]byte(field))
+	}
+
+	total := 0
+	for _, set := range s.sets {
+	terms:
+		for _, t := range set {
+			if t.inverse {
+				continue
+			}
+			for i := range chars {
+				result, _ := t.match(t.caseSensitive, t.normalize, s.forward, &chars[i], t.text, false, nil)
+				if result.Start >= 0 {
+					total += result.Score
+					break terms
+				}
+			}
+		}
+	}
+
+	return total
+}
+
+// selectFields returns the fields of text that nth selects, see
+// FilterOptions.Nth. A range of fields is returned as one field, joined
+// with the delimiter, like fzf matches them.
+func selectFields(text, delimiter, nth string) []string {
+	if nth == "" {
+		return []string{text}
+	}
+
+	var fields []string
+	if delimiter == "" {
+		fields = strings.Fields(text)
+		delimiter = " "
+	} else {
+		fields = strings.Split(text, delimiter)
+	}
+
+	var selected []string
+	for _, expr := range strings.Split(nth, ",") {
+		begin, end := fieldRange(expr, len(fields))
+		if begin < end {
+			selected = append(selected, strings.Join(fields[begin:end], delimiter))
+		}
+	}
+
+	return selected
+}
+
+// fieldRange converts a field index expression (3, -1, 2.., ..3, 2..-2) to
+// the slice bounds of the fields it selects out of count fields
+func fieldRange(expr string, count int) (int, int) {
+	// index converts a 1 based index, negative from the end, to a 0 based one
+	index := func(s string, missing int) int {
+		i, err := strconv.Atoi(s)
+		switch {
+		case s == "" || err != nil:
+			return missing
+		case i < 0:
+			return max(count+i, 0)
+		}
+		return min(max(i-1, 0), count)
+	}
+
+	first, last, isRange := strings.Cut(expr, "..")
+	if !isRange {
+		i := index(first, count)
+		return i, min(i+1, count)
+	}
+
+	return index(first, 0), min(index(last, count-1)+1, count)
+}
@@ -10,32 +10,113 @@ import (
 	fzflib "github.com/junegunn/fzf/src"
 )
 
+// How search terms are matched to the case of the inputs
+const (
+	// CaseSmart ignores case unless the term has upper case letters
+	CaseSmart = "smart"
+	// CaseIgnore always ignores case (fzf's -i)
+	CaseIgnore = "ignore"
+	// CaseRespect never ignores case (fzf's +i)
+	CaseRespect = "respect"
+)
+
+// Fuzzy matching algorithms, see fzf's --algo
+const (
+	// AlgoV1 is faster but doesn't always find the best match
+	AlgoV1 = "v1"
+	// AlgoV2 finds the best match
+	AlgoV2 = "v2"
+)
+
 // FilterOptions defines configuration options for fzf filtering
 type FilterOptions struct {
-	Delimiter   string
-	MatchFields string
+	// Delimiter splits the inputs into fields for Nth, whitespace when empty
+	Delimiter string
+	// Nth selects the fields that are matched against, in fzf's --nth syntax
+	// (1, 2.., ..-2, 2,4). Every field is matched when empty.
+	Nth string
+	// Exact matches terms exactly, a leading ' makes a term fuzzy instead
+	Exact bool
+	// Case is one of CaseSmart, CaseIgnore or CaseRespect
+	Case string
+	// Algo is the fuzzy matching algorithm, AlgoV1 or AlgoV2
+	Algo string
+	// Tiebreak orders matches with the same score, in fzf's --tiebreak
+	// syntax (length, begin, end, index or a comma separated list of them)
+	Tiebreak string
+	// Literal matches accented letters only to themselves, instead of also
+	// to their unaccented form
+	Literal bool
 }
 
 // DefaultFilterOptions returns the default filtering options
 func DefaultFilterOptions() FilterOptions {
 	return FilterOptions{
-		Delimiter:   "\t",
-		MatchFields: "2..", // Only match against text after index + tab
+		Delimiter: "\t",
+		Nth:       "2..", // Only match against text after index + tab
+		Case:      CaseSmart,
+		Algo:      AlgoV2,
+		Tiebreak:  "length",
 	}
 }
 
-// FilterStrings runs fzf's filter functionality on a list of strings
-// Returns the indices of matched strings
-func FilterStrings(inputs []string, term string) ([]int, error) {
+// args returns fzf's options for filtering with term
+func (o FilterOptions) args(term string) []string {
+	args := []string{"--filter", term}
+
+	if o.Delimiter != "" {
+		args = append(args, "--delimiter", o.Delimiter)
+	}
+	if o.Nth != "" {
+		args = append(args, "--nth", o.Nth)
+	}
+	if o.Exact {
+		args = append(args, "--exact")
+	}
+	switch o.Case {
+	case CaseIgnore:
+		args = append(args, "-i")
+	case CaseRespect:
+		args = append(args, "+i")
+	default:
+		args = append(args, "--smart-case")
+	}
+	if o.Algo != "" {
+		args = append(args, "--algo", o.Algo)
+	}
+	if o.Tiebreak != "" {
+		args = append(args, "--tiebreak", o.Tiebreak)
+	}
+	if o.Literal {
+		args = append(args, "--literal")
+	}
+
+	return args
+}
+
+// Match is an input that matched the search term
+type Match struct {
+	// Index of the input in the slice passed to FilterStrings
+	Index int
+	// Score is fzf's score for the match, higher is better. It is 0 when
+	// there is no term.
+	Score int
+}
+
+// FilterStrings runs fzf's filter functionality on a list of strings, each
+// starting with its index and a tab
+// Returns the matched strings in fzf's order, best match first and matches
+// with the same score ordered by opts.Tiebreak
+func FilterStrings(inputs []string, term string, opts FilterOptions) ([]Match, error) {
 	log.Debug("FilterStrings called", "term", term, "input_count", len(inputs))
 
 	if term == "" {
-		// If term is empty, return all indices
-		indices := make([]int, len(inputs))
+		// If term is empty, every input matches in order
+		matches := make([]Match, len(inputs))
 		for i := range inputs {
-			indices[i] = i
+			matches[i] = Match{Index: i}
 		}
-		return indices, nil
+		return matches, nil
 	}
 
 	inputChan := make(chan string)
@@ -50,15 +131,12 @@ func FilterStrings(inputs []string, term string) ([]int, error) {
 
 	options, err := fzflib.ParseOptions(
 		false, // don't load defaults
-		[]string{
-			"--filter", term,
-			"--delimiter", "\t",
-			"--with-nth", "2..", // Only match against text after index + tab
-		},
+		opts.args(term),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to build fzf options: %w", err)
 	}
+	scorer := newScorer(term, opts, options)
 
 	options.Input = inputChan
 	options.Output = outputChan
@@ -66,7 +144,7 @@ func FilterStrings(inputs []string, term string) ([]int, error) {
 	var wg sync.WaitGroup
 	wg.Add(1)
 
-	var matchedIndices []int
+	var matches []Match
 
 	go func() {
 		defer wg.Done()
@@ -74,7 +152,7 @@ func FilterStrings(inputs []string, term string) ([]int, error) {
 			parts := strings.SplitN(match, "\t", 2)
 			if len(parts) >= 1 {
 				if idx, err := strconv.Atoi(parts[0]); err == nil && idx >= 0 && idx < len(inputs) {
-					matchedIndices = append(matchedIndices, idx)
+					matches = append(matches, Match{Index: idx})
 				}
 			}
 		}
@@ -89,5 +167,9 @@ func FilterStrings(inputs []string, term string) ([]int, error) {
 
 	wg.Wait()
 
-	return matchedIndices, nil
+	for i := range matches {
+		matches[i].Score = scorer.score(inputs[matches[i].Index])
+	}
+
+	return matches, nil
 }
@@ -0,0 +1,254 @@
+package fzf
+
+import (
+	"cmp"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	fzflib "github.com/junegunn/fzf/src"
+	"github.com/junegunn/fzf/src/algo"
+	"github.com/junegunn/fzf/src/util"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testInputs = []string{
+	"0\tGitHub Pulls\thttps://github.com/pulls",
+	"1\tgithub search\thttps://example.com/search",
+	"2\tCafé Racer\thttps://racer.example.com",
+	"3\tgo pkg long title\thttps://pkg.go.dev/long",
+	"4\tgo pkg\thttps://pkg.go.dev",
+}
+
+func TestFilterStrings(t *testing.T) {
+	withOptions := func(change func(*FilterOptions)) FilterOptions {
+		opts := DefaultFilterOptions()
+		change(&opts)
+		return opts
+	}
+
+	testCases := []struct {
+		name     string
+		term     string
+		opts     FilterOptions
+		expected []int
+	}{
+		{
+			name:     "Empty term matches everything in order",
+			term:     "",
+			opts:     DefaultFilterOptions(),
+			expected: []int{0, 1, 2, 3, 4},
+		},
+		{
+			name:     "Fuzzy",
+			term:     "gthbsrch",
+			opts:     DefaultFilterOptions(),
+			expected: []int{1},
+		},
+		{
+			name:     "Exact",
+			term:     "gthbsrch",
+			opts:     withOptions(func(o *FilterOptions) { o.Exact = true }),
+			expected: nil,
+		},
+		{
+			name:     "Exact with a fuzzy word",
+			term:     "'gthbsrch",
+			opts:     withOptions(func(o *FilterOptions) { o.Exact = true }),
+			expected: []int{1},
+		},
+		{
+			name:     "Smart case respects upper case",
+			term:     "GitHub",
+			opts:     withOptions(func(o *FilterOptions) { o.Nth = "2" }),
+			expected: []int{0},
+		},
+		{
+			name:     "Smart case ignores lower case",
+			term:     "github",
+			opts:     withOptions(func(o *FilterOptions) { o.Nth = "2" }),
+			expected: []int{0, 1},
+		},
+		{
+			name:     "Ignore case",
+			term:     "GitHub",
+			opts:     withOptions(func(o *FilterOptions) { o.Nth = "2"; o.Case = CaseIgnore }),
+			expected: []int{0, 1},
+		},
+		{
+			name:     "Respect case",
+			term:     "github",
+			opts:     withOptions(func(o *FilterOptions) { o.Nth = "2"; o.Case = CaseRespect }),
+			expected: []int{1},
+		},
+		{
+			name:     "Algorithm v1",
+			term:     "gthbsrch",
+			opts:     withOptions(func(o *FilterOptions) { o.Algo = AlgoV1 }),
+			expected: []int{1},
+		},
+		{
+			name:     "Ties go to the shortest by default",
+			term:     "'go pkg",
+			opts:     withOptions(func(o *FilterOptions) { o.Nth = "2" }),
+			expected: []int{4, 3},
+		},
+		{
+			name:     "Ties in input order",
+			term:     "'go pkg",
+			opts:     withOptions(func(o *FilterOptions) { o.Nth = "2"; o.Tiebreak = "index" }),
+			expected: []int{3, 4},
+		},
+		{
+			name:     "Only the selected fields",
+			term:     "example",
+			opts:     withOptions(func(o *FilterOptions) { o.Nth = "2" }),
+			expected: nil,
+		},
+		{
+			name:     "Fields counted from the end",
+			term:     "'example",
+			opts:     withOptions(func(o *FilterOptions) { o.Nth = "-1" }),
+			expected: []int{1, 2},
+		},
+		{
+			name:     "Accents are normalized",
+			term:     "'cafe",
+			opts:     DefaultFilterOptions(),
+			expected: []int{2},
+		},
+		{
+			name:     "Literal",
+			term:     "'cafe",
+			opts:     withOptions(func(o *FilterOptions) { o.Literal = true }),
+			expected: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			matches, err := FilterStrings(testInputs, tc.term, tc.opts)
+			require.NoError(t, err)
+
+			var indices []int
+			for _, match := range matches {
+				indices = append(indices, match.Index)
+				assert.Equal(t, tc.term != "", match.Score > 0, "Input %d should only be scored when searching", match.Index)
+			}
+			assert.Equal(t, tc.expected, indices)
+		})
+	}
+}
+
+// fzfOrder runs fzf itself and returns the indices of the matches in the
+// order it prints them
+func fzfOrder(t *testing.T, inputs []string, term string, opts FilterOptions) []int {
+	options, err := fzflib.ParseOptions(false, opts.args(term))
+	require.NoError(t, err)
+
+	inputChan := make(chan string, len(inputs))
+	for _, input := range inputs {
+		inputChan <- input
+	}
+	close(inputChan)
+
+	outputChan := make(chan string, len(inputs))
+	options.Input = inputChan
+	options.Output = outputChan
+
+	_, err = fzflib.Run(options)
+	require.NoError(t, err)
+	close(outputChan)
+
+	var indices []int
+	for line := range outputChan {
+		index, _, _ := strings.Cut(line, "\t")
+		i, err := strconv.Atoi(index)
+		require.NoError(t, err)
+		indices = append(indices, i)
+	}
+	return indices
+}
+
+func TestFilterStringsScoresLikeFzf(t *testing.T) {
+	inputs := []string{
+		"0\tgo pkg long title\thttps://pkg.go.dev/long",
+		"1\tGo Packages\thttps://pkg.go.dev",
+		"2\tgopher pkg\thttps://example.com/gopher",
+		"3\tgolang/go packages\thttps://github.com/golang/go",
+		"4\tpkg go\thttps://example.com/pkg",
+		"5\tunrelated\thttps://example.com",
+		"6\tgo\thttps://go.dev",
+	}
+
+	for _, term := range []string{"gopkg", "go pkg", "go\\ pkg", "'go !long", "^go | pkg$", "pkg", "Go", "'pkg'"} {
+		t.Run(term, func(t *testing.T) {
+			// fzf orders by score first, so with ties in input order the
+			// scores alone have to reproduce its order
+			opts := DefaultFilterOptions()
+			opts.Tiebreak = "index"
+
+			matches, err := FilterStrings(inputs, term, opts)
+			require.NoError(t, err)
+
+			byScore := slices.Clone(matches)
+			slices.SortStableFunc(byScore, func(a, b Match) int {
+				return cmp.Or(cmp.Compare(b.Score, a.Score), cmp.Compare(a.Index, b.Index))
+			})
+
+			var indices []int
+			for _, match := range byScore {
+				indices = append(indices, match.Index)
+			}
+			assert.Equal(t, fzfOrder(t, inputs, term, opts), indices)
+		})
+	}
+}
+
+func TestFilterStringsScore(t *testing.T) {
+	matches, err := FilterStrings(testInputs, "gopkg", DefaultFilterOptions())
+	require.NoError(t, err)
+	require.NotEmpty(t, matches)
+
+	// the default options match everything after the index as one field
+	chars := util.ToChars([]byte("go pkg\thttps://pkg.go.dev"))
+	expected, _ := algo.FuzzyMatchV2(false, true, true, &chars, []rune("gopkg"), false, nil)
+
+	index := slices.IndexFunc(matches, func(m Match) bool { return m.Index == 4 })
+	require.GreaterOrEqual(t, index, 0)
+	assert.Equal(t, expected.Score, matches[index].Score)
+}
+
+func TestSelectFields(t *testing.T) {
+	text := "0\ttitle\turl\tfolder"
+
+	testCases := []struct {
+		nth      string
+		expected []string
+	}{
+		{nth: "", expected: []string{text}},
+		{nth: "2", expected: []string{"title"}},
+		{nth: "2,4", expected: []string{"title", "folder"}},
+		{nth: "2..", expected: []string{"title\turl\tfolder"}},
+		{nth: "..2", expected: []string{"0\ttitle"}},
+		{nth: "2..-2", expected: []string{"title\turl"}},
+		{nth: "-1", expected: []string{"folder"}},
+		{nth: "5", expected: nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.nth, func(t *testing.T) {
+			assert.Equal(t, tc.expected, selectFields(text, "\t", tc.nth))
+		})
+	}
+
+	assert.Equal(t, []string{"b"}, selectFields("a  b c", "", "2"))
+}
+
+func TestFilterStringsInvalidOptions(t *testing.T) {
+	_, err := FilterStrings(testInputs, "go", FilterOptions{Algo: "v3"})
+	assert.ErrorContains(t, err, "failed to build fzf options")
+}
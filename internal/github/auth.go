@@ -0,0 +1,90 @@
+package github
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/google/go-github/v70/github"
+	"github.com/malleatus/tamjaweb/internal/config"
+	"github.com/malleatus/tamjaweb/internal/logger"
+	"github.com/spf13/pflag"
+)
+
+var githubLogger = logger.New("github")
+
+// Where ResolveToken found the token
+const (
+	TokenSourceFlag   = "--token"
+	TokenSourceGitHub = "GITHUB_TOKEN"
+	TokenSourceGH     = "GH_TOKEN"
+	TokenSourceGHAuth = "gh auth token"
+	TokenSourceConfig = "config file"
+)
+
+// ResolveToken returns the token to authenticate GitHub API requests with and
+// where it came from, trying in order: flagToken (--token), the GITHUB_TOKEN
+// and GH_TOKEN environment variables, `gh auth token` and the github.token
+// entry of the config file. Both are empty when there is no token, requests
+// are then anonymous and limited to 60 an hour.
+func ResolveToken(flagToken string) (string, string, error) {
+	if flagToken != "" {
+		return flagToken, TokenSourceFlag, nil
+	}
+
+	for _, name := range []string{TokenSourceGitHub, TokenSourceGH} {
+		if token := os.Getenv(name); token != "" {
+			return token, name, nil
+		}
+	}
+
+	token, err := GetGitHubToken()
+	if err != nil {
+		// usually gh isn't installed or isn't logged in
+		githubLogger.Debug("No token from gh", "error", err)
+	} else if token != "" {
+		return token, TokenSourceGHAuth, nil
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return "", "", err
+	}
+	if cfg.GitHub.Token != "" {
+		return cfg.GitHub.Token, TokenSourceConfig, nil
+	}
+
+	return "", "", nil
+}
+
+// AddFetchFlags adds the --token, --max-age, --refresh, --offline and
+// --max-wait flags. The user flag differs between commands, so it is left to
+// them.
+func AddFetchFlags(flags *pflag.FlagSet, opts *FetchOptions) {
+	flags.StringVar(&opts.Token, "token", "", "GitHub token, defaults to $GITHUB_TOKEN, $GH_TOKEN, 'gh auth token' or github.token in the config file")
+
+	flags.DurationVar(&opts.MaxAge, "max-age", 24*time.Hour, "Refresh cached stars in the background once they are older than this, 0 never refreshes them")
+
+	flags.BoolVar(&opts.Refresh, "refresh", false, "Fetch the stars even when they are cached")
+
+	flags.BoolVar(&opts.Offline, "offline", false, "Only use cached stars, never fetch them")
+
+	flags.DurationVar(&opts.MaxWait, "max-wait", time.Minute, "How long fetching stars waits for GitHub's rate limits to reset before giving up")
+}
+
+// newClient builds the client for fetches, authenticated with the token
+// ResolveToken finds
+func newClient(flagToken string) (*github.Client, error) {
+	token, source, err := ResolveToken(flagToken)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find a GitHub token: %w", err)
+	}
+
+	if source == "" {
+		githubLogger.Debug("No GitHub token found, making anonymous requests")
+	} else {
+		githubLogger.Debug("Using GitHub token", "source", source)
+	}
+
+	return BuildGitHubClient(token), nil
+}
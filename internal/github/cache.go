@@ -1,6 +1,8 @@
 package github
 
 import (
+	"time"
+
 	"github.com/malleatus/tamjaweb/internal/cache"
 )
 
@@ -18,7 +20,8 @@ func GetCachedStars() ([]Star, error) {
 	return starsCache.Read()
 }
 
-// WriteCachedStars updates the cache with stars for a specific stargazer
+// WriteCachedStars updates the cache with stars for a specific stargazer,
+// recording that they were fetched now
 func WriteCachedStars(stargazer string, stars []Star) error {
 	starsCache, err := getStarsCache()
 	if err != nil {
@@ -30,5 +33,120 @@ func WriteCachedStars(stargazer string, stars []Star) error {
 		return star.Stargazer == stargazer
 	}
 
-	return starsCache.UpdateWithFilter(stargazerFilter, stars)
+	if err := starsCache.UpdateWithFilter(stargazerFilter, stars); err != nil {
+		return err
+	}
+
+	return starsCache.Touch(stargazer, time.Now())
+}
+
+// StarsFetchedAt returns when the stars of stargazer were last fetched, zero
+// when that wasn't recorded
+func StarsFetchedAt(stargazer string) (time.Time, error) {
+	starsCache, err := getStarsCache()
+	if err != nil {
+		return time.Time{}, err
+	}
+	return starsCache.UpdatedAt(stargazer)
+}
+
+// CachedStarsOutdated checks if the stars of stargazer were fetched longer
+// than maxAge ago. Several users share the cache, so this goes by when each
+// user's stars were fetched rather than when the cache was written.
+func CachedStarsOutdated(stargazer string, maxAge time.Duration) (bool, error) {
+	starsCache, err := getStarsCache()
+	if err != nil {
+		return false, err
+	}
+	return starsCache.IsOutdatedFor(stargazer, maxAge)
+}
+
+// reconciledKey is the cache timestamp of when every star of stargazer was
+// last fetched, as opposed to only the new ones
+func reconciledKey(stargazer string) string {
+	return stargazer + ":reconciled"
+}
+
+// StarsReconciledAt returns when every star of stargazer was last fetched,
+// zero when that wasn't recorded
+func StarsReconciledAt(stargazer string) (time.Time, error) {
+	starsCache, err := getStarsCache()
+	if err != nil {
+		return time.Time{}, err
+	}
+	return starsCache.UpdatedAt(reconciledKey(stargazer))
+}
+
+// MarkStarsReconciled records that every star of stargazer was fetched now
+func MarkStarsReconciled(stargazer string) error {
+	starsCache, err := getStarsCache()
+	if err != nil {
+		return err
+	}
+	return starsCache.Touch(reconciledKey(stargazer), time.Now())
+}
+
+// checkpointMaxAge is how long a checkpoint is resumed from, pages shift as
+// repositories are unstarred
+const checkpointMaxAge = 24 * time.Hour
+
+// starsCheckpoint is a fetch of every star of Stargazer that failed part
+// way, see starsFetch.all
+type starsCheckpoint struct {
+	Stargazer string
+	// NextPage is the page the fetch failed on
+	NextPage int
+	Stars    []Star
+	SavedAt  time.Time
+}
+
+// getStarsCheckpointCache returns the cache for checkpoints of fetches
+func getStarsCheckpointCache() (*cache.CacheStore[starsCheckpoint], error) {
+	return cache.New[starsCheckpoint]("stars-checkpoints.json")
+}
+
+// readStarsCheckpoint returns the checkpoint of stargazer's stars, nil when
+// there is none or it is too old to resume from
+func readStarsCheckpoint(stargazer string) (*starsCheckpoint, error) {
+	checkpointCache, err := getStarsCheckpointCache()
+	if err != nil {
+		return nil, err
+	}
+
+	checkpoints, err := checkpointCache.Read()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, checkpoint := range checkpoints {
+		if checkpoint.Stargazer == stargazer && time.Since(checkpoint.SavedAt) < checkpointMaxAge {
+			return &checkpoint, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// writeStarsCheckpoint replaces the checkpoint of checkpoint.Stargazer
+func writeStarsCheckpoint(checkpoint starsCheckpoint) error {
+	checkpointCache, err := getStarsCheckpointCache()
+	if err != nil {
+		return err
+	}
+
+	return checkpointCache.UpdateWithFilter(func(c starsCheckpoint) bool {
+		return c.Stargazer == checkpoint.Stargazer
+	}, []starsCheckpoint{checkpoint})
+}
+
+// clearStarsCheckpoint removes the checkpoint of stargazer
+func clearStarsCheckpoint(stargazer string) error {
+	checkpointCache, err := getStarsCheckpointCache()
+	if err != nil {
+		return err
+	}
+
+	return checkpointCache.UpdateWithFilter(func(c starsCheckpoint) bool {
+		return c.Stargazer == stargazer
+	}, nil)
 }
@@ -3,19 +3,55 @@ package github
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
-	"os/exec"
+	"net/http"
+	"path/filepath"
 	"strings"
 	"time"
 
 	"github.com/charmbracelet/log"
 	"github.com/google/go-github/v70/github"
-	"github.com/malleatus/tamjaweb/internal/fzf"
+	"github.com/malleatus/tamjaweb/internal/cache"
+	"github.com/malleatus/tamjaweb/internal/execrunner"
+	"github.com/malleatus/tamjaweb/internal/item"
+	"github.com/malleatus/tamjaweb/internal/output"
+	"github.com/malleatus/tamjaweb/internal/query"
 	"github.com/olekukonko/tablewriter"
 )
 
-type Options struct {
+// FetchOptions control where stars come from, see GetAllStars
+type FetchOptions struct {
 	User string
+	// Token authenticates requests to the GitHub API, see ResolveToken for
+	// where it is looked up when empty
+	Token string
+	// MaxAge is how long cached stars are used before they are refreshed in
+	// the background, 0 never refreshes them
+	MaxAge time.Duration
+	// Refresh fetches the stars even when they are cached
+	Refresh bool
+	// Offline never fetches stars, only cached stars are used
+	Offline bool
+	// MaxWait is how long a fetch waits for rate limits to reset in total,
+	// before giving up
+	MaxWait time.Duration
+}
+
+// Validate reports options that contradict each other
+func (o FetchOptions) Validate() error {
+	if o.Refresh && o.Offline {
+		return errors.New("refreshing and staying offline are mutually exclusive")
+	}
+
+	return nil
+}
+
+type Options struct {
+	FetchOptions
+	Sort   output.SortOptions
+	Search item.SearchOptions
+	Output output.Options
 }
 
 // Star represents a starred repository on GitHub
@@ -25,37 +61,138 @@ type Star struct {
 	Description string
 	URL         string
 	StarredAt   string
+	// Language is the main language of the repository, empty for stars
+	// cached before it was recorded
+	Language string
+	// Score is fzf's score for the search term, see fzf.Match. It is 0
+	// when not searching, and not cached.
+	Score int `json:"-"`
+}
+
+// BuildGitHubClient builds the client for token, which is empty for an
+// anonymous client
+var BuildGitHubClient = func(token string) *github.Client {
+	client := github.NewClient(newHTTPClient())
+	if token != "" {
+		client = client.WithAuthToken(token)
+	}
+	return client
 }
 
-var BuildGitHubClient = func() *github.Client {
-	return github.NewClient(nil)
+// newHTTPClient returns the client for requests to GitHub, which makes them
+// conditional on the responses stored in the cache directory, see
+// ConditionalTransport
+func newHTTPClient() *http.Client {
+	cacheDir, err := cache.GetCacheDir()
+	if err != nil {
+		githubLogger.Debug("Not storing responses without a cache directory", "error", err)
+		return nil
+	}
+
+	return &http.Client{
+		Transport: &ConditionalTransport{Dir: filepath.Join(cacheDir, "http"), MaxAge: StoredResponseMaxAge},
+	}
 }
 
-func GetAllStars(user string) ([]Star, error) {
+// GetAllStars returns the stars of opts.User from the cache, fetching them
+// first when they aren't cached or with opts.Refresh. Stars cached longer
+// than opts.MaxAge ago are returned straight away and fetched again in the
+// background, the returned Refresh reports how that went. It is nil when
+// nothing is fetched in the background.
+func GetAllStars(opts FetchOptions) ([]Star, *Refresh, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, nil, err
+	}
+
 	stars, err := GetCachedStars()
 	if err != nil {
-		return nil, fmt.Errorf("error fetching cached stars: %v", err)
+		return nil, nil, fmt.Errorf("error fetching cached stars: %v", err)
 	}
 
 	filteredStars := []Star{}
 	for _, star := range stars {
-		if star.Stargazer == user {
+		if star.Stargazer == opts.User {
 			filteredStars = append(filteredStars, star)
 		}
 	}
 	stars = filteredStars
 
-	if len(stars) == 0 {
-		// no cached stars, do the lookup blocking
-		stars, err = fetchStars(user)
+	fetchedAt, err := StarsFetchedAt(opts.User)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error fetching cached stars: %v", err)
+	}
+	// a user that starred nothing has no stars, but a fetch time
+	cached := len(stars) > 0 || !fetchedAt.IsZero()
+
+	switch {
+	case opts.Offline:
+		if !cached {
+			return nil, nil, fmt.Errorf("no cached stars for %s, run without --offline to fetch them", opts.User)
+		}
+		return stars, nil, nil
+
+	case opts.Refresh || !cached:
+		// do the lookup blocking
+		stars, err = refreshStars(opts, stars)
 		if err != nil {
-			return nil, fmt.Errorf("error fetching stars from GitHub: %v", err)
+			return nil, nil, err
 		}
+		return stars, nil, nil
+	}
 
-		err = WriteCachedStars(user, stars)
+	if opts.MaxAge > 0 {
+		outdated, err := CachedStarsOutdated(opts.User, opts.MaxAge)
 		if err != nil {
-			return nil, fmt.Errorf("error writing stars to cache: %v", err)
+			return nil, nil, fmt.Errorf("error fetching cached stars: %v", err)
 		}
+		if outdated {
+			githubLogger.Debug("Cached stars are outdated, refreshing them in the background", "user", opts.User, "fetched_at", fetchedAt)
+			return stars, startRefresh(opts, stars), nil
+		}
+	}
+
+	return stars, nil, nil
+}
+
+// ReconcileInterval is how often every star is fetched again, which is how
+// unstarred repositories are noticed. In between only the stars added since
+// the newest cached one are fetched. 0 always fetches every star.
+var ReconcileInterval = 7 * 24 * time.Hour
+
+// refreshStars fetches the stars of opts.User and caches them. cached are the
+// user's cached stars, which only the newer stars are added to unless it is
+// time to reconcile them.
+func refreshStars(opts FetchOptions, cached []Star) ([]Star, error) {
+	client, err := newClient(opts.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	reconciledAt, err := StarsReconciledAt(opts.User)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching cached stars: %v", err)
+	}
+	reconcile := len(cached) == 0 || ReconcileInterval <= 0 || time.Since(reconciledAt) > ReconcileInterval
+
+	fetch := &starsFetch{client: client, user: opts.User, retry: &retrier{maxWait: opts.MaxWait}}
+
+	var stars []Star
+	if reconcile {
+		githubLogger.Debug("Fetching every star", "user", opts.User, "reconciled_at", reconciledAt)
+		stars, err = fetch.all()
+	} else {
+		stars, err = fetch.newerThan(cached)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error fetching stars from GitHub: %v", err)
+	}
+
+	err = WriteCachedStars(opts.User, stars)
+	if err == nil && reconcile {
+		err = MarkStarsReconciled(opts.User)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error writing stars to cache: %v", err)
 	}
 
 	return stars, nil
@@ -65,25 +202,130 @@ func GetAllStars(user string) ([]Star, error) {
 // really used in tests. Value of 0 means no limit (fetch all pages).
 var MaxPages int = 0
 
-func fetchStars(user string) ([]Star, error) {
+// starsFetch fetches the stars of user
+type starsFetch struct {
+	client *github.Client
+	user   string
+	retry  *retrier
+}
+
+// all fetches every star, oldest first. A fetch that fails part way is
+// checkpointed, and the next one resumes from the page it failed on.
+func (f *starsFetch) all() ([]Star, error) {
+	page := 1
+	var stars []Star
+
+	checkpoint, err := readStarsCheckpoint(f.user)
+	if err != nil {
+		githubLogger.Debug("Ignoring checkpoint that can't be read", "error", err)
+	} else if checkpoint != nil {
+		githubLogger.Debug("Resuming fetch", "user", f.user, "page", checkpoint.NextPage, "stars", len(checkpoint.Stars))
+		page, stars = checkpoint.NextPage, checkpoint.Stars
+	}
+
+	fetched, nextPage, err := f.list("asc", page, nil)
+	stars = append(stars, fetched...)
+	if err != nil {
+		if nextPage > 1 {
+			checkpointErr := writeStarsCheckpoint(starsCheckpoint{
+				Stargazer: f.user,
+				NextPage:  nextPage,
+				Stars:     stars,
+				SavedAt:   time.Now(),
+			})
+			if checkpointErr != nil {
+				githubLogger.Debug("Failed to checkpoint stars", "error", checkpointErr)
+			} else {
+				err = fmt.Errorf("%w, the next fetch resumes from page %d", err, nextPage)
+			}
+		}
+		return nil, err
+	}
+
+	if err := clearStarsCheckpoint(f.user); err != nil {
+		return nil, err
+	}
+
+	return stars, nil
+}
+
+// starKey identifies a star, a repository starred again is a new star
+type starKey struct {
+	repo      string
+	starredAt string
+}
+
+// newerThan fetches the stars that are newer than cached and adds them to
+// it. The stars are fetched newest first and paging stops at the first
+// cached one, which usually takes a single request.
+func (f *starsFetch) newerThan(cached []Star) ([]Star, error) {
+	known := make(map[starKey]bool, len(cached))
+	for _, star := range cached {
+		known[starKey{star.Repo, star.StarredAt}] = true
+	}
+
+	newStars, _, err := f.list("desc", 1, func(star Star) bool {
+		return known[starKey{star.Repo, star.StarredAt}]
+	})
+	if err != nil {
+		return nil, err
+	}
+	githubLogger.Debug("Fetched new stars", "user", f.user, "count", len(newStars))
+
+	return mergeStars(cached, newStars), nil
+}
+
+// mergeStars adds newStars, newest first, to cached, oldest first. Repositories
+// starred again move to the end.
+func mergeStars(cached []Star, newStars []Star) []Star {
+	starredAgain := make(map[string]bool, len(newStars))
+	for _, star := range newStars {
+		starredAgain[star.Repo] = true
+	}
+
+	merged := make([]Star, 0, len(cached)+len(newStars))
+	for _, star := range cached {
+		if !starredAgain[star.Repo] {
+			merged = append(merged, star)
+		}
+	}
+	for i := len(newStars) - 1; i >= 0; i-- {
+		merged = append(merged, newStars[i])
+	}
+
+	return merged
+}
+
+// list lists the stars sorted by when they were starred in direction, asc
+// or desc, starting at page. Listing stops before the first star known
+// returns true for, known is nil to list every star. On errors the stars
+// listed so far are returned with the page that failed.
+func (f *starsFetch) list(direction string, page int, known func(Star) bool) ([]Star, int, error) {
 	var stars []Star
 
-	ctx := context.Background()
-	client := BuildGitHubClient()
+	// the retrier waits out rate limits, after which the request is left to
+	// GitHub rather than go-github's guess of when the limit resets
+	ctx := context.WithValue(context.Background(), github.BypassRateLimitCheck, true)
 
 	opts := &github.ActivityListStarredOptions{
 		Sort:      "created",
-		Direction: "asc",
+		Direction: direction,
 		ListOptions: github.ListOptions{
-			Page: 1,
+			Page: page,
 		},
 	}
 
 	pageCount := 0
 	for {
-		starredRepos, resp, err := client.Activity.ListStarred(ctx, user, opts)
+		var starredRepos []*github.StarredRepository
+		var resp *github.Response
+		err := f.retry.do(func() (*github.Response, error) {
+			var err error
+			starredRepos, resp, err = f.client.Activity.ListStarred(ctx, f.user, opts)
+			return resp, err
+		})
 		if err != nil {
-			return nil, fmt.Errorf("error fetching starred repositories: %v", err)
+			return stars, opts.Page, fmt.Errorf("error fetching starred repositories: %v", err)
 		}
 
 		for _, starred := range starredRepos {
@@ -98,13 +340,18 @@ func fetchStars(user string) ([]Star, error) {
 					description = *starred.Repository.Description
 				}
 
-				stars = append(stars, Star{
-					Stargazer:   user,
+				star := Star{
+					Stargazer:   f.user,
 					Repo:        repo,
 					Description: description,
 					URL:         repoURL,
 					StarredAt:   starredAt,
-				})
+					Language:    starred.Repository.GetLanguage(),
+				}
+				if known != nil && known(star) {
+					return stars, 0, nil
+				}
+				stars = append(stars, star)
 			}
 		}
 		pageCount++
@@ -117,47 +364,89 @@ func fetchStars(user string) ([]Star, error) {
 		opts.Page = resp.NextPage
 	}
 
-	return stars, nil
+	return stars, 0, nil
 }
 
+// MatchFields are the fields of a star search terms are matched against
+var MatchFields = []string{item.FieldTitle, item.FieldDescription}
+
+// SearchFields lists the fields --in accepts for stars, title being the
+// repository
+var SearchFields = []string{item.FieldTitle, item.FieldURL, item.FieldDescription}
+
 // FilterStarsByTerm filters stars using fzf's filter functionality
-// Returns an array of Stars
-func FilterStarsByTerm(stars []Star, term string) []Star {
-	// If term is empty, return all bookmarks
+// Returns the matching Stars with their scores, best match first
+func FilterStarsByTerm(stars []Star, term string, opts item.SearchOptions) []Star {
+	// If term is empty, return all stars
 	if term == "" {
 		return stars
 	}
 
-	// Create inputs for fzf
-	inputs := make([]string, len(stars))
-	for i, star := range stars {
-		// Create searchable text combining all fields
-		inputs[i] = fmt.Sprintf("%d\t%s\t%s",
-			i,
-			star.Repo,
-			star.Description,
-		)
-	}
-
-	// Use the FZF utility to filter
-	matchedIndices, err := fzf.FilterStrings(inputs, term)
+	matches, err := item.Match(Items(stars), term, opts, MatchFields)
 	if err != nil {
 		log.Error("Failed to filter stars", "error", err)
 		var empty []Star
 		return empty
 	}
 
-	// Build filtered bookmarks map
-	filteredStars := make([]Star, 0, len(matchedIndices))
-
-	for _, idx := range matchedIndices {
-		star := stars[idx]
+	filteredStars := make([]Star, 0, len(matches))
+	for _, match := range matches {
+		star := stars[match.Index]
+		star.Score = match.Score
 		filteredStars = append(filteredStars, star)
 	}
 
 	return filteredStars
 }
 
+// Item adapts the star for searches across sources
+func (s Star) Item() item.Item {
+	return item.Item{
+		Source:      item.SourceStars,
+		Browser:     "GitHub",
+		Profile:     s.Stargazer,
+		Title:       s.Repo,
+		URL:         s.URL,
+		Description: s.Description,
+		Date:        starredAt(s),
+		Details: []item.Detail{
+			{Label: "Language", Value: s.Language},
+		},
+		Score: s.Score,
+	}
+}
+
+// Items adapts stars for searches across sources, keeping their order
+func Items(stars []Star) []item.Item {
+	items := make([]item.Item, 0, len(stars))
+	for _, star := range stars {
+		items = append(items, star.Item())
+	}
+	return items
+}
+
+// StarQueryFields are the fields star searches can filter on, e.g.
+// lang:go user:octocat starred:<2024
+var StarQueryFields = query.Fields[Star]{
+	"repo":        query.Contains(func(s Star) string { return s.Repo }),
+	"description": query.Contains(func(s Star) string { return s.Description }),
+	"url":         query.Contains(func(s Star) string { return s.URL }),
+	"lang":        query.Equals(func(s Star) string { return s.Language }),
+	"user":        query.Equals(func(s Star) string { return s.Stargazer }),
+	"starred":     query.Date(starredAt),
+	"before":      query.Before(starredAt),
+	"after":       query.After(starredAt),
+}
+
+// starredAt parses the date a star was made, the zero time if it can't
+func starredAt(star Star) time.Time {
+	t, err := time.ParseInLocation(time.DateOnly, star.StarredAt, time.Local)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
 func PrintStars(stars []Star) (string, error) {
 	if len(stars) == 0 {
 		return "No stars found", nil
@@ -191,26 +480,9 @@ func PrintStars(stars []Star) (string, error) {
 	return buf.String(), nil
 }
 
-// ExecRunner is an interface for executing commands, so we can inject a mock
-// during tests.
-type ExecRunner interface {
-	Run(name string, args ...string) ([]byte, error)
-}
-
-// RealExecRunner uses the actual `exec.Command`.
-type RealExecRunner struct{}
-
-var DefaultExecRunner ExecRunner = &RealExecRunner{}
-
-func (r *RealExecRunner) Run(name string, args ...string) ([]byte, error) {
-	cmd := exec.Command(name, args...)
-	return cmd.Output()
-}
-
 // GetGitHubToken runs `gh auth token` and returns the trimmed output.
 func GetGitHubToken() (string, error) {
-	runner := DefaultExecRunner
-	out, err := runner.Run("gh", "auth", "token")
+	out, err := execrunner.Default.Run("gh", "auth", "token")
 	if err != nil {
 		return "", fmt.Errorf("failed to run gh auth token: %w", err)
 	}
@@ -1,12 +1,26 @@
 package github
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/bradleyjkemp/cupaloy/v2"
 	"github.com/google/go-github/v70/github"
+	"github.com/malleatus/tamjaweb/internal/execrunner"
+	"github.com/malleatus/tamjaweb/internal/item"
+	"github.com/malleatus/tamjaweb/internal/output"
+	"github.com/malleatus/tamjaweb/internal/query"
 	"github.com/stretchr/testify/suite"
 	"gopkg.in/dnaeon/go-vcr.v4/pkg/recorder"
 )
@@ -17,8 +31,8 @@ type GitHubTestSuite struct {
 	originalHomeDir           string
 	tempHomeDir               string
 	originalMaxPages          int
-	originalExecRunner        ExecRunner
-	originalBuildGitHubClient func() *github.Client
+	originalExecRunner        execrunner.Runner
+	originalBuildGitHubClient func(token string) *github.Client
 	mockRunner                *mockRunner
 }
 
@@ -39,11 +53,11 @@ func (m *mockRunner) Run(name string, args ...string) ([]byte, error) {
 
 // SetupTest runs before each test in the suite.
 func (s *GitHubTestSuite) SetupTest() {
-	s.originalExecRunner = DefaultExecRunner
+	s.originalExecRunner = execrunner.Default
 	s.originalBuildGitHubClient = BuildGitHubClient
 	s.mockRunner = &mockRunner{}
 
-	DefaultExecRunner = s.mockRunner
+	execrunner.Default = s.mockRunner
 
 	homeDir := os.Getenv("HOME")
 
@@ -62,7 +76,7 @@ func (s *GitHubTestSuite) SetupTest() {
 // TearDownTest runs after each test in the suite.
 func (s *GitHubTestSuite) TearDownTest() {
 	MaxPages = s.originalMaxPages
-	DefaultExecRunner = s.originalExecRunner
+	execrunner.Default = s.originalExecRunner
 	BuildGitHubClient = s.originalBuildGitHubClient
 
 	err := os.Setenv("HOME", s.originalHomeDir)
@@ -93,6 +107,718 @@ func (s *GitHubTestSuite) TestGetGitHubToken_Error() {
 	s.Contains(err.Error(), "execution failed")
 }
 
+func (s *GitHubTestSuite) TestResolveToken() {
+	configPath := filepath.Join(s.tempHomeDir, ".config", "tamjaweb", "config.yaml")
+	s.T().Setenv("XDG_CONFIG_HOME", filepath.Join(s.tempHomeDir, ".config"))
+
+	testCases := []struct {
+		name           string
+		flag           string
+		env            map[string]string
+		ghOutput       string
+		config         string
+		expectedToken  string
+		expectedSource string
+	}{
+		{
+			name:           "Flag first",
+			flag:           "flag-token",
+			env:            map[string]string{"GITHUB_TOKEN": "env-token"},
+			expectedToken:  "flag-token",
+			expectedSource: TokenSourceFlag,
+		},
+		{
+			name:           "GITHUB_TOKEN before GH_TOKEN",
+			env:            map[string]string{"GITHUB_TOKEN": "github-token", "GH_TOKEN": "gh-token"},
+			ghOutput:       "gh-auth-token\n",
+			expectedToken:  "github-token",
+			expectedSource: TokenSourceGitHub,
+		},
+		{
+			name:           "GH_TOKEN",
+			env:            map[string]string{"GH_TOKEN": "gh-token"},
+			expectedToken:  "gh-token",
+			expectedSource: TokenSourceGH,
+		},
+		{
+			name:           "gh auth token",
+			ghOutput:       "gh-auth-token\n",
+			config:         "github:\n  token: config-token\n",
+			expectedToken:  "gh-auth-token",
+			expectedSource: TokenSourceGHAuth,
+		},
+		{
+			name:           "Config file",
+			config:         "github:\n  token: config-token\n",
+			expectedToken:  "config-token",
+			expectedSource: TokenSourceConfig,
+		},
+		{
+			name: "Anonymous",
+		},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			for _, name := range []string{"GITHUB_TOKEN", "GH_TOKEN"} {
+				s.T().Setenv(name, tc.env[name])
+			}
+
+			s.mockRunner.Output = []byte(tc.ghOutput)
+			s.mockRunner.Err = nil
+			if tc.ghOutput == "" {
+				s.mockRunner.Err = errors.New("gh: command not found")
+			}
+
+			s.Require().NoError(os.RemoveAll(configPath))
+			if tc.config != "" {
+				s.Require().NoError(os.MkdirAll(filepath.Dir(configPath), 0755))
+				s.Require().NoError(os.WriteFile(configPath, []byte(tc.config), 0644))
+			}
+
+			token, source, err := ResolveToken(tc.flag)
+			s.Require().NoError(err)
+			s.Equal(tc.expectedToken, token)
+			s.Equal(tc.expectedSource, source)
+		})
+	}
+}
+
+func (s *GitHubTestSuite) TestGetAllStarsAuthenticates() {
+	var authorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		_, _ = w.Write([]byte("[]"))
+	}))
+	s.T().Cleanup(server.Close)
+
+	BuildGitHubClient = func(token string) *github.Client {
+		client := s.originalBuildGitHubClient(token)
+		client.BaseURL, _ = url.Parse(server.URL + "/")
+		return client
+	}
+
+	_, _, err := GetAllStars(FetchOptions{User: "rwjblue", Token: "flag-token"})
+	s.Require().NoError(err)
+	s.Equal("Bearer flag-token", authorization)
+}
+
+// serveStars points BuildGitHubClient at a server starring repos for every
+// user, it returns the number of requests made
+func (s *GitHubTestSuite) serveStars(repos ...string) *int {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		starred := []*github.StarredRepository{}
+		for _, repo := range repos {
+			starred = append(starred, &github.StarredRepository{
+				StarredAt: &github.Timestamp{Time: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+				Repository: &github.Repository{
+					FullName: github.Ptr(repo),
+					HTMLURL:  github.Ptr("https://github.com/" + repo),
+				},
+			})
+		}
+		s.NoError(json.NewEncoder(w).Encode(starred))
+	}))
+	s.T().Cleanup(server.Close)
+
+	BuildGitHubClient = func(token string) *github.Client {
+		client := s.originalBuildGitHubClient(token)
+		client.BaseURL, _ = url.Parse(server.URL + "/")
+		return client
+	}
+
+	return &requests
+}
+
+// repos returns the repositories of stars
+func repos(stars []Star) []string {
+	var names []string
+	for _, star := range stars {
+		names = append(names, star.Repo)
+	}
+	return names
+}
+
+func (s *GitHubTestSuite) TestGetAllStarsOffline() {
+	requests := s.serveStars("owner/new")
+
+	_, _, err := GetAllStars(FetchOptions{User: "rwjblue", Offline: true})
+	s.EqualError(err, "no cached stars for rwjblue, run without --offline to fetch them")
+
+	s.Require().NoError(WriteCachedStars("rwjblue", []Star{{Stargazer: "rwjblue", Repo: "owner/cached"}}))
+
+	stars, refresh, err := GetAllStars(FetchOptions{User: "rwjblue", Offline: true, MaxAge: time.Nanosecond})
+	s.Require().NoError(err)
+	s.Nil(refresh)
+	s.Equal([]string{"owner/cached"}, repos(stars))
+	s.Equal(0, *requests)
+
+	// a user without stars has been fetched all the same
+	s.Require().NoError(WriteCachedStars("nobody", nil))
+	stars, _, err = GetAllStars(FetchOptions{User: "nobody", Offline: true})
+	s.Require().NoError(err)
+	s.Empty(stars)
+
+	_, _, err = GetAllStars(FetchOptions{User: "rwjblue", Offline: true, Refresh: true})
+	s.EqualError(err, "refreshing and staying offline are mutually exclusive")
+}
+
+func (s *GitHubTestSuite) TestGetAllStarsRefresh() {
+	requests := s.serveStars("owner/new")
+	s.Require().NoError(WriteCachedStars("rwjblue", []Star{{Stargazer: "rwjblue", Repo: "owner/cached"}}))
+
+	stars, refresh, err := GetAllStars(FetchOptions{User: "rwjblue"})
+	s.Require().NoError(err)
+	s.Nil(refresh)
+	s.Equal([]string{"owner/cached"}, repos(stars))
+	s.Equal(0, *requests)
+
+	stars, refresh, err = GetAllStars(FetchOptions{User: "rwjblue", Refresh: true})
+	s.Require().NoError(err)
+	s.Nil(refresh)
+	s.Equal([]string{"owner/new"}, repos(stars))
+	s.Equal(1, *requests)
+
+	cached, err := GetCachedStars()
+	s.Require().NoError(err)
+	s.Equal([]string{"owner/new"}, repos(cached))
+}
+
+func (s *GitHubTestSuite) TestGetAllStarsRefreshesOutdatedInBackground() {
+	requests := s.serveStars("owner/kept", "owner/new")
+	s.Require().NoError(WriteCachedStars("rwjblue", []Star{
+		{Stargazer: "rwjblue", Repo: "owner/kept"},
+		{Stargazer: "rwjblue", Repo: "owner/removed"},
+	}))
+
+	// fresh enough
+	_, refresh, err := GetAllStars(FetchOptions{User: "rwjblue", MaxAge: time.Hour})
+	s.Require().NoError(err)
+	s.Nil(refresh)
+
+	stars, refresh, err := GetAllStars(FetchOptions{User: "rwjblue", MaxAge: time.Nanosecond})
+	s.Require().NoError(err)
+	s.Equal([]string{"owner/kept", "owner/removed"}, repos(stars), "the cached stars are returned straight away")
+	s.Require().NotNil(refresh)
+
+	var notice bytes.Buffer
+	s.Require().NoError(refresh.Wait(&notice))
+	s.Equal("Refreshed the stars of rwjblue: 1 new, 1 removed since the cached stars shown\n", notice.String())
+	s.Equal(1, *requests)
+
+	cached, err := GetCachedStars()
+	s.Require().NoError(err)
+	s.Equal([]string{"owner/kept", "owner/new"}, repos(cached))
+}
+
+func (s *GitHubTestSuite) TestGetAllStarsTimestampsEachUser() {
+	s.serveStars("owner/repo")
+	s.Require().NoError(WriteCachedStars("rwjblue", nil))
+
+	starsCache, err := getStarsCache()
+	s.Require().NoError(err)
+	s.Require().NoError(starsCache.Touch("rwjblue", time.Now().Add(-48*time.Hour)))
+
+	// fetching another user rewrites the file, which doesn't make rwjblue's
+	// stars any fresher
+	_, _, err = GetAllStars(FetchOptions{User: "octocat"})
+	s.Require().NoError(err)
+
+	_, refresh, err := GetAllStars(FetchOptions{User: "octocat", MaxAge: 24 * time.Hour})
+	s.Require().NoError(err)
+	s.Nil(refresh)
+
+	_, refresh, err = GetAllStars(FetchOptions{User: "rwjblue", MaxAge: 24 * time.Hour})
+	s.Require().NoError(err)
+	s.Require().NotNil(refresh)
+	s.NoError(refresh.Wait(io.Discard))
+
+	fetchedAt, err := StarsFetchedAt("rwjblue")
+	s.Require().NoError(err)
+	s.WithinDuration(time.Now(), fetchedAt, time.Minute)
+}
+
+// replay points BuildGitHubClient at the recorded cassette
+func (s *GitHubTestSuite) replay(cassette string) {
+	r, err := recorder.New(cassette, recorder.WithMode(recorder.ModeReplayOnly), recorder.WithSkipRequestLatency(true))
+	s.Require().NoError(err)
+	s.T().Cleanup(func() {
+		if err := r.Stop(); err != nil {
+			s.Error(err)
+		}
+	})
+
+	client := github.NewClient(r.GetDefaultClient())
+	BuildGitHubClient = func(string) *github.Client {
+		return client
+	}
+}
+
+func (s *GitHubTestSuite) TestGetAllStarsFetchesNewStarsWithVCR() {
+	s.replay("fixtures/get_all_stars")
+	stars, _, err := GetAllStars(FetchOptions{User: "rwjblue"})
+	s.Require().NoError(err)
+	s.Require().Len(stars, 90)
+
+	// newest first, with page 2 left out of the cassette: paging has to stop
+	// at tbtlr/gordon, the newest star cached above
+	s.replay("fixtures/get_new_stars")
+	refreshed, _, err := GetAllStars(FetchOptions{User: "rwjblue", Refresh: true})
+	s.Require().NoError(err)
+
+	s.Equal(stars, refreshed[:90])
+	s.Equal([]string{"junegunn/fzf", "charmbracelet/log"}, repos(refreshed[90:]))
+	s.Equal("2025-04-11", refreshed[91].StarredAt)
+
+	cached, err := GetCachedStars()
+	s.Require().NoError(err)
+	s.Equal(refreshed, cached)
+}
+
+func (s *GitHubTestSuite) TestGetAllStarsReconciles() {
+	s.serveStars("owner/new", "owner/kept")
+	s.Require().NoError(WriteCachedStars("rwjblue", []Star{
+		{Stargazer: "rwjblue", Repo: "owner/unstarred", StarredAt: "2023-01-01"},
+		{Stargazer: "rwjblue", Repo: "owner/kept", StarredAt: "2024-01-01"},
+	}))
+	s.Require().NoError(MarkStarsReconciled("rwjblue"))
+
+	// only the stars newer than owner/kept are added
+	stars, _, err := GetAllStars(FetchOptions{User: "rwjblue", Refresh: true})
+	s.Require().NoError(err)
+	s.Equal([]string{"owner/unstarred", "owner/kept", "owner/new"}, repos(stars))
+
+	starsCache, err := getStarsCache()
+	s.Require().NoError(err)
+	s.Require().NoError(starsCache.Touch(reconciledKey("rwjblue"), time.Now().Add(-ReconcileInterval-time.Hour)))
+
+	// every star is fetched again, dropping owner/unstarred
+	stars, _, err = GetAllStars(FetchOptions{User: "rwjblue", Refresh: true})
+	s.Require().NoError(err)
+	s.Equal([]string{"owner/new", "owner/kept"}, repos(stars))
+
+	reconciledAt, err := StarsReconciledAt("rwjblue")
+	s.Require().NoError(err)
+	s.WithinDuration(time.Now(), reconciledAt, time.Minute)
+}
+
+func (s *GitHubTestSuite) TestConditionalTransport() {
+	testCases := []struct {
+		name        string
+		header      string
+		value       string
+		conditional string
+	}{
+		{name: "ETag", header: "ETag", value: `"abc"`, conditional: "If-None-Match"},
+		{name: "Last-Modified", header: "Last-Modified", value: "Sat, 12 Apr 2025 14:09:20 GMT", conditional: "If-Modified-Since"},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			var conditionals []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				conditionals = append(conditionals, r.Header.Get(tc.conditional))
+				w.Header().Set("X-Ratelimit-Remaining", strconv.Itoa(60-len(conditionals)))
+				if r.Header.Get(tc.conditional) == tc.value {
+					w.WriteHeader(http.StatusNotModified)
+					return
+				}
+				w.Header().Set(tc.header, tc.value)
+				w.Header().Set("Link", `<https://api.github.com/next>; rel="next"`)
+				_, _ = w.Write([]byte("stored body"))
+			}))
+			defer server.Close()
+
+			dir := s.T().TempDir()
+			client := &http.Client{Transport: &ConditionalTransport{Dir: dir}}
+			get := func(authorization string) (*http.Response, string) {
+				req, err := http.NewRequest(http.MethodGet, server.URL+"/stars", nil)
+				s.Require().NoError(err)
+				req.Header.Set("Authorization", authorization)
+
+				resp, err := client.Do(req)
+				s.Require().NoError(err)
+				defer resp.Body.Close()
+
+				body, err := io.ReadAll(resp.Body)
+				s.Require().NoError(err)
+				return resp, string(body)
+			}
+
+			_, body := get("")
+			s.Equal("stored body", body)
+
+			resp, body := get("")
+			s.Equal(http.StatusOK, resp.StatusCode)
+			s.Equal("stored body", body, "the 304 is answered from disk")
+			s.Equal(`<https://api.github.com/next>; rel="next"`, resp.Header.Get("Link"))
+			s.Equal("58", resp.Header.Get("X-Ratelimit-Remaining"), "headers of the 304 are kept")
+
+			// other users get their own responses
+			_, body = get("Bearer token")
+			s.Equal("stored body", body)
+
+			s.Equal([]string{"", tc.value, ""}, conditionals)
+
+			entries, err := os.ReadDir(dir)
+			s.Require().NoError(err)
+			s.Len(entries, 2)
+		})
+	}
+}
+
+func (s *GitHubTestSuite) TestConditionalTransportTokens() {
+	// the same Last-Modified for everyone, so a response stored for one token
+	// would be confirmed for the other
+	var conditionals []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conditionals = append(conditionals, r.Header.Get("If-Modified-Since"))
+		if r.Header.Get("If-Modified-Since") != "" {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("Last-Modified", "Sat, 12 Apr 2025 14:09:20 GMT")
+		_, _ = w.Write([]byte("stars of " + r.Header.Get("Authorization")))
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: &ConditionalTransport{Dir: s.T().TempDir()}}
+	get := func(authorization string) string {
+		req, err := http.NewRequest(http.MethodGet, server.URL+"/user/starred", nil)
+		s.Require().NoError(err)
+		req.Header.Set("Authorization", authorization)
+
+		resp, err := client.Do(req)
+		s.Require().NoError(err)
+		defer func() { _ = resp.Body.Close() }()
+
+		body, err := io.ReadAll(resp.Body)
+		s.Require().NoError(err)
+		return string(body)
+	}
+
+	s.Equal("stars of Bearer alice", get("Bearer alice"))
+	s.Equal("stars of Bearer bob", get("Bearer bob"))
+	s.Equal("stars of Bearer alice", get("Bearer alice"))
+	s.Equal("stars of Bearer bob", get("Bearer bob"))
+	s.Equal("stars of ", get(""))
+
+	lastModified := "Sat, 12 Apr 2025 14:09:20 GMT"
+	s.Equal([]string{"", "", lastModified, lastModified, ""}, conditionals)
+}
+
+func (s *GitHubTestSuite) TestConditionalTransportPrunes() {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"`+r.URL.Path+`"`)
+		if r.Header.Get("If-None-Match") != "" {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		_, _ = w.Write([]byte(r.URL.Path))
+	}))
+	defer server.Close()
+
+	dir := s.T().TempDir()
+	client := &http.Client{Transport: &ConditionalTransport{Dir: dir, MaxAge: time.Hour}}
+	get := func(path string) {
+		resp, err := client.Get(server.URL + path)
+		s.Require().NoError(err)
+		_ = resp.Body.Close()
+	}
+	age := func(name string, age time.Duration) {
+		old := time.Now().Add(-age)
+		s.Require().NoError(os.Chtimes(filepath.Join(dir, name), old, old))
+	}
+
+	get("/unused")
+	get("/used")
+	entries, err := os.ReadDir(dir)
+	s.Require().NoError(err)
+	s.Require().Len(entries, 2)
+	for _, entry := range entries {
+		age(entry.Name(), 2*time.Hour)
+	}
+
+	// confirming a response marks it as used
+	get("/used")
+	get("/new")
+
+	entries, err = os.ReadDir(dir)
+	s.Require().NoError(err)
+	s.Len(entries, 2, "only the unused response is removed")
+}
+
+func (s *GitHubTestSuite) TestGetAllStarsNotModified() {
+	originalReconcileInterval := ReconcileInterval
+	ReconcileInterval = 0
+	defer func() { ReconcileInterval = originalReconcileInterval }()
+
+	var statuses []int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"stars"` {
+			statuses = append(statuses, http.StatusNotModified)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		statuses = append(statuses, http.StatusOK)
+		w.Header().Set("ETag", `"stars"`)
+		_, _ = w.Write([]byte(`[{"starred_at":"2024-01-01T00:00:00Z","repo":{"full_name":"owner/repo","html_url":"https://github.com/owner/repo"}}]`))
+	}))
+	s.T().Cleanup(server.Close)
+
+	BuildGitHubClient = func(token string) *github.Client {
+		client := s.originalBuildGitHubClient(token)
+		client.BaseURL, _ = url.Parse(server.URL + "/")
+		return client
+	}
+
+	stars, _, err := GetAllStars(FetchOptions{User: "rwjblue"})
+	s.Require().NoError(err)
+
+	refreshed, _, err := GetAllStars(FetchOptions{User: "rwjblue", Refresh: true})
+	s.Require().NoError(err)
+
+	s.Equal([]int{http.StatusOK, http.StatusNotModified}, statuses)
+	s.Equal([]string{"owner/repo"}, repos(refreshed))
+	s.Equal(stars, refreshed)
+}
+
+// starsPage is a page of stars served by serveStarPages
+type starsPage struct {
+	status int
+	header map[string]string
+	body   string
+}
+
+// serveStarPages points BuildGitHubClient at a server answering the requests
+// for stars with pages in turn, it returns the pages requested
+func (s *GitHubTestSuite) serveStarPages(pages ...starsPage) *[]string {
+	var requested []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = append(requested, r.URL.Query().Get("page"))
+		s.Require().LessOrEqual(len(requested), len(pages), "unexpected request")
+
+		page := pages[len(requested)-1]
+		for key, value := range page.header {
+			w.Header().Set(key, strings.ReplaceAll(value, "{server}", "http://"+r.Host))
+		}
+		w.WriteHeader(page.status)
+		_, _ = w.Write([]byte(page.body))
+	}))
+	s.T().Cleanup(server.Close)
+
+	BuildGitHubClient = func(token string) *github.Client {
+		client := s.originalBuildGitHubClient(token)
+		client.BaseURL, _ = url.Parse(server.URL + "/")
+		return client
+	}
+
+	return &requested
+}
+
+// starsBody is a page with a star of each repo
+func starsBody(repos ...string) string {
+	var starred []string
+	for _, repo := range repos {
+		starred = append(starred, `{"starred_at":"2024-01-01T00:00:00Z","repo":{"full_name":"`+repo+`","html_url":"https://github.com/`+repo+`"}}`)
+	}
+	return "[" + strings.Join(starred, ",") + "]"
+}
+
+// fakeSleep replaces sleep, it returns the waits
+func (s *GitHubTestSuite) fakeSleep() *[]time.Duration {
+	var waits []time.Duration
+	originalSleep := sleep
+	sleep = func(d time.Duration) { waits = append(waits, d) }
+	s.T().Cleanup(func() { sleep = originalSleep })
+	return &waits
+}
+
+func (s *GitHubTestSuite) TestGetAllStarsRetriesServerErrors() {
+	waits := s.fakeSleep()
+	s.serveStarPages(
+		starsPage{status: http.StatusBadGateway},
+		starsPage{status: http.StatusServiceUnavailable},
+		starsPage{status: http.StatusOK, body: starsBody("owner/repo")},
+	)
+
+	stars, _, err := GetAllStars(FetchOptions{User: "rwjblue"})
+	s.Require().NoError(err)
+	s.Equal([]string{"owner/repo"}, repos(stars))
+	s.Equal([]time.Duration{RetryBackoff, 2 * RetryBackoff}, *waits)
+}
+
+func (s *GitHubTestSuite) TestGetAllStarsGivesUpOnServerErrors() {
+	waits := s.fakeSleep()
+	pages := []starsPage{}
+	for range MaxRetries + 1 {
+		pages = append(pages, starsPage{status: http.StatusInternalServerError})
+	}
+	s.serveStarPages(pages...)
+
+	_, _, err := GetAllStars(FetchOptions{User: "rwjblue"})
+	s.ErrorContains(err, "500")
+	s.Len(*waits, MaxRetries)
+}
+
+func (s *GitHubTestSuite) TestGetAllStarsWaitsForRateLimits() {
+	reset := strconv.FormatInt(time.Now().Add(30*time.Second).Unix(), 10)
+
+	testCases := []struct {
+		name         string
+		limited      starsPage
+		maxWait      time.Duration
+		expectedWait time.Duration
+		expectedErr  string
+	}{
+		{
+			name: "Primary",
+			limited: starsPage{
+				status: http.StatusForbidden,
+				header: map[string]string{"X-Ratelimit-Remaining": "0", "X-Ratelimit-Reset": reset},
+				body:   `{"message":"API rate limit exceeded"}`,
+			},
+			maxWait:      time.Minute,
+			expectedWait: 30 * time.Second,
+		},
+		{
+			name: "Secondary",
+			limited: starsPage{
+				status: http.StatusForbidden,
+				header: map[string]string{"Retry-After": "5"},
+				body:   `{"message":"You have exceeded a secondary rate limit","documentation_url":"https://docs.github.com/rest/overview/rate-limits-for-the-rest-api#about-secondary-rate-limits"}`,
+			},
+			maxWait:      time.Minute,
+			expectedWait: 5 * time.Second,
+		},
+		{
+			name: "Secondary without Retry-After",
+			limited: starsPage{
+				status: http.StatusForbidden,
+				body:   `{"message":"You have exceeded a secondary rate limit","documentation_url":"https://docs.github.com/rest/overview/rate-limits-for-the-rest-api#about-secondary-rate-limits"}`,
+			},
+			maxWait:      time.Minute,
+			expectedWait: time.Minute,
+		},
+		{
+			name: "Longer than --max-wait",
+			limited: starsPage{
+				status: http.StatusForbidden,
+				header: map[string]string{"X-Ratelimit-Remaining": "0", "X-Ratelimit-Reset": reset},
+				body:   `{"message":"API rate limit exceeded"}`,
+			},
+			maxWait:     10 * time.Second,
+			expectedErr: "would exceed --max-wait 10s",
+		},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			s.Require().NoError(os.RemoveAll(filepath.Join(s.tempHomeDir, ".cache")))
+			waits := s.fakeSleep()
+			s.serveStarPages(tc.limited, starsPage{status: http.StatusOK, body: starsBody("owner/repo")})
+
+			stars, _, err := GetAllStars(FetchOptions{User: "rwjblue", MaxWait: tc.maxWait})
+			if tc.expectedErr != "" {
+				s.ErrorContains(err, tc.expectedErr)
+				s.Empty(*waits)
+				return
+			}
+
+			s.Require().NoError(err)
+			s.Equal([]string{"owner/repo"}, repos(stars))
+			s.Require().Len(*waits, 1)
+			s.InDelta(tc.expectedWait, (*waits)[0], float64(2*time.Second))
+		})
+	}
+}
+
+func (s *GitHubTestSuite) TestGetAllStarsResumesFromCheckpoint() {
+	originalMaxRetries := MaxRetries
+	MaxRetries = 0
+	defer func() { MaxRetries = originalMaxRetries }()
+
+	next := map[string]string{"Link": `<{server}/users/rwjblue/starred?direction=asc&page=2&sort=created>; rel="next"`}
+	requested := s.serveStarPages(
+		starsPage{status: http.StatusOK, header: next, body: starsBody("owner/first")},
+		starsPage{status: http.StatusBadGateway},
+		starsPage{status: http.StatusOK, body: starsBody("owner/second")},
+	)
+
+	_, _, err := GetAllStars(FetchOptions{User: "rwjblue"})
+	s.ErrorContains(err, "the next fetch resumes from page 2")
+
+	checkpoint, err := readStarsCheckpoint("rwjblue")
+	s.Require().NoError(err)
+	s.Require().NotNil(checkpoint)
+	s.Equal([]string{"owner/first"}, repos(checkpoint.Stars))
+
+	stars, _, err := GetAllStars(FetchOptions{User: "rwjblue"})
+	s.Require().NoError(err)
+	s.Equal([]string{"owner/first", "owner/second"}, repos(stars))
+	s.Equal([]string{"1", "2", "2"}, *requested)
+
+	checkpoint, err = readStarsCheckpoint("rwjblue")
+	s.Require().NoError(err)
+	s.Nil(checkpoint, "the checkpoint is cleared once every star is fetched")
+}
+
+func (s *GitHubTestSuite) TestMergeStars() {
+	cached := []Star{
+		{Repo: "owner/a", StarredAt: "2024-01-01"},
+		{Repo: "owner/b", StarredAt: "2024-02-01"},
+	}
+	newStars := []Star{
+		{Repo: "owner/a", StarredAt: "2024-04-01"},
+		{Repo: "owner/c", StarredAt: "2024-03-01"},
+	}
+
+	s.Equal([]Star{
+		{Repo: "owner/b", StarredAt: "2024-02-01"},
+		{Repo: "owner/c", StarredAt: "2024-03-01"},
+		{Repo: "owner/a", StarredAt: "2024-04-01"},
+	}, mergeStars(cached, newStars))
+
+	s.Equal(cached, mergeStars(cached, nil))
+}
+
+func (s *GitHubTestSuite) TestRefreshNotice() {
+	old := []Star{{Repo: "owner/a"}, {Repo: "owner/b"}}
+
+	testCases := []struct {
+		name     string
+		refresh  *Refresh
+		expected string
+	}{
+		{
+			name:     "No changes",
+			refresh:  &Refresh{user: "rwjblue", cached: old, stars: old},
+			expected: "Refreshed the stars of rwjblue, nothing changed",
+		},
+		{
+			name:     "Changes",
+			refresh:  &Refresh{user: "rwjblue", cached: old, stars: []Star{{Repo: "owner/b"}, {Repo: "owner/c"}, {Repo: "owner/d"}}},
+			expected: "Refreshed the stars of rwjblue: 2 new, 1 removed since the cached stars shown",
+		},
+		{
+			name:     "Failed",
+			refresh:  &Refresh{user: "rwjblue", cached: old, err: errors.New("rate limited")},
+			expected: "Warning: showed cached stars of rwjblue, refreshing them failed: rate limited",
+		},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			s.Equal(tc.expected, tc.refresh.notice())
+		})
+	}
+
+	var nilRefresh *Refresh
+	s.NoError(nilRefresh.Wait(nil))
+}
+
 func (s *GitHubTestSuite) TestPrintStarsNoStars() {
 	output, err := PrintStars([]Star{})
 	s.NoError(err)
@@ -145,12 +871,13 @@ func (s *GitHubTestSuite) TestGetAllStarsWithVCR() {
 	// NOTE: not using any auth here, so there is nothing to sanitize from the response (in this case)
 	client := github.NewClient(r.GetDefaultClient())
 
-	BuildGitHubClient = func() *github.Client {
+	BuildGitHubClient = func(string) *github.Client {
 		return client
 	}
 
-	stars, err := GetAllStars("rwjblue")
+	stars, refresh, err := GetAllStars(FetchOptions{User: "rwjblue"})
 	s.NoError(err, "Failed to get stars from GitHub API")
+	s.Nil(refresh)
 
 	cupaloy.SnapshotT(s.T(), stars)
 }
@@ -186,7 +913,7 @@ func (s *GitHubTestSuite) TestGetAllStarsFiltersToSpecifiedUser() {
 	err = cache.Write(stars)
 	s.NoError(err)
 
-	stars, err = GetAllStars("rwjblue")
+	stars, _, err = GetAllStars(FetchOptions{User: "rwjblue"})
 	s.NoError(err)
 	s.Equal(2, len(stars), "Should only return stars for rwjblue")
 
@@ -394,7 +1121,7 @@ func (s *GitHubTestSuite) TestFilterStarsByTerm() {
 
 	for _, tc := range testCases {
 		s.Run(tc.name, func() {
-			result := FilterStarsByTerm(stars, tc.searchTerm)
+			result := FilterStarsByTerm(stars, tc.searchTerm, item.SearchOptions{})
 
 			s.Equal(len(tc.expectedStars), len(result), "Number of stars with matches")
 
@@ -416,6 +1143,76 @@ func (s *GitHubTestSuite) TestFilterStarsByTerm() {
 	}
 }
 
+func (s *GitHubTestSuite) TestSortStars() {
+	stars := func() []Star {
+		return []Star{
+			{Repo: "owner/beta", URL: "https://github.com/owner/beta", StarredAt: "2023-02-01", Score: 10},
+			{Repo: "Owner/alpha", URL: "https://github.com/Owner/alpha", StarredAt: "2023-03-01", Score: 30},
+			{Repo: "owner/gamma", URL: "https://github.com/owner/gamma", StarredAt: "2023-01-01", Score: 20},
+		}
+	}
+	repos := func(stars []Star) []string {
+		var repos []string
+		for _, star := range stars {
+			repos = append(repos, star.Repo)
+		}
+		return repos
+	}
+
+	testCases := []struct {
+		sort     output.SortOptions
+		expected []string
+	}{
+		{output.SortOptions{By: output.SortRelevance}, []string{"Owner/alpha", "owner/gamma", "owner/beta"}},
+		{output.SortOptions{By: output.SortTitle}, []string{"Owner/alpha", "owner/beta", "owner/gamma"}},
+		{output.SortOptions{By: output.SortURL}, []string{"Owner/alpha", "owner/beta", "owner/gamma"}},
+		{output.SortOptions{By: output.SortDateAdded}, []string{"Owner/alpha", "owner/beta", "owner/gamma"}},
+		{output.SortOptions{By: output.SortDateAdded, Reverse: true}, []string{"owner/gamma", "owner/beta", "Owner/alpha"}},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.sort.By, func() {
+			sorted := stars()
+			s.NoError(SortStars(sorted, tc.sort))
+			s.Equal(tc.expected, repos(sorted))
+		})
+	}
+
+	s.EqualError(SortStars(stars(), output.SortOptions{By: output.SortFolder}), `invalid sort "folder", expected one of relevance, title, url, date-added`)
+}
+
+func (s *GitHubTestSuite) TestStarQueryFields() {
+	stars := []Star{
+		{Stargazer: "octocat", Repo: "cli/cli", Description: "GitHub's official command line tool", Language: "Go", StarredAt: "2023-05-01"},
+		{Stargazer: "octocat", Repo: "rails/rails", Description: "Ruby on Rails", Language: "Ruby", StarredAt: "2021-01-01"},
+		{Stargazer: "hubot", Repo: "junegunn/fzf", Description: "A command-line fuzzy finder", Language: "Go", StarredAt: "2024-02-01"},
+	}
+
+	testCases := []struct {
+		search   string
+		expected []string
+	}{
+		{search: "lang:go", expected: []string{"cli/cli", "junegunn/fzf"}},
+		{search: "lang:go -user:hubot", expected: []string{"cli/cli"}},
+		{search: "starred:<2023", expected: []string{"rails/rails"}},
+		{search: "starred:2024-02", expected: []string{"junegunn/fzf"}},
+		{search: "after:2022 description:command", expected: []string{"cli/cli", "junegunn/fzf"}},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.search, func() {
+			q, err := query.Parse(tc.search, StarQueryFields, time.Date(2024, 6, 1, 0, 0, 0, 0, time.Local))
+			s.NoError(err)
+
+			var repos []string
+			for _, star := range q.Filter(stars) {
+				repos = append(repos, star.Repo)
+			}
+			s.Equal(tc.expected, repos)
+		})
+	}
+}
+
 func TestGitHubTestSuite(t *testing.T) {
 	suite.Run(t, new(GitHubTestSuite))
 }
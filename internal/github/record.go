@@ -0,0 +1,30 @@
+package github
+
+// StarRecord is a star as written by the machine readable output formats.
+// The json names are part of tamjaweb's interface and must not change.
+type StarRecord struct {
+	Stargazer   string `json:"stargazer" doc:"User who starred the repository"`
+	Repo        string `json:"repo" doc:"Full name of the repository, owner/name"`
+	Description string `json:"description"`
+	URL         string `json:"url"`
+	StarredAt   string `json:"starred_at" doc:"Date the repository was starred, YYYY-MM-DD"`
+	Language    string `json:"language" doc:"Main language of the repository, empty when GitHub detected none"`
+	Score       int    `json:"score" doc:"The fzf score of the match when searching, higher is better, 0 otherwise"`
+}
+
+// StarRecords converts stars to StarRecords
+func StarRecords(stars []Star) []StarRecord {
+	records := make([]StarRecord, 0, len(stars))
+	for _, star := range stars {
+		records = append(records, StarRecord{
+			Stargazer:   star.Stargazer,
+			Repo:        star.Repo,
+			Description: star.Description,
+			URL:         star.URL,
+			StarredAt:   star.StarredAt,
+			Language:    star.Language,
+			Score:       star.Score,
+		})
+	}
+	return records
+}
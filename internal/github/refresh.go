@@ -0,0 +1,81 @@
+package github
+
+import (
+	"fmt"
+	"io"
+)
+
+// Refresh is a fetch of outdated stars running in the background, see
+// GetAllStars
+type Refresh struct {
+	user string
+	// cached are the stars that were returned while refreshing
+	cached []Star
+	done   chan struct{}
+	stars  []Star
+	err    error
+}
+
+func startRefresh(opts FetchOptions, cached []Star) *Refresh {
+	r := &Refresh{user: opts.User, cached: cached, done: make(chan struct{})}
+
+	go func() {
+		defer close(r.done)
+		r.stars, r.err = refreshStars(opts, cached)
+	}()
+
+	return r
+}
+
+// Wait waits for the refresh to finish and writes a notice of what changed
+// to w. The cached stars have been used by then, so a failed refresh is only
+// a warning. Wait does nothing for a nil Refresh.
+func (r *Refresh) Wait(w io.Writer) error {
+	if r == nil {
+		return nil
+	}
+
+	<-r.done
+
+	_, err := fmt.Fprintln(w, r.notice())
+	return err
+}
+
+func (r *Refresh) notice() string {
+	if r.err != nil {
+		return fmt.Sprintf("Warning: showed cached stars of %s, refreshing them failed: %v", r.user, r.err)
+	}
+
+	added, removed := diffStars(r.cached, r.stars)
+	if added == 0 && removed == 0 {
+		return fmt.Sprintf("Refreshed the stars of %s, nothing changed", r.user)
+	}
+	return fmt.Sprintf("Refreshed the stars of %s: %d new, %d removed since the cached stars shown", r.user, added, removed)
+}
+
+// diffStars counts the repositories starred and unstarred between old and
+// current
+func diffStars(old, current []Star) (int, int) {
+	oldRepos := make(map[string]bool, len(old))
+	for _, star := range old {
+		oldRepos[star.Repo] = true
+	}
+
+	added := 0
+	currentRepos := make(map[string]bool, len(current))
+	for _, star := range current {
+		currentRepos[star.Repo] = true
+		if !oldRepos[star.Repo] {
+			added++
+		}
+	}
+
+	removed := 0
+	for repo := range oldRepos {
+		if !currentRepos[repo] {
+			removed++
+		}
+	}
+
+	return added, removed
+}
@@ -0,0 +1,88 @@
+package github
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/go-github/v70/github"
+)
+
+// MaxRetries is how many times a request failing with a server error is
+// retried
+var MaxRetries = 4
+
+// RetryBackoff is the wait before retrying a server error, doubling with
+// each retry
+var RetryBackoff = time.Second
+
+// abuseRetryAfter is the wait for secondary rate limits without a
+// Retry-After header, GitHub asks for at least a minute
+const abuseRetryAfter = time.Minute
+
+// sleep waits for d, tests replace it so they don't wait
+var sleep = time.Sleep
+
+// retrier retries the requests of a fetch, waiting out rate limits for up to
+// maxWait in total and retrying server errors with exponential backoff
+type retrier struct {
+	maxWait time.Duration
+	waited  time.Duration
+}
+
+// do makes request until it succeeds or fails for good
+func (r *retrier) do(request func() (*github.Response, error)) error {
+	backoff := RetryBackoff
+	retries := 0
+
+	for {
+		resp, err := request()
+		if err == nil {
+			return nil
+		}
+
+		if wait, ok := rateLimitWait(err); ok {
+			if r.waited+wait > r.maxWait {
+				return fmt.Errorf("%w, waiting %s for it would exceed --max-wait %s", err, wait.Round(time.Second), r.maxWait)
+			}
+			githubLogger.Debug("Rate limited, waiting", "wait", wait, "error", err)
+			r.waited += wait
+			sleep(wait)
+			continue
+		}
+
+		if resp != nil && resp.StatusCode >= http.StatusInternalServerError && retries < MaxRetries {
+			githubLogger.Debug("Server error, retrying", "backoff", backoff, "error", err)
+			retries++
+			sleep(backoff)
+			backoff *= 2
+			continue
+		}
+
+		return err
+	}
+}
+
+// rateLimitWait returns how long to wait before retrying after err, when it
+// is a rate limit. Waits are at least a second, so a limit that outlasts its
+// reset still runs out of --max-wait.
+func rateLimitWait(err error) (time.Duration, bool) {
+	var wait time.Duration
+
+	var rateLimitErr *github.RateLimitError
+	var abuseErr *github.AbuseRateLimitError
+	switch {
+	case errors.As(err, &rateLimitErr):
+		wait = time.Until(rateLimitErr.Rate.Reset.Time)
+	case errors.As(err, &abuseErr):
+		wait = abuseRetryAfter
+		if abuseErr.RetryAfter != nil {
+			wait = *abuseErr.RetryAfter
+		}
+	default:
+		return 0, false
+	}
+
+	return max(wait, time.Second), true
+}
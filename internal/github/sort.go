@@ -0,0 +1,43 @@
+package github
+
+import (
+	"cmp"
+	"strings"
+
+	"github.com/malleatus/tamjaweb/internal/output"
+)
+
+// StarSorts lists the orders stars can be sorted in. Stars have no folder or
+// usage, title sorts by repository and date-added by when it was starred.
+var StarSorts = []string{
+	output.SortRelevance,
+	output.SortTitle,
+	output.SortURL,
+	output.SortDateAdded,
+}
+
+// SortStars orders stars in place, see output.Sort
+func SortStars(stars []Star, opts output.SortOptions) error {
+	if err := opts.Validate(StarSorts); err != nil {
+		return err
+	}
+
+	var compare func(a, b Star) int
+	var missing func(Star) bool
+	switch opts.By {
+	case output.SortRelevance:
+		compare = func(a, b Star) int { return cmp.Compare(b.Score, a.Score) }
+	case output.SortTitle:
+		compare = func(a, b Star) int { return output.CompareText(a.Repo, b.Repo) }
+	case output.SortURL:
+		compare = func(a, b Star) int { return strings.Compare(a.URL, b.URL) }
+	case output.SortDateAdded:
+		// StarredAt is YYYY-MM-DD, so it sorts as a string
+		compare = func(a, b Star) int { return strings.Compare(b.StarredAt, a.StarredAt) }
+		missing = func(star Star) bool { return star.StarredAt == "" }
+	}
+
+	output.Sort(stars, opts.Reverse, compare, missing)
+
+	return nil
+}
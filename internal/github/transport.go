@@ -0,0 +1,209 @@
+package github
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/malleatus/tamjaweb/internal/cache"
+)
+
+// StoredResponseMaxAge is how long a response is stored without being used,
+// e.g. after the token it was fetched with changed
+var StoredResponseMaxAge = 30 * 24 * time.Hour
+
+// ConditionalTransport makes GET requests conditional on the response stored
+// for the same request, sending its ETag as If-None-Match or its
+// Last-Modified as If-Modified-Since. GitHub answers those with 304 Not
+// Modified when nothing changed, which doesn't count against the rate limit,
+// and the stored response is returned in its place.
+type ConditionalTransport struct {
+	// Base makes the requests, http.DefaultTransport when nil
+	Base http.RoundTripper
+	// Dir stores a response per request
+	Dir string
+	// MaxAge removes stored responses that weren't used for longer whenever
+	// a response is stored, 0 keeps them
+	MaxAge time.Duration
+}
+
+// storedResponse is a response kept for conditional requests
+type storedResponse struct {
+	URL          string
+	ETag         string
+	LastModified string
+	StatusCode   int
+	Header       http.Header
+	Body         []byte
+}
+
+func (t *ConditionalTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	if req.Method != http.MethodGet {
+		return base.RoundTrip(req)
+	}
+
+	path := t.path(req)
+	stored := t.read(path)
+	if stored != nil {
+		// RoundTrippers mustn't modify the request
+		req = req.Clone(req.Context())
+		if stored.ETag != "" {
+			req.Header.Set("If-None-Match", stored.ETag)
+		}
+		if stored.LastModified != "" {
+			req.Header.Set("If-Modified-Since", stored.LastModified)
+		}
+	}
+
+	resp, err := base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusNotModified && stored != nil {
+		githubLogger.Debug("Not modified, using the stored response", "url", stored.URL)
+		_ = resp.Body.Close()
+		// it's still in use, so it mustn't be pruned
+		now := time.Now()
+		if err := os.Chtimes(path, now, now); err != nil {
+			githubLogger.Debug("Failed to mark stored response as used", "path", path, "error", err)
+		}
+		return stored.response(req, resp.Header), nil
+	}
+
+	etag, lastModified := resp.Header.Get("ETag"), resp.Header.Get("Last-Modified")
+	if resp.StatusCode != http.StatusOK || (etag == "" && lastModified == "") {
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	t.write(path, storedResponse{
+		URL:          req.URL.String(),
+		ETag:         etag,
+		LastModified: lastModified,
+		StatusCode:   resp.StatusCode,
+		Header:       resp.Header,
+		Body:         body,
+	})
+
+	return resp, nil
+}
+
+// path returns where the response to req is stored. Responses differ by what
+// is asked for and who asks, e.g. private repositories are only visible with
+// a token, and a 304 to If-Modified-Since doesn't tell them apart.
+func (t *ConditionalTransport) path(req *http.Request) string {
+	hash := sha256.New()
+	for _, part := range []string{req.URL.String(), req.Header.Get("Accept"), req.Header.Get("Authorization")} {
+		hash.Write([]byte(part))
+		hash.Write([]byte{0})
+	}
+	return filepath.Join(t.Dir, hex.EncodeToString(hash.Sum(nil))+".json")
+}
+
+// read returns the response stored at path, nil when there is none. A
+// response that can't be read is requested again.
+func (t *ConditionalTransport) read(path string) *storedResponse {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			githubLogger.Debug("Failed to read stored response", "path", path, "error", err)
+		}
+		return nil
+	}
+
+	var stored storedResponse
+	if err := json.Unmarshal(data, &stored); err != nil {
+		githubLogger.Debug("Failed to parse stored response", "path", path, "error", err)
+		return nil
+	}
+
+	return &stored
+}
+
+// write stores a response at path and prunes the unused ones. The response
+// has been received either way, so failing to store it is only logged.
+func (t *ConditionalTransport) write(path string, stored storedResponse) {
+	t.prune()
+
+	data, err := json.Marshal(stored)
+	if err == nil {
+		err = os.MkdirAll(t.Dir, 0755)
+	}
+	if err == nil {
+		err = cache.WriteFile(path, data)
+	}
+	if err != nil {
+		githubLogger.Debug("Failed to store response", "path", path, "error", err)
+	}
+}
+
+// prune removes the responses that weren't used for longer than MaxAge
+func (t *ConditionalTransport) prune() {
+	if t.MaxAge <= 0 {
+		return
+	}
+
+	entries, err := os.ReadDir(t.Dir)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			githubLogger.Debug("Failed to list stored responses", "dir", t.Dir, "error", err)
+		}
+		return
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || time.Since(info.ModTime()) <= t.MaxAge {
+			continue
+		}
+		path := filepath.Join(t.Dir, entry.Name())
+		if err := os.Remove(path); err != nil {
+			githubLogger.Debug("Failed to remove unused stored response", "path", path, "error", err)
+		}
+	}
+}
+
+// response rebuilds the stored response to req, with the headers of the 304
+// that confirmed it (rate limits, dates) taking precedence
+func (s *storedResponse) response(req *http.Request, header http.Header) *http.Response {
+	merged := s.Header.Clone()
+	for key, values := range header {
+		merged[key] = values
+	}
+	merged.Set("Content-Length", strconv.Itoa(len(s.Body)))
+
+	return &http.Response{
+		Status:        strconv.Itoa(s.StatusCode) + " " + http.StatusText(s.StatusCode),
+		StatusCode:    s.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        merged,
+		Body:          io.NopCloser(bytes.NewReader(s.Body)),
+		ContentLength: int64(len(s.Body)),
+		Request:       req,
+	}
+}
@@ -0,0 +1,192 @@
+package history
+
+import (
+	"bytes"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/malleatus/tamjaweb/internal/browser"
+	"github.com/malleatus/tamjaweb/internal/item"
+	"github.com/malleatus/tamjaweb/internal/output"
+	"github.com/olekukonko/tablewriter"
+)
+
+// Ways history search results can be ranked
+const (
+	// RankRelevance keeps fzf's match order
+	RankRelevance = "relevance"
+	// RankRecent puts the most recently visited pages first
+	RankRecent = "recent"
+	// RankFrecency weighs how often pages were visited by how recently
+	RankFrecency = "frecency"
+)
+
+// Ranks lists the valid values of Options.Rank
+var Ranks = []string{RankRelevance, RankRecent, RankFrecency}
+
+type Options struct {
+	Profile string
+	Strict  bool
+	// Since and Until are parsed with query.ParseTime
+	Since  string
+	Until  string
+	Rank   string
+	Search item.SearchOptions
+	Output output.Options
+}
+
+// MatchFields are the fields of a history search terms are matched against,
+// and the fields --in accepts for history
+var MatchFields = []string{item.FieldTitle, item.FieldURL}
+
+// Entry is a history entry together with the browser it was read from
+type Entry struct {
+	Browser string
+	browser.HistoryEntry
+}
+
+// Entries flattens history into a single list, ordered by browser name and
+// then in each browser's own order
+func Entries(history map[string][]browser.HistoryEntry) []Entry {
+	var entries []Entry
+	for _, browserName := range slices.Sorted(maps.Keys(history)) {
+		for _, entry := range history[browserName] {
+			entries = append(entries, Entry{Browser: browserName, HistoryEntry: entry})
+		}
+	}
+	return entries
+}
+
+// FilterHistoryByTerm filters history using fzf's filter functionality
+// Returns the matching entries, best match first
+func FilterHistoryByTerm(entries []Entry, term string, opts item.SearchOptions) []Entry {
+	if term == "" {
+		return entries
+	}
+
+	matches, err := item.Match(Items(entries), term, opts, MatchFields)
+	if err != nil {
+		log.Error("Failed to filter history", "error", err)
+		return nil
+	}
+
+	filteredEntries := make([]Entry, 0, len(matches))
+	for _, match := range matches {
+		filteredEntries = append(filteredEntries, entries[match.Index])
+	}
+
+	return filteredEntries
+}
+
+// Items adapts entries for searches across sources, keeping their order
+func Items(entries []Entry) []item.Item {
+	items := make([]item.Item, 0, len(entries))
+	for _, entry := range entries {
+		items = append(items, entry.Item())
+	}
+	return items
+}
+
+// Item adapts the history entry for searches across sources
+func (e Entry) Item() item.Item {
+	return item.Item{
+		Source:  item.SourceHistory,
+		Browser: e.Browser,
+		Profile: e.Profile,
+		Title:   e.Title,
+		URL:     e.URL,
+		Date:    e.LastVisit,
+		Details: []item.Detail{
+			{Label: "Visits", Value: strconv.Itoa(e.VisitCount)},
+		},
+	}
+}
+
+// Frecency scores entry by how often and how recently it was visited, like
+// Firefox's frecency but computed from the visit count and the most recent
+// visit only, as that is what every browser records
+func Frecency(entry browser.HistoryEntry, now time.Time) int {
+	age := now.Sub(entry.LastVisit)
+
+	var weight int
+	switch {
+	case age <= 4*24*time.Hour:
+		weight = 100
+	case age <= 14*24*time.Hour:
+		weight = 70
+	case age <= 31*24*time.Hour:
+		weight = 50
+	case age <= 90*24*time.Hour:
+		weight = 30
+	default:
+		weight = 10
+	}
+
+	return entry.VisitCount * weight
+}
+
+// RankHistory orders entries by rank across browsers, see Ranks
+func RankHistory(entries []Entry, rank string, now time.Time) error {
+	var compare func(a, b Entry) int
+
+	switch rank {
+	case "", RankRelevance:
+		return nil
+	case RankRecent:
+		compare = func(a, b Entry) int {
+			return b.LastVisit.Compare(a.LastVisit)
+		}
+	case RankFrecency:
+		compare = func(a, b Entry) int {
+			return Frecency(b.HistoryEntry, now) - Frecency(a.HistoryEntry, now)
+		}
+	default:
+		return fmt.Errorf("invalid rank %q, expected one of %s", rank, strings.Join(Ranks, ", "))
+	}
+
+	slices.SortStableFunc(entries, compare)
+
+	return nil
+}
+
+// prints the history in a tabular format, in the order of entries
+func PrintHistory(entries []Entry) (string, error) {
+	if len(entries) == 0 {
+		return "No history found", nil
+	}
+
+	var buf bytes.Buffer
+	table := tablewriter.NewWriter(&buf)
+
+	table.SetHeader([]string{"Browser", "Profile", "Title", "URL", "Visits", "Last Visit"})
+	table.SetAutoWrapText(true)
+	table.SetColumnAlignment([]int{
+		tablewriter.ALIGN_LEFT,
+		tablewriter.ALIGN_LEFT,
+		tablewriter.ALIGN_LEFT,
+		tablewriter.ALIGN_LEFT,
+		tablewriter.ALIGN_RIGHT,
+		tablewriter.ALIGN_LEFT,
+	})
+	table.SetColWidth(50)
+
+	for _, entry := range entries {
+		table.Append([]string{
+			entry.Browser,
+			entry.Profile,
+			entry.Title,
+			entry.URL,
+			strconv.Itoa(entry.VisitCount),
+			entry.LastVisit.Format("2006-01-02 15:04:05"),
+		})
+	}
+
+	table.Render()
+
+	return buf.String(), nil
+}
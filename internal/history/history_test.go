@@ -0,0 +1,164 @@
+package history
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bradleyjkemp/cupaloy/v2"
+	"github.com/malleatus/tamjaweb/internal/browser"
+	"github.com/malleatus/tamjaweb/internal/item"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var fixedNow = time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+
+func TestFilterHistoryByTerm(t *testing.T) {
+	history := map[string][]browser.HistoryEntry{
+		"TestBrowser1": {
+			{Title: "GitHub", URL: "https://github.com/"},
+			{Title: "Example", URL: "https://example.com/"},
+		},
+		"TestBrowser2": {
+			{Title: "Pull requests", URL: "https://github.com/pulls"},
+		},
+	}
+
+	filtered := FilterHistoryByTerm(Entries(history), "github", item.SearchOptions{})
+
+	assert.Equal(t, []Entry{
+		{Browser: "TestBrowser1", HistoryEntry: browser.HistoryEntry{Title: "GitHub", URL: "https://github.com/"}},
+		{Browser: "TestBrowser2", HistoryEntry: browser.HistoryEntry{Title: "Pull requests", URL: "https://github.com/pulls"}},
+	}, filtered)
+
+	assert.Equal(t, Entries(history), FilterHistoryByTerm(Entries(history), "", item.SearchOptions{}))
+}
+
+func TestFrecency(t *testing.T) {
+	testCases := []struct {
+		name     string
+		entry    browser.HistoryEntry
+		expected int
+	}{
+		{
+			name:     "Visited today",
+			entry:    browser.HistoryEntry{VisitCount: 2, LastVisit: fixedNow.Add(-time.Hour)},
+			expected: 200,
+		},
+		{
+			name:     "Visited last week",
+			entry:    browser.HistoryEntry{VisitCount: 2, LastVisit: fixedNow.AddDate(0, 0, -7)},
+			expected: 140,
+		},
+		{
+			name:     "Visited last month",
+			entry:    browser.HistoryEntry{VisitCount: 2, LastVisit: fixedNow.AddDate(0, 0, -20)},
+			expected: 100,
+		},
+		{
+			name:     "Visited this quarter",
+			entry:    browser.HistoryEntry{VisitCount: 2, LastVisit: fixedNow.AddDate(0, -2, 0)},
+			expected: 60,
+		},
+		{
+			name:     "Visited long ago",
+			entry:    browser.HistoryEntry{VisitCount: 2, LastVisit: fixedNow.AddDate(-1, 0, 0)},
+			expected: 20,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, Frecency(tc.entry, fixedNow))
+		})
+	}
+}
+
+func TestRankHistory(t *testing.T) {
+	entries := func() []Entry {
+		return Entries(map[string][]browser.HistoryEntry{
+			"Chrome": {
+				{Title: "Often, long ago", VisitCount: 50, LastVisit: fixedNow.AddDate(-1, 0, 0)},
+				{Title: "Sometimes, last week", VisitCount: 10, LastVisit: fixedNow.AddDate(0, 0, -7)},
+			},
+			"Firefox": {
+				{Title: "Once, today", VisitCount: 1, LastVisit: fixedNow.Add(-time.Hour)},
+			},
+		})
+	}
+
+	testCases := []struct {
+		rank     string
+		expected []string
+	}{
+		{
+			rank:     RankRelevance,
+			expected: []string{"Often, long ago", "Sometimes, last week", "Once, today"},
+		},
+		{
+			rank:     RankRecent,
+			expected: []string{"Once, today", "Sometimes, last week", "Often, long ago"},
+		},
+		{
+			rank:     RankFrecency,
+			expected: []string{"Sometimes, last week", "Often, long ago", "Once, today"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.rank, func(t *testing.T) {
+			ranked := entries()
+			err := RankHistory(ranked, tc.rank, fixedNow)
+			require.NoError(t, err)
+
+			var titles []string
+			for _, entry := range ranked {
+				titles = append(titles, entry.Title)
+			}
+			assert.Equal(t, tc.expected, titles)
+		})
+	}
+
+	err := RankHistory(entries(), "popular", fixedNow)
+	assert.EqualError(t, err, `invalid rank "popular", expected one of relevance, recent, frecency`)
+}
+
+func TestPrintHistory(t *testing.T) {
+	history := map[string][]browser.HistoryEntry{
+		"TestBrowser": {
+			{
+				Title:      "GitHub",
+				URL:        "https://github.com/",
+				LastVisit:  time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC),
+				VisitCount: 42,
+				Profile:    "Work",
+			},
+		},
+	}
+
+	output, err := PrintHistory(Entries(history))
+	require.NoError(t, err)
+
+	cupaloy.SnapshotT(t, output)
+}
+
+func TestPrintHistoryKeepsOrder(t *testing.T) {
+	entries := Entries(map[string][]browser.HistoryEntry{
+		"Chrome":  {{Title: "Yesterday", URL: "https://a.example.com", LastVisit: fixedNow.AddDate(0, 0, -1)}},
+		"Firefox": {{Title: "Today", URL: "https://b.example.com", LastVisit: fixedNow}},
+	})
+	require.NoError(t, RankHistory(entries, RankRecent, fixedNow))
+
+	output, err := PrintHistory(entries)
+	require.NoError(t, err)
+
+	assert.Less(t, strings.Index(output, "Firefox"), strings.Index(output, "Chrome"), "The most recent visit should be first across browsers")
+}
+
+func TestPrintHistoryEmpty(t *testing.T) {
+	output, err := PrintHistory(nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, "No history found", output)
+}
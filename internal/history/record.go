@@ -0,0 +1,33 @@
+package history
+
+import (
+	"github.com/malleatus/tamjaweb/internal/output"
+)
+
+// Record is a history entry as written by the machine readable output
+// formats. The json names are part of tamjaweb's interface and must not
+// change.
+type Record struct {
+	Browser    string      `json:"browser" doc:"Browser the page was visited in"`
+	Profile    string      `json:"profile" doc:"Name of the browser profile"`
+	Title      string      `json:"title"`
+	URL        string      `json:"url"`
+	VisitCount int         `json:"visit_count" doc:"Number of visits within --since and --until"`
+	LastVisit  output.Date `json:"last_visit" doc:"Most recent visit within --since and --until"`
+}
+
+// Records converts entries to Records, keeping their order
+func Records(entries []Entry) []Record {
+	records := make([]Record, 0, len(entries))
+	for _, entry := range entries {
+		records = append(records, Record{
+			Browser:    entry.Browser,
+			Profile:    entry.Profile,
+			Title:      entry.Title,
+			URL:        entry.URL,
+			VisitCount: entry.VisitCount,
+			LastVisit:  output.NewDate(entry.LastVisit),
+		})
+	}
+	return records
+}
@@ -0,0 +1,302 @@
+// Package item is the common shape of everything tamjaweb lists: bookmarks,
+// GitHub stars, tabs and history entries. Each source adapts its own type
+// into an Item, so searching, sorting and printing across sources is written
+// once.
+package item
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/malleatus/tamjaweb/internal/fzf"
+	"github.com/malleatus/tamjaweb/internal/output"
+	"github.com/malleatus/tamjaweb/internal/query"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/pflag"
+)
+
+// Sources items come from
+const (
+	SourceBookmarks = "bookmarks"
+	SourceStars     = "stars"
+	SourceTabs      = "tabs"
+	SourceHistory   = "history"
+)
+
+// Sources lists every source, in the order they are loaded
+var Sources = []string{SourceBookmarks, SourceStars, SourceTabs, SourceHistory}
+
+// DateNames describe what Item.Date is for each source
+var DateNames = map[string]string{
+	SourceBookmarks: "Added",
+	SourceStars:     "Starred",
+	SourceTabs:      "Last active",
+	SourceHistory:   "Last visit",
+}
+
+// Item is a bookmark, star, tab or history entry
+type Item struct {
+	Source string
+	// Browser the item was read from, GitHub for stars
+	Browser string
+	// Profile is the browser profile, or the stargazer for stars
+	Profile     string
+	Title       string
+	URL         string
+	Folder      string
+	Description string
+	// Date is when a bookmark was added, a repository starred, a tab last
+	// selected or a page last visited. It is zero when not recorded.
+	Date time.Time
+	// Details are extra fields of the source, shown when picking
+	Details []Detail
+	// Score is fzf's score for the search term, see fzf.Match. It is 0
+	// when not searching.
+	Score int
+}
+
+// Detail is a labelled value of an Item
+type Detail struct {
+	Label string
+	Value string
+}
+
+// Fields of an Item that search terms can be matched against, in the order
+// Match passes them to fzf
+const (
+	FieldTitle       = "title"
+	FieldURL         = "url"
+	FieldFolder      = "folder"
+	FieldDescription = "description"
+)
+
+// SearchFields lists every field, which is what --in accepts for searches
+// across sources
+var SearchFields = []string{FieldTitle, FieldURL, FieldFolder, FieldDescription}
+
+// MatchFields are the fields searches across sources are matched against
+var MatchFields = []string{FieldTitle, FieldURL, FieldDescription}
+
+// field returns the value of one of the Field constants
+func (i Item) field(name string) string {
+	switch name {
+	case FieldTitle:
+		return i.Title
+	case FieldURL:
+		return i.URL
+	case FieldFolder:
+		return i.Folder
+	case FieldDescription:
+		return i.Description
+	}
+	return ""
+}
+
+// SearchOptions are how the search commands match their search terms
+type SearchOptions struct {
+	// Exact matches words exactly instead of fuzzily
+	Exact bool
+	// CaseSensitive respects case, otherwise it is only respected for words
+	// with upper case letters
+	CaseSensitive bool
+	// In are the fields matched against, the command's defaults when empty
+	In []string
+}
+
+// AddSearchFlags adds the --exact, --case-sensitive and --in flags, fields
+// lists the values --in accepts and defaults the fields matched without it
+func AddSearchFlags(flags *pflag.FlagSet, opts *SearchOptions, fields []string, defaults []string) {
+	flags.BoolVar(&opts.Exact, "exact", false, "Match words exactly instead of fuzzily, a leading ' makes a word fuzzy")
+
+	flags.BoolVar(&opts.CaseSensitive, "case-sensitive", false, "Respect case, by default it is only respected for words with upper case letters")
+
+	flags.StringSliceVar(&opts.In, "in", nil, "Fields to match the search term against: "+strings.Join(fields, ", ")+" (default "+strings.Join(defaults, ",")+")")
+}
+
+// Validate checks that --in only names fields
+func (o SearchOptions) Validate(fields []string) error {
+	for _, name := range o.In {
+		if !slices.Contains(fields, name) {
+			return fmt.Errorf("invalid field %q for --in, expected one of %s", name, strings.Join(fields, ", "))
+		}
+	}
+	return nil
+}
+
+// FilterOptions returns fzf's options for matching the given fields
+func (o SearchOptions) FilterOptions(fields []string) fzf.FilterOptions {
+	opts := fzf.DefaultFilterOptions()
+	opts.Exact = o.Exact
+	if o.CaseSensitive {
+		opts.Case = fzf.CaseRespect
+	}
+
+	// Match puts the index first, followed by SearchFields
+	var nth []string
+	for _, name := range fields {
+		if i := slices.Index(SearchFields, name); i >= 0 {
+			nth = append(nth, strconv.Itoa(i+2))
+		}
+	}
+	opts.Nth = strings.Join(nth, ",")
+
+	return opts
+}
+
+// Match fuzzy matches term against fields of items with fzf and returns the
+// matches, best first. The fields are opts.In, or defaults without it. Every
+// item matches an empty term, in order.
+func Match(items []Item, term string, opts SearchOptions, defaults []string) ([]fzf.Match, error) {
+	fields := opts.In
+	if len(fields) == 0 {
+		fields = defaults
+	}
+
+	inputs := make([]string, len(items))
+	for i, item := range items {
+		values := []string{strconv.Itoa(i)}
+		for _, name := range SearchFields {
+			values = append(values, fieldCleaner.Replace(item.field(name)))
+		}
+		inputs[i] = strings.Join(values, "\t")
+	}
+
+	return fzf.FilterStrings(inputs, term, opts.FilterOptions(fields))
+}
+
+// fieldCleaner keeps each field to a single field of a single line
+var fieldCleaner = strings.NewReplacer("\t", " ", "\n", " ")
+
+// FilterByTerm returns the items matching term with their scores, best
+// match first, see Match
+func FilterByTerm(items []Item, term string, opts SearchOptions, defaults []string) ([]Item, error) {
+	if term == "" {
+		return items, nil
+	}
+
+	matches, err := Match(items, term, opts, defaults)
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := make([]Item, 0, len(matches))
+	for _, match := range matches {
+		item := items[match.Index]
+		item.Score = match.Score
+		filtered = append(filtered, item)
+	}
+
+	return filtered, nil
+}
+
+// QueryFields are the fields searches across sources can filter on, e.g.
+// source:stars host:github.com date:2024
+var QueryFields = query.Fields[Item]{
+	"source":      query.Equals(func(i Item) string { return i.Source }),
+	"title":       query.Contains(func(i Item) string { return i.Title }),
+	"url":         query.Contains(func(i Item) string { return i.URL }),
+	"host":        query.Host(func(i Item) string { return i.URL }),
+	"folder":      query.Contains(func(i Item) string { return i.Folder }),
+	"description": query.Contains(func(i Item) string { return i.Description }),
+	"browser":     query.Equals(func(i Item) string { return i.Browser }),
+	"profile":     query.Equals(func(i Item) string { return i.Profile }),
+	"date":        query.Date(func(i Item) time.Time { return i.Date }),
+	"before":      query.Before(func(i Item) time.Time { return i.Date }),
+	"after":       query.After(func(i Item) time.Time { return i.Date }),
+}
+
+// Sorts lists the orders items can be sorted in
+var Sorts = []string{
+	output.SortRelevance,
+	output.SortTitle,
+	output.SortURL,
+	output.SortDate,
+	output.SortSource,
+	output.SortBrowser,
+}
+
+// SortItems orders items in place, see output.Sort
+func SortItems(items []Item, opts output.SortOptions) error {
+	if err := opts.Validate(Sorts); err != nil {
+		return err
+	}
+
+	var compare func(a, b Item) int
+	var missing func(Item) bool
+	switch opts.By {
+	case output.SortRelevance:
+		compare = func(a, b Item) int { return cmp.Compare(b.Score, a.Score) }
+	case output.SortTitle:
+		compare = func(a, b Item) int {
+			return cmp.Or(output.CompareText(a.Title, b.Title), strings.Compare(a.URL, b.URL))
+		}
+	case output.SortURL:
+		compare = func(a, b Item) int { return strings.Compare(a.URL, b.URL) }
+	case output.SortDate:
+		compare = func(a, b Item) int { return b.Date.Compare(a.Date) }
+		missing = func(i Item) bool { return i.Date.IsZero() }
+	case output.SortSource:
+		// in the order sources are loaded rather than by name
+		compare = func(a, b Item) int {
+			return cmp.Compare(slices.Index(Sources, a.Source), slices.Index(Sources, b.Source))
+		}
+	case output.SortBrowser:
+		compare = func(a, b Item) int {
+			return cmp.Or(strings.Compare(a.Browser, b.Browser), strings.Compare(a.Profile, b.Profile))
+		}
+	}
+
+	output.Sort(items, opts.Reverse, compare, missing)
+
+	return nil
+}
+
+// PrintItems prints items in a tabular format
+func PrintItems(items []Item) (string, error) {
+	if len(items) == 0 {
+		return "No results found", nil
+	}
+
+	var buf bytes.Buffer
+	table := tablewriter.NewWriter(&buf)
+
+	table.SetHeader([]string{"Source", "Browser", "Profile", "Title", "URL", "Date"})
+	table.SetAutoWrapText(true)
+	table.SetColumnAlignment([]int{
+		tablewriter.ALIGN_LEFT,
+		tablewriter.ALIGN_LEFT,
+		tablewriter.ALIGN_LEFT,
+		tablewriter.ALIGN_LEFT,
+		tablewriter.ALIGN_LEFT,
+		tablewriter.ALIGN_LEFT,
+	})
+	table.SetColWidth(50)
+
+	for _, item := range items {
+		table.Append([]string{
+			item.Source,
+			item.Browser,
+			item.Profile,
+			item.Title,
+			item.URL,
+			formatDate(item.Date),
+		})
+	}
+
+	table.Render()
+
+	return buf.String(), nil
+}
+
+// formatDate formats t for display, leaving dates that were never set empty
+func formatDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format("2006-01-02 15:04:05")
+}
@@ -0,0 +1,157 @@
+package item
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bradleyjkemp/cupaloy/v2"
+	"github.com/malleatus/tamjaweb/internal/output"
+	"github.com/malleatus/tamjaweb/internal/query"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var fixedNow = time.Date(2024, 6, 15, 12, 0, 0, 0, time.Local)
+
+func testItems() []Item {
+	return []Item{
+		{Source: SourceBookmarks, Browser: "Chrome", Profile: "Work", Title: "Pull requests", URL: "https://github.com/pulls", Folder: "Bookmark Bar/Work", Date: time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)},
+		{Source: SourceStars, Browser: "GitHub", Profile: "octocat", Title: "junegunn/fzf", URL: "https://github.com/junegunn/fzf", Description: "A command-line fuzzy finder", Date: time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC)},
+		{Source: SourceTabs, Browser: "Firefox", Title: "Fuzzy finding in Go", URL: "https://example.com/fuzzy"},
+		{Source: SourceHistory, Browser: "Chrome", Profile: "Default", Title: "GitHub", URL: "https://github.com", Date: time.Date(2024, 6, 14, 9, 0, 0, 0, time.UTC)},
+	}
+}
+
+func titles(items []Item) []string {
+	var titles []string
+	for _, item := range items {
+		titles = append(titles, item.Title)
+	}
+	return titles
+}
+
+func TestFilterByTerm(t *testing.T) {
+	testCases := []struct {
+		name     string
+		term     string
+		opts     SearchOptions
+		expected []string
+	}{
+		{
+			name:     "Empty term keeps everything in order",
+			term:     "",
+			expected: []string{"Pull requests", "junegunn/fzf", "Fuzzy finding in Go", "GitHub"},
+		},
+		{
+			name:     "Matches across sources",
+			term:     "fuzzy",
+			expected: []string{"Fuzzy finding in Go", "junegunn/fzf"},
+		},
+		{
+			name:     "Only the given fields",
+			term:     "fuzzy",
+			opts:     SearchOptions{In: []string{FieldTitle, FieldURL}},
+			expected: []string{"Fuzzy finding in Go"},
+		},
+		{
+			name:     "Folder",
+			term:     "'work",
+			opts:     SearchOptions{In: []string{FieldFolder}},
+			expected: []string{"Pull requests"},
+		},
+		{
+			name:     "Exact",
+			term:     "fzy",
+			opts:     SearchOptions{Exact: true},
+			expected: nil,
+		},
+		{
+			name:     "Case sensitive",
+			term:     "github",
+			opts:     SearchOptions{CaseSensitive: true, In: []string{FieldTitle}},
+			expected: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			filtered, err := FilterByTerm(testItems(), tc.term, tc.opts, MatchFields)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, titles(filtered))
+
+			for _, item := range filtered {
+				assert.Equal(t, tc.term != "", item.Score > 0, "%s should only be scored when searching", item.Title)
+			}
+		})
+	}
+}
+
+func TestSearchOptionsValidate(t *testing.T) {
+	opts := SearchOptions{In: []string{FieldTitle, FieldFolder}}
+	assert.NoError(t, opts.Validate(SearchFields))
+
+	err := opts.Validate([]string{FieldTitle, FieldURL})
+	assert.EqualError(t, err, `invalid field "folder" for --in, expected one of title, url`)
+}
+
+func TestSortItems(t *testing.T) {
+	testCases := []struct {
+		sort     output.SortOptions
+		expected []string
+	}{
+		{sort: output.SortOptions{By: output.SortTitle}, expected: []string{"Fuzzy finding in Go", "GitHub", "junegunn/fzf", "Pull requests"}},
+		{sort: output.SortOptions{By: output.SortDate}, expected: []string{"GitHub", "Pull requests", "junegunn/fzf", "Fuzzy finding in Go"}},
+		{sort: output.SortOptions{By: output.SortDate, Reverse: true}, expected: []string{"junegunn/fzf", "Pull requests", "GitHub", "Fuzzy finding in Go"}},
+		{sort: output.SortOptions{By: output.SortSource, Reverse: true}, expected: []string{"GitHub", "Fuzzy finding in Go", "junegunn/fzf", "Pull requests"}},
+		{sort: output.SortOptions{By: output.SortBrowser}, expected: []string{"GitHub", "Pull requests", "Fuzzy finding in Go", "junegunn/fzf"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.sort.By, func(t *testing.T) {
+			items := testItems()
+			require.NoError(t, SortItems(items, tc.sort))
+			assert.Equal(t, tc.expected, titles(items))
+		})
+	}
+
+	err := SortItems(testItems(), output.SortOptions{By: output.SortLastUsed})
+	assert.EqualError(t, err, `invalid sort "last-used", expected one of relevance, title, url, date, source, browser`)
+}
+
+func TestQueryFields(t *testing.T) {
+	testCases := []struct {
+		search   string
+		expected []string
+	}{
+		{search: "source:stars", expected: []string{"junegunn/fzf"}},
+		{search: "-source:bookmarks host:github.com", expected: []string{"junegunn/fzf", "GitHub"}},
+		{search: "browser:chrome profile:work", expected: []string{"Pull requests"}},
+		{search: "description:finder", expected: []string{"junegunn/fzf"}},
+		{search: "folder:work", expected: []string{"Pull requests"}},
+		{search: "date:2024", expected: []string{"Pull requests", "GitHub"}},
+		{search: "after:7d", expected: []string{"GitHub"}},
+		{search: "before:2024", expected: []string{"junegunn/fzf"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.search, func(t *testing.T) {
+			q, err := query.Parse(tc.search, QueryFields, fixedNow)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, titles(q.Filter(testItems())))
+		})
+	}
+}
+
+func TestPrintItems(t *testing.T) {
+	result, err := PrintItems(testItems())
+	require.NoError(t, err)
+	cupaloy.SnapshotT(t, result)
+
+	result, err = PrintItems(nil)
+	require.NoError(t, err)
+	assert.Equal(t, "No results found", result)
+}
+
+func TestRecords(t *testing.T) {
+	cupaloy.SnapshotT(t, Records(testItems()))
+}
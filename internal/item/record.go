@@ -0,0 +1,36 @@
+package item
+
+import "github.com/malleatus/tamjaweb/internal/output"
+
+// Record is an item as written by the machine readable output formats. The
+// json names are part of tamjaweb's interface and must not change.
+type Record struct {
+	Source      string      `json:"source" doc:"Where the item came from: bookmarks, stars, tabs or history"`
+	Browser     string      `json:"browser" doc:"Browser the item was read from, GitHub for stars"`
+	Profile     string      `json:"profile" doc:"Name of the browser profile, or the stargazer for stars"`
+	Title       string      `json:"title"`
+	URL         string      `json:"url"`
+	Folder      string      `json:"folder" doc:"Folder path of bookmarks, empty for other sources"`
+	Description string      `json:"description" doc:"Description of starred repositories, empty for other sources"`
+	Date        output.Date `json:"date" doc:"When a bookmark was added, a repository starred, a tab last selected or a page last visited, null when not recorded"`
+	Score       int         `json:"score" doc:"The fzf score of the match when searching, higher is better, 0 otherwise"`
+}
+
+// Records converts items to Records
+func Records(items []Item) []Record {
+	records := make([]Record, 0, len(items))
+	for _, item := range items {
+		records = append(records, Record{
+			Source:      item.Source,
+			Browser:     item.Browser,
+			Profile:     item.Profile,
+			Title:       item.Title,
+			URL:         item.URL,
+			Folder:      item.Folder,
+			Description: item.Description,
+			Date:        output.NewDate(item.Date),
+			Score:       item.Score,
+		})
+	}
+	return records
+}
@@ -0,0 +1,361 @@
+package output
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+// Formats lists can be written in
+const (
+	// Table is the human readable default, rendered by each list itself
+	Table = "table"
+	// JSON writes the records as a single array
+	JSON = "json"
+	// NDJSON writes one JSON object per line
+	NDJSON = "ndjson"
+	CSV    = "csv"
+	TSV    = "tsv"
+	// Markdown writes a GitHub flavoured markdown table
+	Markdown = "markdown"
+	// Template executes Options.Template for each record
+	Template = "template"
+)
+
+// Formats lists the valid values of Options.Format
+var Formats = []string{Table, JSON, NDJSON, CSV, TSV, Markdown, Template}
+
+// Options selects how a list is written
+type Options struct {
+	Format string
+	// Template is a text/template executed for each record, see Render
+	Template string
+}
+
+// AddFlags adds the --output and --template flags to a list command
+func AddFlags(flags *pflag.FlagSet, opts *Options) {
+	flags.StringVarP(&opts.Format, "output", "o", Table, "Output format: "+strings.Join(Formats, ", ")+" (see 'tamjaweb schema' for the field names)")
+
+	flags.StringVar(&opts.Template, "template", "", "Go template executed for each result, e.g. '{{.Title}} {{.URL}}' (implies --output template)")
+}
+
+// Validate checks the options before any work is done, so that a typo fails
+// fast instead of after loading everything
+func (o *Options) Validate() error {
+	if o.Template != "" && (o.Format == "" || o.Format == Table) {
+		o.Format = Template
+	}
+
+	if !slices.Contains(Formats, o.Format) {
+		return fmt.Errorf("invalid output format %q, expected one of %s", o.Format, strings.Join(Formats, ", "))
+	}
+
+	if o.Format == Template {
+		if o.Template == "" {
+			return fmt.Errorf("--output %s requires --template", Template)
+		}
+		if _, err := parseTemplate(o.Template); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Render writes records in the format selected by opts. records is a slice
+// of structs whose json tags name their fields, the same names are used as
+// the header of csv, tsv and markdown output. table renders the human
+// readable format and is only called for Table.
+func Render[T any](w io.Writer, opts Options, records []T, table func() (string, error)) error {
+	if err := opts.Validate(); err != nil {
+		return err
+	}
+
+	switch opts.Format {
+	case Table:
+		formatted, err := table()
+		if err != nil {
+			return err
+		}
+		_, err = io.WriteString(w, formatted)
+		return err
+
+	case JSON:
+		if records == nil {
+			records = []T{}
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetEscapeHTML(false)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(records)
+
+	case NDJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetEscapeHTML(false)
+		for _, record := range records {
+			if err := encoder.Encode(record); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case CSV, TSV:
+		return writeDelimited(w, opts.Format, records)
+
+	case Markdown:
+		return writeMarkdown(w, records)
+
+	case Template:
+		return writeTemplate(w, opts.Template, records)
+	}
+
+	return nil
+}
+
+// field is a json tagged field of a record
+type field struct {
+	name  string
+	index int
+	doc   string
+}
+
+// fields returns the json tagged fields of the record type t
+func fields(t reflect.Type) []field {
+	var fields []field
+	for i := range t.NumField() {
+		structField := t.Field(i)
+		name, _, _ := strings.Cut(structField.Tag.Get("json"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		fields = append(fields, field{name: name, index: i, doc: structField.Tag.Get("doc")})
+	}
+	return fields
+}
+
+// header returns the field names of records of type T
+func header[T any]() []string {
+	var names []string
+	for _, field := range fields(reflect.TypeFor[T]()) {
+		names = append(names, field.name)
+	}
+	return names
+}
+
+// row formats the fields of record as strings
+func row[T any](record T) []string {
+	value := reflect.ValueOf(record)
+
+	var cells []string
+	for _, field := range fields(value.Type()) {
+		cells = append(cells, formatValue(value.Field(field.index)))
+	}
+	return cells
+}
+
+// formatValue formats a field for the text based formats
+func formatValue(value reflect.Value) string {
+	switch v := value.Interface().(type) {
+	case Date:
+		return v.String()
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case int:
+		return strconv.Itoa(v)
+	case map[string]string:
+		pairs := make([]string, 0, len(v))
+		for _, key := range slices.Sorted(maps.Keys(v)) {
+			pairs = append(pairs, key+"="+v[key])
+		}
+		return strings.Join(pairs, " ")
+	case []string:
+		return strings.Join(v, ",")
+	}
+
+	return fmt.Sprint(value.Interface())
+}
+
+func writeDelimited[T any](w io.Writer, format string, records []T) error {
+	if format == CSV {
+		writer := csv.NewWriter(w)
+		if err := writer.Write(header[T]()); err != nil {
+			return err
+		}
+		for _, record := range records {
+			if err := writer.Write(row(record)); err != nil {
+				return err
+			}
+		}
+		writer.Flush()
+		return writer.Error()
+	}
+
+	// tsv has no quoting, so the separators can't appear in values
+	clean := strings.NewReplacer("\t", " ", "\r\n", " ", "\n", " ")
+
+	out := bufio.NewWriter(w)
+	out.WriteString(strings.Join(header[T](), "\t") + "\n")
+	for _, record := range records {
+		cells := row(record)
+		for i := range cells {
+			cells[i] = clean.Replace(cells[i])
+		}
+		out.WriteString(strings.Join(cells, "\t") + "\n")
+	}
+	return out.Flush()
+}
+
+func writeMarkdown[T any](w io.Writer, records []T) error {
+	escape := strings.NewReplacer("|", `\|`, "\r\n", " ", "\n", " ")
+
+	out := bufio.NewWriter(w)
+	writeRow := func(cells []string) {
+		out.WriteString("|")
+		for _, cell := range cells {
+			out.WriteString(" " + escape.Replace(cell) + " |")
+		}
+		out.WriteString("\n")
+	}
+
+	names := header[T]()
+	writeRow(names)
+	out.WriteString(strings.Repeat("| --- ", len(names)) + "|\n")
+	for _, record := range records {
+		writeRow(row(record))
+	}
+
+	return out.Flush()
+}
+
+// templateFuncs are available to --template on top of the text/template
+// built ins
+var templateFuncs = template.FuncMap{
+	"json": func(v any) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+	"join": func(sep string, values []string) string {
+		return strings.Join(values, sep)
+	},
+}
+
+func parseTemplate(text string) (*template.Template, error) {
+	tmpl, err := template.New("output").Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// writeTemplate executes the template for each record, followed by a new
+// line. Templates see the records themselves, so fields are referred to by
+// their Go names (e.g. {{.Title}}).
+func writeTemplate[T any](w io.Writer, text string, records []T) error {
+	tmpl, err := parseTemplate(text)
+	if err != nil {
+		return err
+	}
+
+	out := bufio.NewWriter(w)
+	for _, record := range records {
+		if err := tmpl.Execute(out, record); err != nil {
+			return err
+		}
+		if !strings.HasSuffix(text, "\n") {
+			out.WriteString("\n")
+		}
+	}
+
+	return out.Flush()
+}
+
+// Schema returns a JSON Schema describing the output of a list of records of
+// type T, using the doc tags of its fields as descriptions
+func Schema[T any](title string) map[string]any {
+	properties := map[string]any{}
+	var required []string
+
+	t := reflect.TypeFor[T]()
+	for _, field := range fields(t) {
+		property := schemaType(t.Field(field.index).Type)
+		if field.doc != "" {
+			property["description"] = field.doc
+		}
+		properties[field.name] = property
+		required = append(required, field.name)
+	}
+
+	return map[string]any{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"title":   title,
+		"type":    "array",
+		"items": map[string]any{
+			"type":                 "object",
+			"properties":           properties,
+			"required":             required,
+			"additionalProperties": false,
+		},
+	}
+}
+
+func schemaType(t reflect.Type) map[string]any {
+	var property map[string]any
+	switch {
+	case t == reflect.TypeFor[Date]():
+		property = map[string]any{"type": []string{"string", "null"}, "format": "date-time"}
+	case t.Kind() == reflect.String:
+		property = map[string]any{"type": "string"}
+	case t.Kind() == reflect.Bool:
+		property = map[string]any{"type": "boolean"}
+	case t.Kind() == reflect.Int:
+		property = map[string]any{"type": "integer"}
+	// nil maps and slices are written as null
+	case t.Kind() == reflect.Map:
+		property = map[string]any{"type": []string{"object", "null"}, "additionalProperties": schemaType(t.Elem())}
+	case t.Kind() == reflect.Slice:
+		property = map[string]any{"type": []string{"array", "null"}, "items": schemaType(t.Elem())}
+	default:
+		property = map[string]any{}
+	}
+
+	return property
+}
+
+// Date is a date field of a record. It embeds time.Time, so templates can
+// format it (e.g. {{.DateAdded.Format "2006-01-02"}}), and is written as
+// null in JSON, or empty elsewhere, when it was never set.
+type Date struct {
+	time.Time
+}
+
+// NewDate wraps t for a record
+func NewDate(t time.Time) Date {
+	return Date{Time: t}
+}
+
+func (d Date) String() string {
+	if d.IsZero() {
+		return ""
+	}
+	return d.Format(time.RFC3339)
+}
+
+func (d Date) MarshalJSON() ([]byte, error) {
+	if d.IsZero() {
+		return []byte("null"), nil
+	}
+	return d.Time.MarshalJSON()
+}
@@ -0,0 +1,196 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bradleyjkemp/cupaloy/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testRecord struct {
+	Title     string            `json:"title" doc:"Title of the page"`
+	URL       string            `json:"url"`
+	Visits    int               `json:"visits"`
+	Pinned    bool              `json:"pinned"`
+	DateAdded Date              `json:"date_added"`
+	Tags      []string          `json:"tags"`
+	MetaInfo  map[string]string `json:"meta_info"`
+	internal  string
+}
+
+func testRecords() []testRecord {
+	return []testRecord{
+		{
+			Title:     "Example, \"quoted\" | piped",
+			URL:       "https://example.com/?a=1&b=2",
+			Visits:    3,
+			Pinned:    true,
+			DateAdded: NewDate(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)),
+			Tags:      []string{"a", "b"},
+			MetaInfo:  map[string]string{"z": "1", "a": "2"},
+		},
+		{
+			Title: "Multi\tline\nTitle",
+			URL:   "https://example.org",
+		},
+	}
+}
+
+func TestRender(t *testing.T) {
+	for _, format := range []string{JSON, NDJSON, CSV, TSV, Markdown} {
+		t.Run(format, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := Render(&buf, Options{Format: format}, testRecords(), nil)
+			require.NoError(t, err)
+
+			cupaloy.SnapshotT(t, buf.String())
+		})
+	}
+}
+
+func TestRenderTable(t *testing.T) {
+	var buf bytes.Buffer
+	err := Render(&buf, Options{Format: Table}, testRecords(), func() (string, error) {
+		return "a table", nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "a table", buf.String())
+
+	err = Render(&buf, Options{Format: Table}, testRecords(), func() (string, error) {
+		return "", errors.New("broken table")
+	})
+	assert.EqualError(t, err, "broken table")
+}
+
+func TestRenderEmpty(t *testing.T) {
+	testCases := []struct {
+		format   string
+		expected string
+	}{
+		{format: JSON, expected: "[]\n"},
+		{format: NDJSON, expected: ""},
+		{format: CSV, expected: "title,url,visits,pinned,date_added,tags,meta_info\n"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.format, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := Render[testRecord](&buf, Options{Format: tc.format}, nil, nil)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, buf.String())
+		})
+	}
+}
+
+func TestRenderTemplate(t *testing.T) {
+	testCases := []struct {
+		name     string
+		opts     Options
+		expected string
+	}{
+		{
+			name:     "Fields",
+			opts:     Options{Format: Template, Template: "{{.Title}} {{.URL}}"},
+			expected: "Example, \"quoted\" | piped https://example.com/?a=1&b=2\nMulti\tline\nTitle https://example.org\n",
+		},
+		{
+			name:     "Implied by --template",
+			opts:     Options{Format: Table, Template: `{{.URL}} {{.DateAdded.Format "2006-01-02"}}`},
+			expected: "https://example.com/?a=1&b=2 2024-01-02\nhttps://example.org 0001-01-01\n",
+		},
+		{
+			name:     "Functions",
+			opts:     Options{Template: `{{json .Title}} {{join "+" .Tags}} {{.DateAdded}}` + "\n"},
+			expected: "\"Example, \\\"quoted\\\" | piped\" a+b 2024-01-02T03:04:05Z\n\"Multi\\tline\\nTitle\"  \n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := Render(&buf, tc.opts, testRecords(), nil)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, buf.String())
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	testCases := []struct {
+		name     string
+		opts     Options
+		expected string
+	}{
+		{
+			name:     "Unknown format",
+			opts:     Options{Format: "yaml"},
+			expected: `invalid output format "yaml", expected one of table, json, ndjson, csv, tsv, markdown, template`,
+		},
+		{
+			name:     "Template without --template",
+			opts:     Options{Format: Template},
+			expected: "--output template requires --template",
+		},
+		{
+			name:     "Invalid template",
+			opts:     Options{Template: "{{.Title"},
+			expected: "invalid template: template: output:1: unclosed action",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.EqualError(t, tc.opts.Validate(), tc.expected)
+		})
+	}
+}
+
+func TestSchema(t *testing.T) {
+	data, err := json.MarshalIndent(Schema[testRecord]("Test records"), "", "  ")
+	require.NoError(t, err)
+
+	cupaloy.SnapshotT(t, string(data))
+}
+
+func TestSort(t *testing.T) {
+	type item struct {
+		name  string
+		value int
+	}
+	items := func() []item {
+		return []item{{"a", 2}, {"none", 0}, {"b", 1}, {"c", 2}, {"also none", 0}}
+	}
+	compare := func(a, b item) int { return a.value - b.value }
+	missing := func(i item) bool { return i.value == 0 }
+	names := func(items []item) []string {
+		var names []string
+		for _, i := range items {
+			names = append(names, i.name)
+		}
+		return names
+	}
+
+	sorted := items()
+	Sort(sorted, false, compare, missing)
+	assert.Equal(t, []string{"b", "a", "c", "none", "also none"}, names(sorted), "Should keep ties in order and missing values last")
+
+	sorted = items()
+	Sort(sorted, true, compare, missing)
+	assert.Equal(t, []string{"c", "a", "b", "none", "also none"}, names(sorted), "Should keep missing values last when reversed")
+
+	sorted = items()
+	Sort(sorted, true, func(a, b item) int { return 0 }, nil)
+	assert.Equal(t, []string{"also none", "c", "b", "none", "a"}, names(sorted), "Should reverse the original order when everything ties")
+}
+
+func TestSortOptionsValidate(t *testing.T) {
+	sorts := []string{SortRelevance, SortTitle}
+
+	assert.NoError(t, SortOptions{By: SortTitle}.Validate(sorts))
+	assert.EqualError(t, SortOptions{By: SortFolder}.Validate(sorts), `invalid sort "folder", expected one of relevance, title`)
+}
@@ -0,0 +1,16 @@
+package output
+
+import (
+	"os"
+	"testing"
+
+	"github.com/charmbracelet/log"
+)
+
+func TestMain(m *testing.M) {
+	log.SetLevel(log.DebugLevel)
+
+	code := m.Run()
+
+	os.Exit(code)
+}
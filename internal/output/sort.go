@@ -0,0 +1,86 @@
+package output
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+// Orders a list can be sorted in, each list supports the ones that apply to
+// its items
+const (
+	// SortRelevance orders search results by their fzf score, best first,
+	// keeping fzf's order for equal scores. Without a search term everything
+	// scores the same, so lists keep the order they were loaded in.
+	SortRelevance = "relevance"
+	SortTitle     = "title"
+	SortURL       = "url"
+	// SortDateAdded orders by when an item was added, newest first
+	SortDateAdded = "date-added"
+	// SortLastUsed orders by when an item was last used, most recent first
+	SortLastUsed = "last-used"
+	SortFolder   = "folder"
+	SortBrowser  = "browser"
+	// SortDate orders by an item's date, newest first, for lists whose
+	// items have different kinds of dates
+	SortDate = "date"
+	// SortSource orders by where an item came from
+	SortSource = "source"
+)
+
+// SortOptions selects the order of a list
+type SortOptions struct {
+	By      string
+	Reverse bool
+}
+
+// AddSortFlags adds the --sort and --reverse flags, sorts lists the values
+// --sort accepts
+func AddSortFlags(flags *pflag.FlagSet, opts *SortOptions, sorts []string) {
+	flags.StringVar(&opts.By, "sort", SortRelevance, "Sort results by: "+strings.Join(sorts, ", ")+". Dates sort newest first and text A to Z")
+
+	flags.BoolVar(&opts.Reverse, "reverse", false, "Reverse the sort order")
+}
+
+// Validate checks that the order is one of sorts
+func (o SortOptions) Validate(sorts []string) error {
+	if !slices.Contains(sorts, o.By) {
+		return fmt.Errorf("invalid sort %q, expected one of %s", o.By, strings.Join(sorts, ", "))
+	}
+	return nil
+}
+
+// Sort orders items stably with compare, then reverses them for reverse.
+// missing reports the items that lack the value being sorted on (e.g. a
+// bookmark that was never used), those stay at the end even when reversed.
+// missing may be nil.
+func Sort[T any](items []T, reverse bool, compare func(a, b T) int, missing func(T) bool) {
+	if missing == nil {
+		missing = func(T) bool { return false }
+	}
+
+	slices.SortStableFunc(items, func(a, b T) int {
+		switch aMissing, bMissing := missing(a), missing(b); {
+		case aMissing && !bMissing:
+			return 1
+		case !aMissing && bMissing:
+			return -1
+		}
+		return compare(a, b)
+	})
+
+	if reverse {
+		sorted := slices.IndexFunc(items, missing)
+		if sorted < 0 {
+			sorted = len(items)
+		}
+		slices.Reverse(items[:sorted])
+	}
+}
+
+// CompareText compares strings for sorting, ignoring case
+func CompareText(a, b string) int {
+	return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+}
@@ -0,0 +1,255 @@
+package pick
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	fzflib "github.com/junegunn/fzf/src"
+	"github.com/malleatus/tamjaweb/internal/execrunner"
+	"github.com/malleatus/tamjaweb/internal/item"
+	"github.com/malleatus/tamjaweb/internal/search"
+)
+
+// Things that can be done with the picked URLs
+const (
+	// ActionOpen opens the URLs in the default browser
+	ActionOpen = "open"
+	// ActionCopy copies the URLs to the clipboard with an OSC 52 escape
+	// sequence, which works over ssh and in most terminals
+	ActionCopy = "copy"
+	// ActionPrint writes the URLs to stdout
+	ActionPrint = "print"
+)
+
+// Actions lists the valid values of Options.Action
+var Actions = []string{ActionOpen, ActionCopy, ActionPrint}
+
+// ActionKeys are the fzf keys that accept the selection with an action,
+// enter uses Options.Action
+var ActionKeys = map[string]string{
+	"ctrl-o": ActionOpen,
+	"ctrl-y": ActionCopy,
+	"alt-p":  ActionPrint,
+}
+
+type Options struct {
+	search.LoadOptions
+	// Action is done with the selection when it is accepted with enter
+	Action string
+	// Query is the initial search term
+	Query string
+}
+
+// Validate checks the options before anything is loaded
+func (o *Options) Validate() error {
+	if err := o.LoadOptions.Validate(); err != nil {
+		return err
+	}
+
+	if !slices.Contains(Actions, o.Action) {
+		return fmt.Errorf("invalid action %q, expected one of %s", o.Action, strings.Join(Actions, ", "))
+	}
+
+	return nil
+}
+
+// RunFzf runs fzf, tests replace it so they don't need a terminal
+var RunFzf = fzflib.Run
+
+// Pick shows items in fzf's interactive finder and returns the ones that
+// were selected, with the action to do with them. Nothing is returned when
+// the finder is closed without a selection.
+func Pick(items []item.Item, opts Options) (string, []item.Item, error) {
+	if len(items) == 0 {
+		return "", nil, nil
+	}
+
+	options, err := fzflib.ParseOptions(
+		true, // respect FZF_DEFAULT_OPTS for colors, layout, etc.
+		fzfArgs(opts),
+	)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to build fzf options: %w", err)
+	}
+
+	// buffered, so nothing is left blocked when fzf is closed early
+	inputChan := make(chan string, len(items))
+	for i, entry := range items {
+		inputChan <- fzfLine(i, entry)
+	}
+	close(inputChan)
+
+	outputChan := make(chan string)
+	options.Input = inputChan
+	options.Output = outputChan
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	var lines []string
+	go func() {
+		defer wg.Done()
+		for line := range outputChan {
+			lines = append(lines, line)
+		}
+	}()
+
+	code, err := RunFzf(options)
+	close(outputChan)
+	wg.Wait()
+
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to run fzf: %w", err)
+	}
+	if code != fzflib.ExitOk {
+		return "", nil, nil
+	}
+
+	action, picked := parseSelection(lines, items, opts.Action)
+	return action, picked, nil
+}
+
+// fzfArgs returns fzf's options for picking, see fzfLine for the fields of
+// each line
+func fzfArgs(opts Options) []string {
+	var keys, help []string
+	for _, action := range Actions {
+		for key, keyAction := range ActionKeys {
+			if keyAction == action {
+				keys = append(keys, key)
+				help = append(help, key+": "+action)
+			}
+		}
+	}
+
+	args := []string{
+		"--multi",
+		"--delimiter", "\t",
+		"--with-nth", "2..4",
+		"--expect", strings.Join(keys, ","),
+		"--header", fmt.Sprintf("enter: %s, %s, tab: select", opts.Action, strings.Join(help, ", ")),
+		"--preview", "printf '%b' {5}",
+		"--preview-window", "right,50%,wrap",
+		"--prompt", "pick> ",
+	}
+	if opts.Query != "" {
+		args = append(args, "--query", opts.Query)
+	}
+
+	return args
+}
+
+// previewEscaper escapes a preview for printf's %b, tabs would split the
+// preview into fields
+var previewEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, "\t", " ")
+
+// fieldCleaner keeps the displayed fields to a single field and line
+var fieldCleaner = strings.NewReplacer("\t", " ", "\r\n", " ", "\n", " ")
+
+// fzfLine formats an item for fzf as its index, source, title, URL and
+// escaped preview, separated by tabs
+func fzfLine(index int, entry item.Item) string {
+	return strings.Join([]string{
+		strconv.Itoa(index),
+		entry.Source,
+		fieldCleaner.Replace(entry.Title),
+		fieldCleaner.Replace(entry.URL),
+		previewEscaper.Replace(Preview(entry)),
+	}, "\t")
+}
+
+// Preview formats the title and URL of item followed by its other non empty
+// fields, it is shown next to the list for the highlighted item
+func Preview(i item.Item) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s\n%s\n\n", i.Title, i.URL)
+
+	browser := i.Browser
+	if i.Profile != "" {
+		browser = fmt.Sprintf("%s (%s)", i.Browser, i.Profile)
+	}
+
+	details := []item.Detail{
+		{Label: "Browser", Value: browser},
+		{Label: "Folder", Value: i.Folder},
+		{Label: "Description", Value: i.Description},
+		{Label: item.DateNames[i.Source], Value: formatDate(i.Date)},
+	}
+	for _, detail := range append(details, i.Details...) {
+		if detail.Value != "" {
+			fmt.Fprintf(&sb, "%s: %s\n", detail.Label, detail.Value)
+		}
+	}
+
+	return sb.String()
+}
+
+// formatDate formats t for display, leaving dates that were never set empty
+func formatDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format("2006-01-02 15:04:05")
+}
+
+// parseSelection reads fzf's output: the key that accepted the selection
+// (empty for enter), followed by the selected lines
+func parseSelection(lines []string, items []item.Item, defaultAction string) (string, []item.Item) {
+	if len(lines) == 0 {
+		return "", nil
+	}
+
+	action, ok := ActionKeys[lines[0]]
+	if !ok {
+		action = defaultAction
+	}
+
+	var picked []item.Item
+	for _, line := range lines[1:] {
+		index, _, _ := strings.Cut(line, "\t")
+		if i, err := strconv.Atoi(index); err == nil && i >= 0 && i < len(items) {
+			picked = append(picked, items[i])
+		}
+	}
+
+	return action, picked
+}
+
+// OpenCommand returns the command that opens a URL in the default browser on
+// goos
+func OpenCommand(goos string, url string) (string, []string) {
+	switch goos {
+	case "darwin":
+		return "open", []string{url}
+	case "windows":
+		return "rundll32", []string{"url.dll,FileProtocolHandler", url}
+	}
+	return "xdg-open", []string{url}
+}
+
+// Open opens url in the default browser
+func Open(goos string, url string) error {
+	name, args := OpenCommand(goos, url)
+	if _, err := execrunner.Default.Run(name, args...); err != nil {
+		return fmt.Errorf("failed to open %s with %s: %w", url, name, err)
+	}
+	return nil
+}
+
+// Copy writes the OSC 52 escape sequence that makes the terminal copy text to
+// the clipboard. Inside tmux the sequence is wrapped so tmux passes it on.
+func Copy(w io.Writer, text string, tmux bool) error {
+	sequence := "\x1b]52;c;" + base64.StdEncoding.EncodeToString([]byte(text)) + "\a"
+	if tmux {
+		sequence = "\x1bPtmux;\x1b" + sequence + "\x1b\\"
+	}
+
+	_, err := io.WriteString(w, sequence)
+	return err
+}
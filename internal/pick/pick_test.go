@@ -0,0 +1,236 @@
+package pick
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bradleyjkemp/cupaloy/v2"
+	fzflib "github.com/junegunn/fzf/src"
+	"github.com/malleatus/tamjaweb/internal/bookmarks"
+	"github.com/malleatus/tamjaweb/internal/browser"
+	"github.com/malleatus/tamjaweb/internal/execrunner"
+	"github.com/malleatus/tamjaweb/internal/github"
+	"github.com/malleatus/tamjaweb/internal/history"
+	"github.com/malleatus/tamjaweb/internal/item"
+	"github.com/malleatus/tamjaweb/internal/tabs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockRunner struct {
+	Command string
+	Args    []string
+	Err     error
+}
+
+func (m *mockRunner) Run(name string, args ...string) ([]byte, error) {
+	m.Command = name
+	m.Args = args
+	return nil, m.Err
+}
+
+func testItems() []item.Item {
+	return []item.Item{
+		{Source: item.SourceBookmarks, Title: "Example", URL: "https://example.com"},
+		{Source: item.SourceStars, Title: "junegunn/fzf", URL: "https://github.com/junegunn/fzf", Description: `A command-line fuzzy finder, C:\fzf`},
+		{Source: item.SourceTabs, Title: "Multi\tline\ntitle", URL: "https://tabs.example.com"},
+	}
+}
+
+// fakeFzf replaces RunFzf with a finder that reads the input and picks the
+// lines at indices, accepting them with key
+func fakeFzf(t *testing.T, key string, indices []int, code int) *[]string {
+	original := RunFzf
+	t.Cleanup(func() { RunFzf = original })
+
+	var input []string
+	RunFzf = func(options *fzflib.Options) (int, error) {
+		for line := range options.Input {
+			input = append(input, line)
+		}
+		if code != fzflib.ExitOk {
+			return code, nil
+		}
+
+		options.Output <- key
+		for _, i := range indices {
+			options.Output <- input[i]
+		}
+		return code, nil
+	}
+
+	return &input
+}
+
+func TestPick(t *testing.T) {
+	testCases := []struct {
+		name           string
+		key            string
+		indices        []int
+		expectedAction string
+		expectedTitles []string
+	}{
+		{
+			name:           "Enter uses the default action",
+			key:            "",
+			indices:        []int{0},
+			expectedAction: ActionPrint,
+			expectedTitles: []string{"Example"},
+		},
+		{
+			name:           "Open key",
+			key:            "ctrl-o",
+			indices:        []int{1, 2},
+			expectedAction: ActionOpen,
+			expectedTitles: []string{"junegunn/fzf", "Multi\tline\ntitle"},
+		},
+		{
+			name:           "Copy key",
+			key:            "ctrl-y",
+			indices:        []int{2},
+			expectedAction: ActionCopy,
+			expectedTitles: []string{"Multi\tline\ntitle"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fakeFzf(t, tc.key, tc.indices, fzflib.ExitOk)
+
+			action, picked, err := Pick(testItems(), Options{Action: ActionPrint})
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.expectedAction, action)
+			var titles []string
+			for _, entry := range picked {
+				titles = append(titles, entry.Title)
+			}
+			assert.Equal(t, tc.expectedTitles, titles)
+		})
+	}
+}
+
+func TestPickInput(t *testing.T) {
+	input := fakeFzf(t, "", nil, fzflib.ExitOk)
+
+	_, _, err := Pick(testItems(), Options{Action: ActionOpen})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"0\tbookmarks\tExample\thttps://example.com\tExample\\nhttps://example.com\\n\\n",
+		"1\tstars\tjunegunn/fzf\thttps://github.com/junegunn/fzf\tjunegunn/fzf\\nhttps://github.com/junegunn/fzf\\n\\nDescription: A command-line fuzzy finder, C:\\\\fzf\\n",
+		"2\ttabs\tMulti line title\thttps://tabs.example.com\tMulti line\\ntitle\\nhttps://tabs.example.com\\n\\n",
+	}, *input, "Should keep every item to one line of five fields")
+}
+
+func TestPickAborted(t *testing.T) {
+	fakeFzf(t, "", nil, fzflib.ExitInterrupt)
+
+	action, picked, err := Pick(testItems(), Options{Action: ActionOpen})
+	require.NoError(t, err)
+	assert.Empty(t, action)
+	assert.Empty(t, picked)
+}
+
+func TestFzfArgs(t *testing.T) {
+	args := strings.Join(fzfArgs(Options{Action: ActionCopy, Query: "git"}), " ")
+
+	assert.Contains(t, args, "--expect ctrl-o,ctrl-y,alt-p")
+	assert.Contains(t, args, "--header enter: copy, ctrl-o: open, ctrl-y: copy, alt-p: print, tab: select")
+	assert.Contains(t, args, "--query git")
+}
+
+func TestValidate(t *testing.T) {
+	opts := Options{Action: ActionOpen}
+	opts.Sources = []string{item.SourceBookmarks, item.SourceStars}
+	opts.User = "octocat"
+	assert.NoError(t, opts.Validate())
+
+	opts.Sources = []string{"bookmarks", "reading-list"}
+	assert.EqualError(t, opts.Validate(), `invalid source "reading-list", expected one of bookmarks, stars, tabs, history`)
+
+	opts.Sources = []string{item.SourceStars}
+	opts.User = ""
+	assert.EqualError(t, opts.Validate(), "--sources stars requires --user")
+
+	opts = Options{Action: "share"}
+	assert.EqualError(t, opts.Validate(), `invalid action "share", expected one of open, copy, print`)
+}
+
+func TestPreview(t *testing.T) {
+	fixedTime := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	var items []item.Item
+	items = append(items, bookmarks.Items(bookmarks.Entries(map[string][]browser.Bookmark{
+		"Chrome": {
+			{Title: "Example", URL: "https://example.com", FolderPath: "Bookmark Bar/Work", DateAdded: fixedTime, Profile: "Work"},
+			{Title: "Never Used", URL: "https://unused.example.com"},
+		},
+	}))...)
+	items = append(items, github.Items([]github.Star{
+		{Stargazer: "octocat", Repo: "junegunn/fzf", Description: "A command-line fuzzy finder", URL: "https://github.com/junegunn/fzf", StarredAt: "2024-01-02", Language: "Go"},
+	})...)
+	items = append(items, tabs.Items(map[string][]browser.Tab{
+		"Firefox": {
+			{Title: "Open Tab", URL: "https://open.example.com", Window: 2, LastActive: fixedTime, Profile: "default-release"},
+			{Title: "Closed Tab", URL: "https://closed.example.com", Closed: true},
+		},
+		"Chrome": {
+			{Title: "Chrome Tab", URL: "https://chrome.example.com", Window: 1, Active: true},
+		},
+	})...)
+	items = append(items, history.Items(history.Entries(map[string][]browser.HistoryEntry{
+		"Chrome": {
+			{Title: "Visited", URL: "https://visited.example.com", VisitCount: 3, LastVisit: fixedTime, Profile: "Default"},
+		},
+	}))...)
+
+	var previews []string
+	for _, entry := range items {
+		previews = append(previews, Preview(entry))
+	}
+	cupaloy.SnapshotT(t, previews)
+}
+
+func TestOpen(t *testing.T) {
+	original := execrunner.Default
+	t.Cleanup(func() { execrunner.Default = original })
+
+	testCases := []struct {
+		goos     string
+		command  string
+		expected []string
+	}{
+		{goos: "linux", command: "xdg-open", expected: []string{"https://example.com"}},
+		{goos: "freebsd", command: "xdg-open", expected: []string{"https://example.com"}},
+		{goos: "darwin", command: "open", expected: []string{"https://example.com"}},
+		{goos: "windows", command: "rundll32", expected: []string{"url.dll,FileProtocolHandler", "https://example.com"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.goos, func(t *testing.T) {
+			runner := &mockRunner{}
+			execrunner.Default = runner
+
+			require.NoError(t, Open(tc.goos, "https://example.com"))
+			assert.Equal(t, tc.command, runner.Command)
+			assert.Equal(t, tc.expected, runner.Args)
+		})
+	}
+
+	execrunner.Default = &mockRunner{Err: errors.New("exit status 3")}
+	assert.EqualError(t, Open("linux", "https://example.com"), "failed to open https://example.com with xdg-open: exit status 3")
+}
+
+func TestCopy(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Copy(&buf, "https://example.com", false))
+	assert.Equal(t, "\x1b]52;c;aHR0cHM6Ly9leGFtcGxlLmNvbQ==\a", buf.String())
+
+	buf.Reset()
+	require.NoError(t, Copy(&buf, "https://example.com", true))
+	assert.Equal(t, "\x1bPtmux;\x1b\x1b]52;c;aHR0cHM6Ly9leGFtcGxlLmNvbQ==\a\x1b\\", buf.String())
+}
@@ -0,0 +1,254 @@
+// Package query parses the search terms of the search commands. A search is
+// free text, which is fuzzy matched with fzf, mixed with field filters:
+//
+//	github folder:Work -host:gist.github.com added:>2024-01-01 "pull request"
+//
+// Filters are a field name, a colon and a value, quoted when it contains
+// spaces (folder:"Reading List"). A leading - negates a filter or a word.
+// Quoted text outside a filter is matched as an exact phrase.
+package query
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Field is a field of T that can be filtered on, see Contains, Equals, Host
+// and Date
+type Field[T any] struct {
+	// match reports whether item matches a filter on the field
+	match func(item T, f filter) bool
+	// parse checks a filter's value when the query is parsed
+	parse func(f *filter, now time.Time) error
+	// op is the comparison of filters that don't have one, e.g. < for before:
+	op string
+}
+
+// Fields are the fields of T that can be filtered on, by name
+type Fields[T any] map[string]Field[T]
+
+// Query is a parsed search
+type Query[T any] struct {
+	// Text is the free text of the search, in fzf's extended search syntax
+	Text    string
+	filters []filter
+	fields  Fields[T]
+}
+
+// filter is a field:value term of a search
+type filter struct {
+	field   string
+	value   string
+	negated bool
+	// op, start and end are only set for date fields
+	op    string
+	start time.Time
+	end   time.Time
+}
+
+// Parse parses search, taking the filters on fields out of it. now is used
+// for relative dates such as added:<30d.
+func Parse[T any](search string, fields Fields[T], now time.Time) (Query[T], error) {
+	q := Query[T]{fields: fields}
+
+	var text []string
+	for _, token := range tokenize(search) {
+		negated := false
+		if len(token) > 1 && token[0] == '-' {
+			negated = true
+			token = token[1:]
+		}
+
+		if name, value, ok := strings.Cut(token, ":"); ok && !strings.HasPrefix(token, `"`) {
+			if field, ok := fields[strings.ToLower(name)]; ok {
+				f := filter{field: strings.ToLower(name), value: unquote(value), negated: negated, op: field.op}
+				if f.value == "" {
+					return Query[T]{}, fmt.Errorf("%s: missing value", token)
+				}
+				if field.parse != nil {
+					if err := field.parse(&f, now); err != nil {
+						return Query[T]{}, fmt.Errorf("%s: %w", token, err)
+					}
+				}
+				q.filters = append(q.filters, f)
+				continue
+			}
+		}
+
+		text = append(text, fzfTerm(token, negated))
+	}
+	q.Text = strings.Join(text, " ")
+
+	return q, nil
+}
+
+// Match reports whether item matches every filter of the query. The free
+// text is left to fzf.
+func (q Query[T]) Match(item T) bool {
+	for _, f := range q.filters {
+		if q.fields[f.field].match(item, f) == f.negated {
+			return false
+		}
+	}
+	return true
+}
+
+// Filter returns the items that match every filter of the query
+func (q Query[T]) Filter(items []T) []T {
+	if len(q.filters) == 0 {
+		return items
+	}
+
+	var filtered []T
+	for _, item := range items {
+		if q.Match(item) {
+			filtered = append(filtered, item)
+		}
+	}
+	return filtered
+}
+
+// tokenize splits a search on spaces, keeping quoted text together
+func tokenize(search string) []string {
+	var tokens []string
+	var token strings.Builder
+	quoted := false
+
+	for _, r := range search {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			token.WriteRune(r)
+		case unicode.IsSpace(r) && !quoted:
+			if token.Len() > 0 {
+				tokens = append(tokens, token.String())
+				token.Reset()
+			}
+		default:
+			token.WriteRune(r)
+		}
+	}
+	if token.Len() > 0 {
+		tokens = append(tokens, token.String())
+	}
+
+	return tokens
+}
+
+// unquote removes the quotes around a value, an unterminated quote runs to
+// the end of the search
+func unquote(value string) string {
+	if strings.HasPrefix(value, `"`) {
+		value = strings.TrimSuffix(value[1:], `"`)
+	}
+	return value
+}
+
+// fzfTerm converts a word or quoted phrase of the free text to fzf's syntax.
+// Words are passed through so that fzf's own operators keep working.
+func fzfTerm(token string, negated bool) string {
+	if strings.HasPrefix(token, `"`) {
+		phrase := strings.ReplaceAll(unquote(token), " ", `\ `)
+		if negated {
+			return "!" + phrase
+		}
+		return "'" + phrase
+	}
+
+	if negated {
+		return "!" + token
+	}
+	return token
+}
+
+// Contains is a text field matched by filters that it contains, ignoring case
+func Contains[T any](text func(T) string) Field[T] {
+	return Field[T]{match: func(item T, f filter) bool {
+		return strings.Contains(strings.ToLower(text(item)), strings.ToLower(f.value))
+	}}
+}
+
+// Equals is a text field matched by filters equal to it, ignoring case
+func Equals[T any](text func(T) string) Field[T] {
+	return Field[T]{match: func(item T, f filter) bool {
+		return strings.EqualFold(text(item), f.value)
+	}}
+}
+
+// Host is the host of a URL field. A filter matches the host and its
+// subdomains, so host:github.com matches gist.github.com but not
+// notgithub.com.
+func Host[T any](rawURL func(T) string) Field[T] {
+	return Field[T]{match: func(item T, f filter) bool {
+		u, err := url.Parse(rawURL(item))
+		if err != nil {
+			return false
+		}
+		host := strings.ToLower(u.Hostname())
+		value := strings.ToLower(f.value)
+		return host == value || strings.HasSuffix(host, "."+value)
+	}}
+}
+
+// Date is a date field. Filters compare it with >, >=, <, <= or = (the
+// default), see ParseTimeRange for the values they accept. Items without
+// the date never match.
+func Date[T any](date func(T) time.Time) Field[T] {
+	return Field[T]{
+		match: func(item T, f filter) bool {
+			t := date(item)
+			if t.IsZero() {
+				return false
+			}
+
+			switch f.op {
+			case ">":
+				return !t.Before(f.end)
+			case ">=":
+				return !t.Before(f.start)
+			case "<":
+				return t.Before(f.start)
+			case "<=":
+				return t.Before(f.end)
+			}
+			return !t.Before(f.start) && t.Before(f.end)
+		},
+		parse: func(f *filter, now time.Time) error {
+			for _, op := range []string{">=", "<=", ">", "<", "="} {
+				if value, ok := strings.CutPrefix(f.value, op); ok {
+					f.op, f.value = op, value
+					break
+				}
+			}
+
+			start, end, err := ParseTimeRange(f.value, now)
+			if err != nil {
+				return err
+			}
+			// a point in time has no range to be in, so it means since then
+			if start.Equal(end) && (f.op == "" || f.op == "=") {
+				f.op = ">="
+			}
+			f.start, f.end = start, end
+
+			return nil
+		},
+	}
+}
+
+// Before is a date field matched by filters before the date, e.g. before:2024
+func Before[T any](date func(T) time.Time) Field[T] {
+	field := Date(date)
+	field.op = "<"
+	return field
+}
+
+// After is a date field matched by filters after the date, e.g. after:2024-06
+func After[T any](date func(T) time.Time) Field[T] {
+	field := Date(date)
+	field.op = ">"
+	return field
+}
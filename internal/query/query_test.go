@@ -0,0 +1,111 @@
+package query
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testItem struct {
+	Title   string
+	URL     string
+	Folder  string
+	Browser string
+	Added   time.Time
+}
+
+var testFields = Fields[testItem]{
+	"title":   Contains(func(i testItem) string { return i.Title }),
+	"folder":  Contains(func(i testItem) string { return i.Folder }),
+	"host":    Host(func(i testItem) string { return i.URL }),
+	"browser": Equals(func(i testItem) string { return i.Browser }),
+	"added":   Date(func(i testItem) time.Time { return i.Added }),
+	"before":  Before(func(i testItem) time.Time { return i.Added }),
+	"after":   After(func(i testItem) time.Time { return i.Added }),
+}
+
+func testItems() []testItem {
+	return []testItem{
+		{Title: "Pull requests", URL: "https://github.com/pulls", Folder: "Bookmark Bar/Work", Browser: "Chrome", Added: time.Date(2024, 3, 1, 12, 0, 0, 0, time.Local)},
+		{Title: "Gists", URL: "https://gist.github.com", Folder: "Bookmark Bar/Work/Code", Browser: "Brave", Added: time.Date(2023, 12, 31, 12, 0, 0, 0, time.Local)},
+		{Title: "Not GitHub", URL: "https://notgithub.com", Folder: "Other Bookmarks", Browser: "Chrome", Added: time.Date(2024, 6, 14, 12, 0, 0, 0, time.Local)},
+		{Title: "Reading List", URL: "https://example.com/read", Folder: "Reading List", Browser: "Firefox"},
+	}
+}
+
+func TestParseText(t *testing.T) {
+	testCases := []struct {
+		search   string
+		expected string
+	}{
+		{search: "github pulls", expected: "github pulls"},
+		{search: "  spaced   out  ", expected: "spaced out"},
+		{search: `"pull request" review`, expected: `'pull\ request review`},
+		{search: `-"pull request" -draft`, expected: `!pull\ request !draft`},
+		{search: "^fzf syntax$ | kept", expected: "^fzf syntax$ | kept"},
+		{search: "https://github.com folder:Work", expected: "https://github.com"},
+		{search: `folder:"Reading List" unknown:field`, expected: "unknown:field"},
+		{search: `"unterminated phrase`, expected: `'unterminated\ phrase`},
+		{search: "-", expected: "-"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.search, func(t *testing.T) {
+			q, err := Parse(tc.search, testFields, fixedNow)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, q.Text)
+		})
+	}
+}
+
+func TestFilter(t *testing.T) {
+	testCases := []struct {
+		search   string
+		expected []string
+	}{
+		{search: "", expected: []string{"Pull requests", "Gists", "Not GitHub", "Reading List"}},
+		{search: "folder:work", expected: []string{"Pull requests", "Gists"}},
+		{search: `folder:"reading list"`, expected: []string{"Reading List"}},
+		{search: "host:github.com", expected: []string{"Pull requests", "Gists"}},
+		{search: "-host:gist.github.com", expected: []string{"Pull requests", "Not GitHub", "Reading List"}},
+		{search: "browser:chrome", expected: []string{"Pull requests", "Not GitHub"}},
+		{search: "BROWSER:brave", expected: []string{"Gists"}},
+		{search: "added:2024", expected: []string{"Pull requests", "Not GitHub"}},
+		{search: "added:2024-03", expected: []string{"Pull requests"}},
+		{search: "added:>2024-03-01", expected: []string{"Not GitHub"}},
+		{search: "added:>=2024-03-01", expected: []string{"Pull requests", "Not GitHub"}},
+		{search: "added:<2024", expected: []string{"Gists"}},
+		{search: "added:<=2024-03-01", expected: []string{"Pull requests", "Gists"}},
+		{search: "added:7d", expected: []string{"Not GitHub"}},
+		{search: "-added:2024", expected: []string{"Gists", "Reading List"}},
+		{search: "before:2024", expected: []string{"Gists"}},
+		{search: "after:2023", expected: []string{"Pull requests", "Not GitHub"}},
+		{search: "folder:work added:2024 pulls", expected: []string{"Pull requests"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.search, func(t *testing.T) {
+			q, err := Parse(tc.search, testFields, fixedNow)
+			require.NoError(t, err)
+
+			var titles []string
+			for _, item := range q.Filter(testItems()) {
+				titles = append(titles, item.Title)
+			}
+			assert.Equal(t, tc.expected, titles)
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	_, err := Parse("folder:", testFields, fixedNow)
+	assert.EqualError(t, err, "folder:: missing value")
+
+	_, err = Parse("github added:>lastweek", testFields, fixedNow)
+	assert.EqualError(t, err, `added:>lastweek: invalid time "lastweek", expected a date (2006-01-02), an RFC 3339 time or a duration (36h, 7d, 2w)`)
+
+	_, err = Parse("added:>", testFields, fixedNow)
+	assert.EqualError(t, err, "added:>: missing date")
+}
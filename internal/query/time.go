@@ -0,0 +1,66 @@
+package query
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// relativeTimePattern matches durations like "36h", "7d" or "2w"
+var relativeTimePattern = regexp.MustCompile(`^(\d+)([dw])$`)
+
+// ParseTime parses a --since/--until value: a date (2024-01-02), a RFC 3339
+// time, or a duration before now ("36h", "7d", "2w"). An empty value is the
+// zero time.
+func ParseTime(value string, now time.Time) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	if match := relativeTimePattern.FindStringSubmatch(value); match != nil {
+		n, _ := strconv.Atoi(match[1])
+		if match[2] == "w" {
+			n *= 7
+		}
+		return now.AddDate(0, 0, -n), nil
+	}
+	if duration, err := time.ParseDuration(value); err == nil {
+		return now.Add(-duration), nil
+	}
+
+	return time.Time{}, fmt.Errorf("invalid time %q, expected a date (2006-01-02), an RFC 3339 time or a duration (36h, 7d, 2w)", value)
+}
+
+// ParseTimeRange parses the value of a date filter into the period it
+// covers, from start up to but excluding end. Years (2024) and months
+// (2024-06) cover the whole year or month and dates the whole day. Anything
+// else ParseTime accepts is a point in time, with start and end equal.
+func ParseTimeRange(value string, now time.Time) (start, end time.Time, err error) {
+	if t, err := time.ParseInLocation("2006", value, time.Local); err == nil {
+		return t, t.AddDate(1, 0, 0), nil
+	}
+	if t, err := time.ParseInLocation("2006-01", value, time.Local); err == nil {
+		return t, t.AddDate(0, 1, 0), nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return t, t.AddDate(0, 0, 1), nil
+	}
+
+	if value == "" {
+		return time.Time{}, time.Time{}, fmt.Errorf("missing date")
+	}
+
+	t, err := ParseTime(value, now)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	return t, t, nil
+}
@@ -0,0 +1,130 @@
+package query
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var fixedNow = time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+
+func TestParseTime(t *testing.T) {
+	testCases := []struct {
+		name        string
+		value       string
+		expected    time.Time
+		expectError bool
+	}{
+		{
+			name:     "Empty",
+			value:    "",
+			expected: time.Time{},
+		},
+		{
+			name:     "Date",
+			value:    "2024-01-02",
+			expected: time.Date(2024, 1, 2, 0, 0, 0, 0, time.Local),
+		},
+		{
+			name:     "RFC 3339",
+			value:    "2024-01-02T03:04:05Z",
+			expected: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		},
+		{
+			name:     "Hours",
+			value:    "36h",
+			expected: fixedNow.Add(-36 * time.Hour),
+		},
+		{
+			name:     "Days",
+			value:    "7d",
+			expected: fixedNow.AddDate(0, 0, -7),
+		},
+		{
+			name:     "Weeks",
+			value:    "2w",
+			expected: fixedNow.AddDate(0, 0, -14),
+		},
+		{
+			name:        "Invalid",
+			value:       "last tuesday",
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			parsed, err := ParseTime(tc.value, fixedNow)
+
+			if tc.expectError {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.True(t, tc.expected.Equal(parsed), "expected %s, got %s", tc.expected, parsed)
+		})
+	}
+}
+
+func TestParseTimeRange(t *testing.T) {
+	testCases := []struct {
+		name        string
+		value       string
+		start       time.Time
+		end         time.Time
+		expectError bool
+	}{
+		{
+			name:  "Year",
+			value: "2024",
+			start: time.Date(2024, 1, 1, 0, 0, 0, 0, time.Local),
+			end:   time.Date(2025, 1, 1, 0, 0, 0, 0, time.Local),
+		},
+		{
+			name:  "Month",
+			value: "2024-02",
+			start: time.Date(2024, 2, 1, 0, 0, 0, 0, time.Local),
+			end:   time.Date(2024, 3, 1, 0, 0, 0, 0, time.Local),
+		},
+		{
+			name:  "Date",
+			value: "2024-02-29",
+			start: time.Date(2024, 2, 29, 0, 0, 0, 0, time.Local),
+			end:   time.Date(2024, 3, 1, 0, 0, 0, 0, time.Local),
+		},
+		{
+			name:  "Duration",
+			value: "7d",
+			start: fixedNow.AddDate(0, 0, -7),
+			end:   fixedNow.AddDate(0, 0, -7),
+		},
+		{
+			name:        "Empty",
+			value:       "",
+			expectError: true,
+		},
+		{
+			name:        "Invalid",
+			value:       "yesterday",
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			start, end, err := ParseTimeRange(tc.value, fixedNow)
+
+			if tc.expectError {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.True(t, tc.start.Equal(start), "expected start %s, got %s", tc.start, start)
+			assert.True(t, tc.end.Equal(end), "expected end %s, got %s", tc.end, end)
+		})
+	}
+}
@@ -0,0 +1,117 @@
+// Package search loads items from every source for the commands that work
+// across them, tamjaweb search and tamjaweb pick
+package search
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/malleatus/tamjaweb/internal/bookmarks"
+	"github.com/malleatus/tamjaweb/internal/browser"
+	"github.com/malleatus/tamjaweb/internal/github"
+	"github.com/malleatus/tamjaweb/internal/history"
+	"github.com/malleatus/tamjaweb/internal/item"
+	"github.com/malleatus/tamjaweb/internal/output"
+	"github.com/malleatus/tamjaweb/internal/query"
+	"github.com/malleatus/tamjaweb/internal/tabs"
+)
+
+// LoadOptions selects what Load loads
+type LoadOptions struct {
+	Profile string
+	Strict  bool
+	// Sources are loaded in item.Sources order. Empty loads every source,
+	// skipping stars without User.
+	Sources []string
+	// FetchOptions are for the stars of the GitHub user FetchOptions.User
+	github.FetchOptions
+	// Since limits history to pages visited since then, parsed with
+	// query.ParseTime
+	Since string
+}
+
+// Validate checks the options before anything is loaded
+func (o *LoadOptions) Validate() error {
+	for _, source := range o.Sources {
+		if !slices.Contains(item.Sources, source) {
+			return fmt.Errorf("invalid source %q, expected one of %s", source, strings.Join(item.Sources, ", "))
+		}
+	}
+
+	if slices.Contains(o.Sources, item.SourceStars) && o.User == "" {
+		return fmt.Errorf("--sources %s requires --user", item.SourceStars)
+	}
+
+	return nil
+}
+
+type Options struct {
+	LoadOptions
+	Sort   output.SortOptions
+	Search item.SearchOptions
+	Output output.Options
+}
+
+// Load loads the items of every source in opts, reporting the browsers that
+// failed to w, see browser.ErrorReporter. Outdated stars are refreshed in the
+// background, callers wait for the returned Refresh once they are done with
+// the items.
+func Load(ctx context.Context, w io.Writer, opts LoadOptions, now time.Time) ([]item.Item, *github.Refresh, error) {
+	var items []item.Item
+	var refresh *github.Refresh
+	reporter := browser.ErrorReporter{W: w, Strict: opts.Strict}
+
+	for _, source := range item.Sources {
+		if len(opts.Sources) > 0 && !slices.Contains(opts.Sources, source) {
+			continue
+		}
+
+		switch source {
+		case item.SourceBookmarks:
+			allBookmarks, err := browser.GetAllBookmarks(ctx, opts.Profile)
+			if err := reporter.Report(err, "bookmarks"); err != nil {
+				return nil, refresh, err
+			}
+			items = append(items, bookmarks.Items(bookmarks.Entries(allBookmarks))...)
+
+		case item.SourceStars:
+			if opts.User == "" {
+				log.Debug("Skipping stars without --user")
+				continue
+			}
+
+			stars, starsRefresh, err := github.GetAllStars(opts.FetchOptions)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to get stars: %w", err)
+			}
+			refresh = starsRefresh
+			items = append(items, github.Items(stars)...)
+
+		case item.SourceTabs:
+			allTabs, err := browser.GetAllTabs(ctx, opts.Profile)
+			if err := reporter.Report(err, "tabs"); err != nil {
+				return nil, refresh, err
+			}
+			items = append(items, tabs.Items(tabs.FilterOpenTabs(allTabs))...)
+
+		case item.SourceHistory:
+			since, err := query.ParseTime(opts.Since, now)
+			if err != nil {
+				return nil, refresh, fmt.Errorf("--since: %w", err)
+			}
+
+			allHistory, err := browser.GetAllHistory(ctx, opts.Profile, browser.HistoryWindow{Since: since})
+			if err := reporter.Report(err, "history"); err != nil {
+				return nil, refresh, err
+			}
+			items = append(items, history.Items(history.Entries(allHistory))...)
+		}
+	}
+
+	return items, refresh, reporter.Err()
+}
@@ -0,0 +1,106 @@
+package search
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/malleatus/tamjaweb/internal/browser"
+	"github.com/malleatus/tamjaweb/internal/item"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeBrowser is a Browser with fixed bookmarks, or an error
+type fakeBrowser struct {
+	name      string
+	bookmarks []browser.Bookmark
+	err       error
+}
+
+func (f *fakeBrowser) Name() string {
+	return f.name
+}
+
+func (f *fakeBrowser) GetBookmarks(ctx context.Context, profile string) ([]browser.Bookmark, error) {
+	return f.bookmarks, f.err
+}
+
+func withRegisteredBrowsers(t *testing.T, browsers ...browser.Browser) {
+	t.Helper()
+
+	original := browser.RegisteredBrowsers
+	browser.RegisteredBrowsers = browsers
+	t.Cleanup(func() { browser.RegisteredBrowsers = original })
+}
+
+func TestLoad(t *testing.T) {
+	withRegisteredBrowsers(t,
+		&fakeBrowser{name: "Firefox", bookmarks: []browser.Bookmark{{Title: "Firefox Bookmark", URL: "https://firefox.example.com"}}},
+		&fakeBrowser{name: "Chrome", bookmarks: []browser.Bookmark{{Title: "Chrome Bookmark", URL: "https://chrome.example.com"}}},
+		&fakeBrowser{name: "Broken", err: errors.New("corrupt bookmarks file")},
+	)
+
+	testCases := []struct {
+		name           string
+		opts           LoadOptions
+		expectedTitles []string
+		expectedErr    string
+	}{
+		{
+			name:           "Skips stars without a user",
+			opts:           LoadOptions{Profile: "Default"},
+			expectedTitles: []string{"Chrome Bookmark", "Firefox Bookmark"},
+		},
+		{
+			name:           "Only the selected sources",
+			opts:           LoadOptions{Profile: "Default", Sources: []string{item.SourceTabs}},
+			expectedTitles: nil,
+		},
+		{
+			name:           "Strict returns the partial results",
+			opts:           LoadOptions{Profile: "Default", Strict: true, Sources: []string{item.SourceBookmarks}},
+			expectedTitles: []string{"Chrome Bookmark", "Firefox Bookmark"},
+			expectedErr:    "failed to load bookmarks from 1 browser profile(s)",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var stderr bytes.Buffer
+			items, _, err := Load(context.Background(), &stderr, tc.opts, time.Now())
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+			} else {
+				require.NoError(t, err)
+			}
+
+			var titles []string
+			for _, entry := range items {
+				assert.Equal(t, item.SourceBookmarks, entry.Source)
+				titles = append(titles, entry.Title)
+			}
+			assert.Equal(t, tc.expectedTitles, titles)
+
+			if len(tc.opts.Sources) == 0 || tc.opts.Sources[0] == item.SourceBookmarks {
+				assert.Contains(t, stderr.String(), "Warning: failed to load bookmarks from 1 browser profile(s)")
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	opts := LoadOptions{Sources: []string{item.SourceBookmarks, item.SourceHistory}}
+	assert.NoError(t, opts.Validate())
+
+	opts.Sources = []string{"reading-list"}
+	assert.EqualError(t, opts.Validate(), `invalid source "reading-list", expected one of bookmarks, stars, tabs, history`)
+
+	opts.Sources = []string{item.SourceStars}
+	assert.EqualError(t, opts.Validate(), "--sources stars requires --user")
+
+	opts.User = "octocat"
+	assert.NoError(t, opts.Validate())
+}
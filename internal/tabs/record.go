@@ -0,0 +1,48 @@
+package tabs
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/malleatus/tamjaweb/internal/browser"
+	"github.com/malleatus/tamjaweb/internal/output"
+)
+
+// Record is a tab as written by the machine readable output formats. The
+// json names are part of tamjaweb's interface and must not change.
+type Record struct {
+	Browser    string      `json:"browser" doc:"Browser the tab was read from"`
+	Profile    string      `json:"profile" doc:"Name of the browser profile"`
+	ID         string      `json:"id" doc:"The browser's id for the tab, only unique within a session"`
+	Window     int         `json:"window" doc:"Window the tab is in, numbered from 1, 0 for closed tabs"`
+	Index      int         `json:"index" doc:"Position of the tab in its window"`
+	Title      string      `json:"title"`
+	URL        string      `json:"url"`
+	Pinned     bool        `json:"pinned"`
+	Active     bool        `json:"active" doc:"Whether the tab is the selected tab of its window"`
+	LastActive output.Date `json:"last_active" doc:"When the tab was last selected, or closed, null when not recorded"`
+	Closed     bool        `json:"closed" doc:"Whether the tab was recently closed and can be restored"`
+}
+
+// Records converts tabs to Records, ordered by browser name
+func Records(tabs map[string][]browser.Tab) []Record {
+	var records []Record
+	for _, browserName := range slices.Sorted(maps.Keys(tabs)) {
+		for _, tab := range tabs[browserName] {
+			records = append(records, Record{
+				Browser:    browserName,
+				Profile:    tab.Profile,
+				ID:         tab.ID,
+				Window:     tab.Window,
+				Index:      tab.Index,
+				Title:      tab.Title,
+				URL:        tab.URL,
+				Pinned:     tab.Pinned,
+				Active:     tab.Active,
+				LastActive: output.NewDate(tab.LastActive),
+				Closed:     tab.Closed,
+			})
+		}
+	}
+	return records
+}
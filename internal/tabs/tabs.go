@@ -0,0 +1,195 @@
+package tabs
+
+import (
+	"bytes"
+	"maps"
+	"slices"
+	"strconv"
+
+	"github.com/charmbracelet/log"
+	"github.com/malleatus/tamjaweb/internal/browser"
+	"github.com/malleatus/tamjaweb/internal/item"
+	"github.com/malleatus/tamjaweb/internal/output"
+	"github.com/olekukonko/tablewriter"
+)
+
+type Options struct {
+	Profile string
+	Strict  bool
+	// Closed includes the recently closed tabs
+	Closed bool
+	// Live reads the tabs of a running browser through the DevTools protocol
+	// instead of the session files
+	Live bool
+	// CDPURL is where the browser serves the DevTools protocol
+	CDPURL string
+	Search item.SearchOptions
+	Output output.Options
+}
+
+// FindTabs returns the tabs query refers to, either the tab with that exact
+// id or the tabs matching query as a search term, best match first
+func FindTabs(tabs []browser.Tab, query string) []browser.Tab {
+	for _, tab := range tabs {
+		if tab.ID == query {
+			return []browser.Tab{tab}
+		}
+	}
+
+	if query == "" {
+		return nil
+	}
+
+	return FilterTabsByTerm(map[string][]browser.Tab{"": tabs}, query, item.SearchOptions{})[""]
+}
+
+// MatchFields are the fields of a tab search terms are matched against, and
+// the fields --in accepts for tabs
+var MatchFields = []string{item.FieldTitle, item.FieldURL}
+
+// FilterTabsByTerm filters tabs using fzf's filter functionality, matching
+// the same way as bookmarks are matched
+// Returns a map of browser names to matching tabs
+func FilterTabsByTerm(tabs map[string][]browser.Tab, term string, opts item.SearchOptions) map[string][]browser.Tab {
+	if term == "" {
+		return tabs
+	}
+
+	type tabEntry struct {
+		browserName string
+		tab         browser.Tab
+	}
+
+	// in the same order as Items, so ties are ranked the same on every run
+	var entries []tabEntry
+	for _, browserName := range slices.Sorted(maps.Keys(tabs)) {
+		for _, tab := range tabs[browserName] {
+			entries = append(entries, tabEntry{
+				browserName: browserName,
+				tab:         tab,
+			})
+		}
+	}
+
+	matches, err := item.Match(Items(tabs), term, opts, MatchFields)
+	if err != nil {
+		log.Error("Failed to filter tabs", "error", err)
+		return make(map[string][]browser.Tab)
+	}
+
+	filteredTabs := make(map[string][]browser.Tab)
+	for _, match := range matches {
+		entry := entries[match.Index]
+		filteredTabs[entry.browserName] = append(filteredTabs[entry.browserName], entry.tab)
+	}
+
+	return filteredTabs
+}
+
+// Items adapts tabs for searches across sources, ordered by browser name
+func Items(tabs map[string][]browser.Tab) []item.Item {
+	var items []item.Item
+	for _, browserName := range slices.Sorted(maps.Keys(tabs)) {
+		for _, tab := range tabs[browserName] {
+			items = append(items, tabItem(browserName, tab))
+		}
+	}
+	return items
+}
+
+func tabItem(browserName string, tab browser.Tab) item.Item {
+	return item.Item{
+		Source:  item.SourceTabs,
+		Browser: browserName,
+		Profile: tab.Profile,
+		Title:   tab.Title,
+		URL:     tab.URL,
+		Date:    tab.LastActive,
+		Details: []item.Detail{
+			{Label: "Window", Value: formatWindow(tab)},
+		},
+	}
+}
+
+// FilterOpenTabs drops the recently closed tabs
+func FilterOpenTabs(tabs map[string][]browser.Tab) map[string][]browser.Tab {
+	filteredTabs := make(map[string][]browser.Tab)
+
+	for browserName, tabList := range tabs {
+		for _, tab := range tabList {
+			if !tab.Closed {
+				filteredTabs[browserName] = append(filteredTabs[browserName], tab)
+			}
+		}
+	}
+
+	return filteredTabs
+}
+
+// prints the tabs in a tabular format, ordered by browser name
+func PrintTabs(tabs map[string][]browser.Tab) (string, error) {
+	if len(tabs) == 0 {
+		return "No tabs found", nil
+	}
+
+	var buf bytes.Buffer
+	table := tablewriter.NewWriter(&buf)
+
+	table.SetHeader([]string{"Browser", "Profile", "Window", "Title", "URL", "Pinned", "Last Active"})
+	table.SetAutoWrapText(true)
+	table.SetColumnAlignment([]int{
+		tablewriter.ALIGN_LEFT,
+		tablewriter.ALIGN_LEFT,
+		tablewriter.ALIGN_LEFT,
+		tablewriter.ALIGN_LEFT,
+		tablewriter.ALIGN_LEFT,
+		tablewriter.ALIGN_LEFT,
+		tablewriter.ALIGN_LEFT,
+	})
+	table.SetColWidth(50)
+
+	for _, browserName := range slices.Sorted(maps.Keys(tabs)) {
+		for _, tab := range tabs[browserName] {
+			table.Append([]string{
+				browserName,
+				tab.Profile,
+				formatWindow(tab),
+				tab.Title,
+				tab.URL,
+				formatPinned(tab.Pinned),
+				formatLastActive(tab),
+			})
+		}
+	}
+
+	table.Render()
+
+	return buf.String(), nil
+}
+
+// formatWindow shows the tab's window, marking the selected tab of each window
+func formatWindow(tab browser.Tab) string {
+	if tab.Closed {
+		return "closed"
+	}
+
+	window := strconv.Itoa(tab.Window)
+	if tab.Active {
+		window += " *"
+	}
+	return window
+}
+
+func formatPinned(pinned bool) string {
+	if pinned {
+		return "yes"
+	}
+	return ""
+}
+
+func formatLastActive(tab browser.Tab) string {
+	if tab.LastActive.IsZero() {
+		return ""
+	}
+	return tab.LastActive.Format("2006-01-02 15:04:05")
+}
@@ -0,0 +1,197 @@
+package tabs
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bradleyjkemp/cupaloy/v2"
+	"github.com/malleatus/tamjaweb/internal/browser"
+	"github.com/malleatus/tamjaweb/internal/item"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrintTabs(t *testing.T) {
+	fixedTime := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	tabs := map[string][]browser.Tab{
+		"TestBrowser": {
+			{
+				Window:  1,
+				Index:   0,
+				Title:   "GitHub",
+				URL:     "https://github.com/",
+				Pinned:  true,
+				Profile: "Work",
+			},
+			{
+				Window:     1,
+				Index:      1,
+				Title:      "Example",
+				URL:        "https://example.com/",
+				Active:     true,
+				LastActive: fixedTime,
+				Profile:    "Work",
+			},
+			{
+				Title:      "Closed Example",
+				URL:        "https://closed.example.com/",
+				LastActive: fixedTime,
+				Closed:     true,
+				Profile:    "Work",
+			},
+		},
+	}
+
+	output, err := PrintTabs(tabs)
+	require.NoError(t, err)
+
+	cupaloy.SnapshotT(t, output)
+}
+
+func TestPrintTabsEmpty(t *testing.T) {
+	output, err := PrintTabs(map[string][]browser.Tab{})
+	require.NoError(t, err)
+
+	assert.Equal(t, "No tabs found", output)
+}
+
+func TestPrintTabsOrdersBrowsers(t *testing.T) {
+	tabs := map[string][]browser.Tab{
+		"Firefox":  {{Title: "Firefox Tab", URL: "https://firefox.example.com/"}},
+		"Chrome":   {{Title: "Chrome Tab", URL: "https://chrome.example.com/"}},
+		"Brave":    {{Title: "Brave Tab", URL: "https://brave.example.com/"}},
+		"Chromium": {{Title: "Chromium Tab", URL: "https://chromium.example.com/"}},
+	}
+
+	// map order changes between runs, so a few runs would catch it
+	for range 5 {
+		output, err := PrintTabs(tabs)
+		require.NoError(t, err)
+
+		brave, chrome := strings.Index(output, "Brave Tab"), strings.Index(output, "Chrome Tab")
+		chromium, firefox := strings.Index(output, "Chromium Tab"), strings.Index(output, "Firefox Tab")
+		assert.True(t, brave < chrome && chrome < chromium && chromium < firefox, "Should print browsers by name:\n%s", output)
+	}
+}
+
+func TestFilterTabsByTerm(t *testing.T) {
+	tabs := map[string][]browser.Tab{
+		"TestBrowser1": {
+			{Title: "GitHub", URL: "https://github.com/"},
+			{Title: "Example", URL: "https://example.com/"},
+		},
+		"TestBrowser2": {
+			{Title: "Pull requests", URL: "https://github.com/pulls", Closed: true},
+		},
+	}
+
+	testCases := []struct {
+		name     string
+		term     string
+		expected map[string][]string
+	}{
+		{
+			name: "Empty term returns everything",
+			term: "",
+			expected: map[string][]string{
+				"TestBrowser1": {"GitHub", "Example"},
+				"TestBrowser2": {"Pull requests"},
+			},
+		},
+		{
+			name: "Match by URL across browsers",
+			term: "github.com",
+			expected: map[string][]string{
+				"TestBrowser1": {"GitHub"},
+				"TestBrowser2": {"Pull requests"},
+			},
+		},
+		{
+			name: "Match by title",
+			term: "example",
+			expected: map[string][]string{
+				"TestBrowser1": {"Example"},
+			},
+		},
+		{
+			name:     "No matches",
+			term:     "zzzzzz",
+			expected: map[string][]string{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			filtered := FilterTabsByTerm(tabs, tc.term, item.SearchOptions{})
+
+			titles := map[string][]string{}
+			for browserName, tabList := range filtered {
+				for _, tab := range tabList {
+					titles[browserName] = append(titles[browserName], tab.Title)
+				}
+			}
+			assert.Equal(t, tc.expected, titles)
+		})
+	}
+}
+
+func TestFindTabs(t *testing.T) {
+	tabs := []browser.Tab{
+		{ID: "A1", Title: "GitHub", URL: "https://github.com/"},
+		{ID: "B2", Title: "GitHub Pulls", URL: "https://github.com/pulls"},
+		{ID: "C3", Title: "Example", URL: "https://example.com/"},
+	}
+
+	testCases := []struct {
+		name     string
+		query    string
+		expected []string
+	}{
+		{
+			name:     "Exact id",
+			query:    "B2",
+			expected: []string{"B2"},
+		},
+		{
+			name:     "Search term",
+			query:    "pulls",
+			expected: []string{"B2"},
+		},
+		{
+			name:     "Several matches",
+			query:    "github",
+			expected: []string{"A1", "B2"},
+		},
+		{
+			name:  "Empty query",
+			query: "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var ids []string
+			for _, tab := range FindTabs(tabs, tc.query) {
+				ids = append(ids, tab.ID)
+			}
+			assert.Equal(t, tc.expected, ids)
+		})
+	}
+}
+
+func TestFilterOpenTabs(t *testing.T) {
+	tabs := map[string][]browser.Tab{
+		"TestBrowser1": {
+			{Title: "Open"},
+			{Title: "Closed", Closed: true},
+		},
+		"TestBrowser2": {
+			{Title: "Closed", Closed: true},
+		},
+	}
+
+	assert.Equal(t, map[string][]browser.Tab{
+		"TestBrowser1": {{Title: "Open"}},
+	}, FilterOpenTabs(tabs))
+}